@@ -1,29 +1,92 @@
 "use client";
 
-import { useState } from "react";
-import { useAPIKeys, useRevokeAPIKey, useOrganizations } from "@/lib/hooks";
+import { Fragment, useMemo, useState } from "react";
+import {
+  useAPIKeys,
+  useUpdateAPIKey,
+  useRevokeAPIKey,
+  useRevokeAPIKeys,
+  useReevaluateAPIKeys,
+  useOrganizations,
+} from "@/lib/hooks";
+import type { APIKey } from "@/lib/api-client";
 import { formatDate } from "@/lib/utils";
 
+const USAGE_DAYS = 30;
+const STALE_DAYS = 90;
+
+function totalRequests(key: APIKey): number {
+  return (key.dailyUsage ?? []).reduce((sum, day) => sum + Number(day.requests ?? 0), 0);
+}
+
+// One entry per line or comma
+function splitList(value: string): string[] {
+  return value.split(/[\n,]/).map((v) => v.trim()).filter(Boolean);
+}
+
+interface RestrictionsDraft {
+  id: string;
+  cidrs: string;
+  origins: string;
+}
+
 export default function APIKeysPage() {
   const { data: orgs = [] } = useOrganizations();
   const [selectedOrg, setSelectedOrg] = useState("");
-  const { data: keys = [], isLoading } = useAPIKeys(selectedOrg || null);
+  const [staleOnly, setStaleOnly] = useState(false);
+  const unusedSince = useMemo(
+    () => (staleOnly ? new Date(Date.now() - STALE_DAYS * 24 * 3600 * 1000).toISOString() : undefined),
+    [staleOnly],
+  );
+  const { data: keys = [], isLoading } = useAPIKeys(selectedOrg || null, {
+    usage_days: USAGE_DAYS,
+    unused_since: unusedSince,
+  });
+  const [draft, setDraft] = useState<RestrictionsDraft | null>(null);
+  const updateKey = useUpdateAPIKey();
   const revokeKey = useRevokeAPIKey();
+  const revokeKeys = useRevokeAPIKeys();
+  const reevaluateKeys = useReevaluateAPIKeys();
 
   return (
     <div>
       <div className="flex items-center justify-between mb-6">
         <h2 className="text-2xl font-bold">API Keys</h2>
-        <select
-          value={selectedOrg}
-          onChange={(e) => setSelectedOrg(e.target.value)}
-          className="px-4 py-2 border border-gray-300 rounded-lg text-sm focus:outline-none focus:ring-2 focus:ring-blue-500"
-        >
-          <option value="">Select organization...</option>
-          {orgs.map((org) => (
-            <option key={org.id} value={org.id}>{org.name}</option>
-          ))}
-        </select>
+        <div className="flex items-center gap-3">
+          <label className="flex items-center gap-2 text-sm text-gray-600">
+            <input type="checkbox" checked={staleOnly} onChange={(e) => setStaleOnly(e.target.checked)} />
+            Unused for {STALE_DAYS} days
+          </label>
+          {selectedOrg && staleOnly && keys.length > 0 && (
+            <button
+              onClick={() => revokeKeys.mutate({ orgId: selectedOrg, ids: keys.map((k) => k.id!) })}
+              disabled={revokeKeys.isPending}
+              className="px-4 py-2 bg-red-600 text-white rounded-lg text-sm font-medium hover:bg-red-700 disabled:opacity-50"
+            >
+              Revoke {keys.length} stale
+            </button>
+          )}
+          {selectedOrg && (
+            <button
+              onClick={() => reevaluateKeys.mutate(selectedOrg)}
+              disabled={reevaluateKeys.isPending}
+              title="Disable keys whose owner no longer has the permissions behind their scopes"
+              className="px-4 py-2 border border-gray-300 rounded-lg text-sm font-medium hover:bg-gray-50 disabled:opacity-50"
+            >
+              Re-evaluate scopes
+            </button>
+          )}
+          <select
+            value={selectedOrg}
+            onChange={(e) => setSelectedOrg(e.target.value)}
+            className="px-4 py-2 border border-gray-300 rounded-lg text-sm focus:outline-none focus:ring-2 focus:ring-blue-500"
+          >
+            <option value="">Select organization...</option>
+            {orgs.map((org) => (
+              <option key={org.id} value={org.id}>{org.name}</option>
+            ))}
+          </select>
+        </div>
       </div>
 
       <div className="bg-white rounded-lg border border-gray-200 overflow-hidden">
@@ -34,20 +97,45 @@ export default function APIKeysPage() {
               <th className="text-left px-6 py-3 font-medium text-gray-500">Prefix</th>
               <th className="text-left px-6 py-3 font-medium text-gray-500">Env</th>
               <th className="text-left px-6 py-3 font-medium text-gray-500">Last Used</th>
+              <th className="text-left px-6 py-3 font-medium text-gray-500">Requests ({USAGE_DAYS}d)</th>
               <th className="text-left px-6 py-3 font-medium text-gray-500">Actions</th>
             </tr>
           </thead>
           <tbody className="divide-y divide-gray-100">
             {!selectedOrg ? (
-              <tr><td colSpan={5} className="px-6 py-8 text-center text-gray-400">Select an organization</td></tr>
+              <tr><td colSpan={6} className="px-6 py-8 text-center text-gray-400">Select an organization</td></tr>
             ) : isLoading ? (
-              <tr><td colSpan={5} className="px-6 py-8 text-center text-gray-400">Loading...</td></tr>
+              <tr><td colSpan={6} className="px-6 py-8 text-center text-gray-400">Loading...</td></tr>
             ) : keys.length === 0 ? (
-              <tr><td colSpan={5} className="px-6 py-8 text-center text-gray-400">No API keys</td></tr>
+              <tr><td colSpan={6} className="px-6 py-8 text-center text-gray-400">No API keys</td></tr>
             ) : (
               keys.map((key) => (
-                <tr key={key.id} className="hover:bg-gray-50">
-                  <td className="px-6 py-4 font-medium">{key.name}</td>
+                <Fragment key={key.id}>
+                <tr className="hover:bg-gray-50">
+                  <td className="px-6 py-4 font-medium">
+                    {key.name}
+                    {key.revokeAt && (
+                      <span className="ml-2 inline-flex items-center px-2 py-0.5 rounded text-xs font-medium bg-yellow-100 text-yellow-800">
+                        rotated, revoked {formatDate(key.revokeAt)}
+                      </span>
+                    )}
+                    {key.disabledAt && (
+                      <span
+                        title={key.disabledReason}
+                        className="ml-2 inline-flex items-center px-2 py-0.5 rounded text-xs font-medium bg-red-100 text-red-800"
+                      >
+                        disabled
+                      </span>
+                    )}
+                    {((key.allowedCidrs?.length ?? 0) > 0 || (key.allowedOrigins?.length ?? 0) > 0) && (
+                      <span
+                        title={[...(key.allowedCidrs ?? []), ...(key.allowedOrigins ?? [])].join("\n")}
+                        className="ml-2 inline-flex items-center px-2 py-0.5 rounded text-xs font-medium bg-blue-100 text-blue-800"
+                      >
+                        restricted
+                      </span>
+                    )}
+                  </td>
                   <td className="px-6 py-4 font-mono text-xs">{key.prefix}...</td>
                   <td className="px-6 py-4">
                     <span className={`inline-flex items-center px-2 py-0.5 rounded text-xs font-medium ${
@@ -56,18 +144,85 @@ export default function APIKeysPage() {
                       {key.environment?.replace("API_KEY_ENVIRONMENT_", "").toLowerCase()}
                     </span>
                   </td>
-                  <td className="px-6 py-4 text-gray-500">{formatDate(key.lastUsedAt)}</td>
+                  <td className="px-6 py-4 text-gray-500">
+                    {formatDate(key.lastUsedAt)}
+                    {key.lastUsedIp && <span className="block text-xs font-mono">{key.lastUsedIp}</span>}
+                  </td>
+                  <td className="px-6 py-4 text-gray-500">{totalRequests(key).toLocaleString()}</td>
                   <td className="px-6 py-4">
                     {!key.revokedAt && (
-                      <button
-                        onClick={() => revokeKey.mutate(key.id!)}
-                        className="text-red-600 hover:text-red-800 text-sm font-medium"
-                      >
-                        Revoke
-                      </button>
+                      <div className="flex gap-3">
+                        <button
+                          onClick={() => setDraft({
+                            id: key.id!,
+                            cidrs: (key.allowedCidrs ?? []).join("\n"),
+                            origins: (key.allowedOrigins ?? []).join("\n"),
+                          })}
+                          className="text-blue-600 hover:text-blue-800 text-sm font-medium"
+                        >
+                          Restrictions
+                        </button>
+                        <button
+                          onClick={() => revokeKey.mutate(key.id!)}
+                          className="text-red-600 hover:text-red-800 text-sm font-medium"
+                        >
+                          Revoke
+                        </button>
+                      </div>
                     )}
                   </td>
                 </tr>
+                {draft?.id === key.id && (
+                  <tr className="bg-gray-50">
+                    <td colSpan={6} className="px-6 py-4">
+                      <div className="grid grid-cols-2 gap-4">
+                        <label className="text-xs text-gray-500">
+                          Allowed IPs / CIDRs (empty = any)
+                          <textarea
+                            value={draft.cidrs}
+                            onChange={(e) => setDraft({ ...draft, cidrs: e.target.value })}
+                            placeholder="203.0.113.0/24"
+                            rows={3}
+                            className="mt-1 w-full px-3 py-2 border border-gray-300 rounded-lg font-mono text-xs"
+                          />
+                        </label>
+                        <label className="text-xs text-gray-500">
+                          Allowed origins (empty = any)
+                          <textarea
+                            value={draft.origins}
+                            onChange={(e) => setDraft({ ...draft, origins: e.target.value })}
+                            placeholder="https://app.example.com"
+                            rows={3}
+                            className="mt-1 w-full px-3 py-2 border border-gray-300 rounded-lg font-mono text-xs"
+                          />
+                        </label>
+                      </div>
+                      {updateKey.error && <p className="mt-2 text-xs text-red-600">{updateKey.error.message}</p>}
+                      <div className="mt-3 flex gap-2">
+                        <button
+                          onClick={() => updateKey.mutate(
+                            {
+                              id: draft.id,
+                              update: { allowed_cidrs: splitList(draft.cidrs), allowed_origins: splitList(draft.origins) },
+                            },
+                            { onSuccess: () => setDraft(null) },
+                          )}
+                          disabled={updateKey.isPending}
+                          className="px-3 py-1.5 bg-blue-600 text-white rounded-lg text-sm font-medium hover:bg-blue-700 disabled:opacity-50"
+                        >
+                          Save
+                        </button>
+                        <button
+                          onClick={() => { setDraft(null); updateKey.reset(); }}
+                          className="px-3 py-1.5 border border-gray-300 rounded-lg text-sm font-medium hover:bg-gray-50"
+                        >
+                          Cancel
+                        </button>
+                      </div>
+                    </td>
+                  </tr>
+                )}
+                </Fragment>
               ))
             )}
           </tbody>
@@ -150,4 +150,34 @@ describe("API functions", () => {
     expect(url).toContain("action=user.registered");
     expect(url).toContain("page_size=10");
   });
+
+  it("listAPIKeys passes usage and staleness filters", async () => {
+    mockFetch.mockResolvedValueOnce({
+      ok: true,
+      text: async () => JSON.stringify({ keys: [] }),
+    });
+
+    const { listAPIKeys } = await import("../api-client");
+    await listAPIKeys("org-1", { usage_days: 30, unused_since: "2026-01-01T00:00:00Z" });
+
+    const url = mockFetch.mock.calls[0][0] as string;
+    expect(url).toContain("organization_id=org-1");
+    expect(url).toContain("usage_days=30");
+    expect(url).toContain("unused_since=2026-01-01T00%3A00%3A00Z");
+  });
+
+  it("updateAPIKey sends only the fields to change", async () => {
+    mockFetch.mockResolvedValueOnce({
+      ok: true,
+      text: async () => JSON.stringify({ id: "key-1" }),
+    });
+
+    const { updateAPIKey } = await import("../api-client");
+    await updateAPIKey("key-1", { allowed_cidrs: ["203.0.113.0/24"] });
+
+    const [url, opts] = mockFetch.mock.calls[0];
+    expect(url).toBe("http://test:8080/v1/api-keys/key-1");
+    expect(opts.method).toBe("PATCH");
+    expect(JSON.parse(opts.body)).toEqual({ allowed_cidrs: ["203.0.113.0/24"] });
+  });
 });
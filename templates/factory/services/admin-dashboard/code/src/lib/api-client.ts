@@ -16,6 +16,8 @@ export type Invitation = components["schemas"]["customersInvitation"];
 export type SessionInfo = components["schemas"]["customersSessionInfo"];
 export type EntitlementInfo = components["schemas"]["customersEntitlementInfo"];
 export type Permission = components["schemas"]["customersPermission"];
+export type RevokeAPIKeysResponse = components["schemas"]["customersRevokeAPIKeysResponse"];
+export type ReevaluateAPIKeysResponse = components["schemas"]["customersReevaluateAPIKeysResponse"];
 
 // Auth types
 export type AuthenticateResponse = components["schemas"]["customersAuthenticateResponse"];
@@ -222,18 +224,57 @@ export function revokeInvitation(id: string) {
 // API Keys API
 // ============================================================================
 
-export function listAPIKeys(orgId: string, pageSize = 100) {
+export interface APIKeyListOptions {
+  page_size?: number;
+  usage_days?: number;
+  unused_since?: string;
+}
+
+export function listAPIKeys(orgId: string, options: APIKeyListOptions = {}) {
+  const qs = new URLSearchParams();
+  qs.set("organization_id", orgId);
+  qs.set("page_size", String(options.page_size || 100));
+  if (options.usage_days) qs.set("usage_days", String(options.usage_days));
+  if (options.unused_since) qs.set("unused_since", options.unused_since);
   return request<{ keys?: APIKey[]; next_page_token?: string }>(
-    `/v1/api-keys?organization_id=${orgId}&page_size=${pageSize}`,
+    `/v1/api-keys?${qs}`,
   );
 }
 
+export interface APIKeyUpdate {
+  name?: string;
+  allowed_cidrs?: string[];
+  allowed_origins?: string[];
+}
+
+// Only the fields present are updated (the gateway derives the update mask).
+export function updateAPIKey(id: string, update: APIKeyUpdate) {
+  return request<APIKey>(`/v1/api-keys/${id}`, {
+    method: "PATCH",
+    body: JSON.stringify(update),
+  });
+}
+
 export function revokeAPIKey(id: string) {
   return request<Record<string, never>>(`/v1/api-keys/${id}`, {
     method: "DELETE",
   });
 }
 
+export function revokeAPIKeys(orgId: string, ids: string[]) {
+  return request<RevokeAPIKeysResponse>("/v1/api-keys:batchRevoke", {
+    method: "POST",
+    body: JSON.stringify({ organization_id: orgId, ids }),
+  });
+}
+
+export function reevaluateAPIKeys(orgId: string) {
+  return request<ReevaluateAPIKeysResponse>("/v1/api-keys:reevaluate", {
+    method: "POST",
+    body: JSON.stringify({ organization_id: orgId }),
+  });
+}
+
 // ============================================================================
 // Organizations API
 // ============================================================================
@@ -129,6 +129,54 @@ export interface paths {
         delete: operations["APIKeyService_RevokeAPIKey"];
         options?: never;
         head?: never;
+        patch: operations["APIKeyService_UpdateAPIKey"];
+        trace?: never;
+    };
+    "/v1/api-keys/{id}:rotate": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        post: operations["APIKeyService_RotateAPIKey"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/api-keys:batchRevoke": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        post: operations["APIKeyService_RevokeAPIKeys"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/api-keys:reevaluate": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        post: operations["APIKeyService_ReevaluateAPIKeys"];
+        delete?: never;
+        options?: never;
+        head?: never;
         patch?: never;
         trace?: never;
     };
@@ -148,6 +196,70 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/v1/audit-log/checkpoints": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["AuditService_ListAuditCheckpoints"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/audit-log/export": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["AuditService_ExportAuditLog"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/audit-log/verify": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["AuditService_VerifyAuditChain"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/audit-log/watch": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["AuditService_WatchAuditLog"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/v1/auth/.well-known/jwks.json": {
         parameters: {
             query?: never;
@@ -212,6 +324,22 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/v1/health": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["UserService_Health"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/v1/identity:resolve": {
         parameters: {
             query?: never;
@@ -568,6 +696,18 @@ export interface paths {
 export type webhooks = Record<string, never>;
 export interface components {
     schemas: {
+        APIKeyServiceRotateAPIKeyBody: {
+            /**
+             * Format: int64
+             * @description How long the old key keeps working; 0 means the default (24h). At most 30 days.
+             */
+            gracePeriodSeconds?: string;
+            /**
+             * Format: date-time
+             * @description Expiry of the new key; defaults to the old key's.
+             */
+            expiresAt?: string;
+        };
         AdminServiceImpersonateUserBody: Record<string, never>;
         AdminServiceOverrideEntitlementBody: {
             feature?: string;
@@ -583,6 +723,12 @@ export interface components {
             name?: string;
             description?: string;
         };
+        apiHttpBody: {
+            contentType?: string;
+            /** Format: byte */
+            data?: string;
+            extensions?: components["schemas"]["protobufAny"][];
+        };
         customersAPIKey: {
             id?: string;
             organizationId?: string;
@@ -599,6 +745,41 @@ export interface components {
             lastUsedAt?: string;
             /** Format: date-time */
             revokedAt?: string;
+            /** predecessor, for keys issued by RotateAPIKey */
+            rotatedFromId?: string;
+            /**
+             * end of the rotation grace period, when set
+             * Format: date-time
+             */
+            revokeAt?: string;
+            /**
+             * Format: date-time
+             * @description Set while the owner lacks a permission behind one of the scopes; the key
+             *     is rejected until re-evaluation finds the permissions back.
+             */
+            disabledAt?: string;
+            disabledReason?: string;
+            lastUsedIp?: string;
+            /** filled by ListAPIKeys when usage_days is set */
+            dailyUsage?: components["schemas"]["customersAPIKeyDailyUsage"][];
+            /**
+             * @description Optional restrictions; empty = any. CIDRs such as 203.0.113.0/24 (a bare
+             *     IP means that address only); origins such as https://app.example.com or
+             *     https://*.example.com, matched against the request's Origin or Referer.
+             */
+            allowedCidrs?: string[];
+            allowedOrigins?: string[];
+        };
+        /**
+         * @description Requests validated with a key on one day (UTC). Validation results are
+         *     cached by the sidecar, so this counts backend validations: a lower bound of
+         *     the requests made with the key.
+         */
+        customersAPIKeyDailyUsage: {
+            /** YYYY-MM-DD */
+            date?: string;
+            /** Format: int64 */
+            requests?: string;
         };
         /**
          * @default API_KEY_ENVIRONMENT_UNSPECIFIED
@@ -621,6 +802,29 @@ export interface components {
         customersAssignRoleResponse: {
             assignment?: components["schemas"]["customersRoleAssignment"];
         };
+        /** AuditChainBreak is the first event where the chain doesn't verify */
+        customersAuditChainBreak: {
+            /** Format: int64 */
+            chainSeq?: string;
+            /** Empty when the event is missing */
+            eventId?: string;
+            reason?: string;
+        };
+        /**
+         * @description AuditCheckpoint commits to an org's chain up to chain_seq. signature is a
+         *     JWT with org, seq and hash claims, verifiable with the JWKS
+         *     (/v1/auth/.well-known/jwks.json).
+         */
+        customersAuditCheckpoint: {
+            id?: string;
+            orgId?: string;
+            /** Format: int64 */
+            chainSeq?: string;
+            hash?: string;
+            signature?: string;
+            /** Format: date-time */
+            createdAt?: string;
+        };
         customersAuditEvent: {
             id?: string;
             actorId?: string;
@@ -635,7 +839,21 @@ export interface components {
             ipAddress?: string;
             /** Format: date-time */
             createdAt?: string;
+            /**
+             * Position in the org's hash chain; 0 for events recorded before chaining
+             * Format: int64
+             */
+            chainSeq?: string;
+            prevHash?: string;
+            /** sha256 of prev_hash, a newline and the event's canonical JSON */
+            hash?: string;
         };
+        /**
+         * - AUDIT_EXPORT_FORMAT_NDJSON: One JSON object per line
+         * @default AUDIT_EXPORT_FORMAT_UNSPECIFIED
+         * @enum {string}
+         */
+        customersAuditExportFormat: "AUDIT_EXPORT_FORMAT_UNSPECIFIED" | "AUDIT_EXPORT_FORMAT_NDJSON" | "AUDIT_EXPORT_FORMAT_CSV";
         customersAuthenticateRequest: {
             provider?: string;
             providerId?: string;
@@ -672,6 +890,8 @@ export interface components {
             environment?: components["schemas"]["customersAPIKeyEnvironment"];
             /** Format: date-time */
             expiresAt?: string;
+            allowedCidrs?: string[];
+            allowedOrigins?: string[];
         };
         customersCreateAPIKeyResponse: {
             key?: components["schemas"]["customersAPIKey"];
@@ -727,6 +947,21 @@ export interface components {
             organizations?: components["schemas"]["customersOrganization"][];
             roleAssignments?: components["schemas"]["customersRoleAssignment"][];
         };
+        /**
+         * @description HealthResponse reports "ok", or "degraded" while dependencies are down
+         *     (e.g. Vault unreachable at startup); the service keeps serving what it can.
+         */
+        customersHealthResponse: {
+            status?: string;
+            /** Components that are down, with their status; the reason is only logged */
+            degraded?: {
+                [key: string]: string;
+            };
+            /** Counters since startup, e.g. audit_events_dropped */
+            counters?: {
+                [key: string]: string;
+            };
+        };
         customersImpersonateUserResponse: {
             accessToken?: string;
             /** Format: int64 */
@@ -760,6 +995,9 @@ export interface components {
             sessions?: components["schemas"]["customersSessionInfo"][];
             nextPageToken?: string;
         };
+        customersListAuditCheckpointsResponse: {
+            checkpoints?: components["schemas"]["customersAuditCheckpoint"][];
+        };
         customersListInvitationsResponse: {
             invitations?: components["schemas"]["customersInvitation"][];
         };
@@ -787,6 +1025,8 @@ export interface components {
         };
         customersLogoutRequest: {
             refreshToken?: string;
+            /** @description Optional: the current access token, denylisted until it expires. */
+            accessToken?: string;
         };
         customersOrgMembership: {
             orgId?: string;
@@ -825,6 +1065,19 @@ export interface components {
             /** Format: int32 */
             totalCount?: number;
         };
+        customersReevaluateAPIKeysRequest: {
+            organizationId?: string;
+        };
+        customersReevaluateAPIKeysResponse: {
+            /** Format: int32 */
+            checked?: number;
+            /** owner lost a permission behind the scopes */
+            disabled?: components["schemas"]["customersAPIKey"][];
+            /** owner has the permissions again */
+            enabled?: components["schemas"]["customersAPIKey"][];
+            /** could not be re-evaluated; retried on the next run */
+            failedKeyIds?: string[];
+        };
         customersRefreshTokenRequest: {
             refreshToken?: string;
         };
@@ -855,6 +1108,14 @@ export interface components {
             roles?: string[];
             found?: boolean;
         };
+        customersRevokeAPIKeysRequest: {
+            organizationId?: string;
+            ids?: string[];
+        };
+        customersRevokeAPIKeysResponse: {
+            /** ids already revoked or not in the org are skipped */
+            revokedIds?: string[];
+        };
         customersRole: {
             id?: string;
             name?: string;
@@ -875,6 +1136,13 @@ export interface components {
             /** Format: date-time */
             assignedAt?: string;
         };
+        customersRotateAPIKeyResponse: {
+            /** the successor */
+            key?: components["schemas"]["customersAPIKey"];
+            plaintextKey?: string;
+            /** with revoke_at set */
+            previousKey?: components["schemas"]["customersAPIKey"];
+        };
         customersSearchUsersResponse: {
             users?: components["schemas"]["customersUser"][];
             nextPageToken?: string;
@@ -961,9 +1229,22 @@ export interface components {
          * @enum {string}
          */
         customersUserStatus: "USER_STATUS_UNSPECIFIED" | "USER_STATUS_ACTIVE" | "USER_STATUS_INACTIVE" | "USER_STATUS_SUSPENDED" | "USER_STATUS_DELETED";
+        customersVerifyAuditChainResponse: {
+            valid?: boolean;
+            /** Format: int64 */
+            checked?: string;
+            /** Format: int32 */
+            checkpointsChecked?: number;
+            firstBreak?: components["schemas"]["customersAuditChainBreak"];
+        };
         customersVersionResponse: {
             version?: string;
         };
+        customersWatchAuditLogResponse: {
+            event?: components["schemas"]["customersAuditEvent"];
+            /** Pass back in WatchAuditLogRequest to resume after this event */
+            resumeToken?: string;
+        };
         protobufAny: {
             "@type"?: string;
         } & {
@@ -1227,6 +1508,10 @@ export interface operations {
                 organizationId?: string;
                 pageSize?: number;
                 pageToken?: string;
+                /** @description Include daily usage for the last usage_days days; 0 = none. */
+                usageDays?: number;
+                /** @description Only keys not used since then (never-used keys created before it), to find stale keys. */
+                unusedSince?: string;
             };
             header?: never;
             path?: never;
@@ -1318,6 +1603,142 @@ export interface operations {
             };
         };
     };
+    APIKeyService_UpdateAPIKey: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                id: string;
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["customersAPIKey"];
+            };
+        };
+        responses: {
+            /** @description A successful response. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["customersAPIKey"];
+                };
+            };
+            /** @description An unexpected error response. */
+            default: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["rpcStatus"];
+                };
+            };
+        };
+    };
+    APIKeyService_RevokeAPIKeys: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["customersRevokeAPIKeysRequest"];
+            };
+        };
+        responses: {
+            /** @description A successful response. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["customersRevokeAPIKeysResponse"];
+                };
+            };
+            /** @description An unexpected error response. */
+            default: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["rpcStatus"];
+                };
+            };
+        };
+    };
+    APIKeyService_RotateAPIKey: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                id: string;
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["APIKeyServiceRotateAPIKeyBody"];
+            };
+        };
+        responses: {
+            /** @description A successful response. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["customersRotateAPIKeyResponse"];
+                };
+            };
+            /** @description An unexpected error response. */
+            default: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["rpcStatus"];
+                };
+            };
+        };
+    };
+    APIKeyService_ReevaluateAPIKeys: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["customersReevaluateAPIKeysRequest"];
+            };
+        };
+        responses: {
+            /** @description A successful response. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["customersReevaluateAPIKeysResponse"];
+                };
+            };
+            /** @description An unexpected error response. */
+            default: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["rpcStatus"];
+                };
+            };
+        };
+    };
     AuditService_QueryAuditLog: {
         parameters: {
             query?: {
@@ -1357,6 +1778,158 @@ export interface operations {
             };
         };
     };
+    AuditService_ListAuditCheckpoints: {
+        parameters: {
+            query?: {
+                orgId?: string;
+                from?: string;
+                to?: string;
+            };
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description A successful response. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["customersListAuditCheckpointsResponse"];
+                };
+            };
+            /** @description An unexpected error response. */
+            default: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["rpcStatus"];
+                };
+            };
+        };
+    };
+    AuditService_ExportAuditLog: {
+        parameters: {
+            query?: {
+                orgId?: string;
+                actorId?: string;
+                action?: string;
+                resource?: string;
+                resourceId?: string;
+                from?: string;
+                to?: string;
+                /** @description  - AUDIT_EXPORT_FORMAT_NDJSON: One JSON object per line */
+                format?: "AUDIT_EXPORT_FORMAT_UNSPECIFIED" | "AUDIT_EXPORT_FORMAT_NDJSON" | "AUDIT_EXPORT_FORMAT_CSV";
+            };
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description A successful response.(streaming responses) */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    /**
+                     * Free form byte stream
+                     * Format: binary
+                     */
+                    "application/json": string;
+                };
+            };
+            /** @description An unexpected error response. */
+            default: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["rpcStatus"];
+                };
+            };
+        };
+    };
+    AuditService_VerifyAuditChain: {
+        parameters: {
+            query?: {
+                orgId?: string;
+                from?: string;
+                to?: string;
+            };
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description A successful response. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["customersVerifyAuditChainResponse"];
+                };
+            };
+            /** @description An unexpected error response. */
+            default: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["rpcStatus"];
+                };
+            };
+        };
+    };
+    AuditService_WatchAuditLog: {
+        parameters: {
+            query?: {
+                orgId?: string;
+                actorId?: string;
+                action?: string;
+                resource?: string;
+                resourceId?: string;
+                from?: string;
+                to?: string;
+                /** @description From a previous WatchAuditLogResponse: resume after that event */
+                resumeToken?: string;
+            };
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description A successful response.(streaming responses) */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    /** Stream result of customersWatchAuditLogResponse */
+                    "application/json": {
+                        result?: components["schemas"]["customersWatchAuditLogResponse"];
+                        error?: components["schemas"]["rpcStatus"];
+                    };
+                };
+            };
+            /** @description An unexpected error response. */
+            default: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["rpcStatus"];
+                };
+            };
+        };
+    };
     AuthService_GetJWKS: {
         parameters: {
             query?: never;
@@ -1485,6 +2058,35 @@ export interface operations {
             };
         };
     };
+    UserService_Health: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description A successful response. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["customersHealthResponse"];
+                };
+            };
+            /** @description An unexpected error response. */
+            default: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["rpcStatus"];
+                };
+            };
+        };
+    };
     IdentityService_ResolveIdentity: {
         parameters: {
             query?: never;
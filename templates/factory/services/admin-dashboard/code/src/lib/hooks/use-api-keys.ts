@@ -1,15 +1,23 @@
 import { useQuery, useMutation, useQueryClient } from "@tanstack/react-query";
 import * as api from "../api-client";
 
-export function useAPIKeys(orgId: string | null) {
+export function useAPIKeys(orgId: string | null, options: api.APIKeyListOptions = {}) {
   return useQuery({
-    queryKey: ["api-keys", orgId],
-    queryFn: () => api.listAPIKeys(orgId!),
+    queryKey: ["api-keys", orgId, options],
+    queryFn: () => api.listAPIKeys(orgId!, options),
     enabled: !!orgId,
     select: (data) => data.keys ?? [],
   });
 }
 
+export function useUpdateAPIKey() {
+  const queryClient = useQueryClient();
+  return useMutation({
+    mutationFn: ({ id, update }: { id: string; update: api.APIKeyUpdate }) => api.updateAPIKey(id, update),
+    onSuccess: () => queryClient.invalidateQueries({ queryKey: ["api-keys"] }),
+  });
+}
+
 export function useRevokeAPIKey() {
   const queryClient = useQueryClient();
   return useMutation({
@@ -17,3 +25,19 @@ export function useRevokeAPIKey() {
     onSuccess: () => queryClient.invalidateQueries({ queryKey: ["api-keys"] }),
   });
 }
+
+export function useRevokeAPIKeys() {
+  const queryClient = useQueryClient();
+  return useMutation({
+    mutationFn: ({ orgId, ids }: { orgId: string; ids: string[] }) => api.revokeAPIKeys(orgId, ids),
+    onSuccess: () => queryClient.invalidateQueries({ queryKey: ["api-keys"] }),
+  });
+}
+
+export function useReevaluateAPIKeys() {
+  const queryClient = useQueryClient();
+  return useMutation({
+    mutationFn: (orgId: string) => api.reevaluateAPIKeys(orgId),
+    onSuccess: () => queryClient.invalidateQueries({ queryKey: ["api-keys"] }),
+  });
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+
+	backend "backend/pkg/gen"
+)
+
+// Defaults for the API key validation cache. Short TTLs bound how long a
+// revoked key keeps working if an invalidation message is missed.
+const (
+	DefaultAPIKeyCacheSize   = 10000
+	DefaultAPIKeyPositiveTTL = 30 * time.Second
+	DefaultAPIKeyNegativeTTL = 10 * time.Second
+)
+
+// Redis keys and channel for the shared cache tier.
+const (
+	apiKeyCachePrefix       = "apikey:cache:"
+	apiKeyCacheIDPrefix     = "apikey:cache:id:"
+	apiKeyRevocationChannel = "api-key-revocations"
+)
+
+// apiKeyCacheLookupTimeout keeps a slow Redis from stalling every request.
+const apiKeyCacheLookupTimeout = 50 * time.Millisecond
+
+// APIKeyCache caches ValidateAPIKey results, both valid and invalid.
+//
+// Entries are keyed by a SHA-256 of the plaintext, so neither tier holds the
+// key itself. The local tier is a bounded LRU; the optional Redis tier is shared
+// across sidecar replicas. Revocations published by the backend evict entries
+// from both.
+type APIKeyCache struct {
+	client      *redis.Client // nil → local only
+	capacity    int
+	positiveTTL time.Duration
+	negativeTTL time.Duration
+
+	mu      sync.Mutex
+	order   *list.List               // front = most recently used
+	entries map[string]*list.Element // hash → element
+	byKeyID map[string]string        // key ID → hash (valid keys only)
+}
+
+type apiKeyCacheEntry struct {
+	hash      string
+	resp      *backend.ValidateAPIKeyResponse
+	expiresAt time.Time
+}
+
+// NewAPIKeyCache creates a cache holding up to capacity entries locally.
+func NewAPIKeyCache(client *redis.Client, capacity int, positiveTTL, negativeTTL time.Duration) *APIKeyCache {
+	return &APIKeyCache{
+		client:      client,
+		capacity:    capacity,
+		positiveTTL: positiveTTL,
+		negativeTTL: negativeTTL,
+		order:       list.New(),
+		entries:     make(map[string]*list.Element),
+		byKeyID:     make(map[string]string),
+	}
+}
+
+// apiKeyCacheKey is the local hash an API key is cached under.
+func apiKeyCacheKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+// Get returns a cached validation result, checking the local tier then Redis.
+func (c *APIKeyCache) Get(ctx context.Context, hash string) (*backend.ValidateAPIKeyResponse, bool) {
+	if resp, ok := c.getLocal(hash); ok {
+		return resp, true
+	}
+	if c.client == nil {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, apiKeyCacheLookupTimeout)
+	defer cancel()
+
+	pipe := c.client.Pipeline()
+	get := pipe.Get(ctx, apiKeyCachePrefix+hash)
+	ttl := pipe.PTTL(ctx, apiKeyCachePrefix+hash)
+	if _, err := pipe.Exec(ctx); err != nil {
+		if err != redis.Nil {
+			log.Printf("WARNING: API key cache lookup failed: %v", err)
+		}
+		return nil, false
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		return nil, false
+	}
+	resp := &backend.ValidateAPIKeyResponse{}
+	if err := proto.Unmarshal(data, resp); err != nil {
+		return nil, false
+	}
+	// Never keep it locally longer than Redis would
+	if remaining := ttl.Val(); remaining > 0 {
+		c.putLocal(hash, resp, time.Now().Add(min(remaining, c.ttlFor(resp))))
+	}
+	return resp, true
+}
+
+// Put caches a validation result in both tiers.
+func (c *APIKeyCache) Put(ctx context.Context, hash string, resp *backend.ValidateAPIKeyResponse) {
+	ttl := c.ttlFor(resp)
+	c.putLocal(hash, resp, time.Now().Add(ttl))
+
+	if c.client == nil {
+		return
+	}
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, apiKeyCacheLookupTimeout)
+	defer cancel()
+
+	pipe := c.client.Pipeline()
+	pipe.Set(ctx, apiKeyCachePrefix+hash, data, ttl)
+	if resp.Valid && resp.KeyId != "" {
+		pipe.Set(ctx, apiKeyCacheIDPrefix+resp.KeyId, hash, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("WARNING: API key cache write failed: %v", err)
+	}
+}
+
+// Invalidate drops every cached validation of the key from both tiers.
+func (c *APIKeyCache) Invalidate(ctx context.Context, keyID string) {
+	c.mu.Lock()
+	if hash, ok := c.byKeyID[keyID]; ok {
+		if el, ok := c.entries[hash]; ok {
+			c.removeElement(el)
+		}
+	}
+	c.mu.Unlock()
+
+	if c.client == nil {
+		return
+	}
+	hash, err := c.client.GetDel(ctx, apiKeyCacheIDPrefix+keyID).Result()
+	if err == redis.Nil {
+		return
+	}
+	if err != nil {
+		log.Printf("WARNING: API key cache invalidation failed: %v", err)
+		return
+	}
+	if err := c.client.Del(ctx, apiKeyCachePrefix+hash).Err(); err != nil {
+		log.Printf("WARNING: API key cache invalidation failed: %v", err)
+	}
+}
+
+// Run listens for API key revocations until ctx is done.
+func (c *APIKeyCache) Run(ctx context.Context) {
+	if c.client == nil {
+		return
+	}
+	sub := c.client.Subscribe(ctx, apiKeyRevocationChannel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			c.Invalidate(ctx, msg.Payload)
+		}
+	}
+}
+
+func (c *APIKeyCache) ttlFor(resp *backend.ValidateAPIKeyResponse) time.Duration {
+	if resp.Valid {
+		// A rotated key must not outlive its grace period in the cache
+		if resp.RevokeAt != nil {
+			return max(min(c.positiveTTL, time.Until(resp.RevokeAt.AsTime())), time.Millisecond)
+		}
+		return c.positiveTTL
+	}
+	return c.negativeTTL
+}
+
+func (c *APIKeyCache) getLocal(hash string) (*backend.ValidateAPIKeyResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[hash]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*apiKeyCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.resp, true
+}
+
+func (c *APIKeyCache) putLocal(hash string, resp *backend.ValidateAPIKeyResponse, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[hash]; ok {
+		c.removeElement(el)
+	}
+	entry := &apiKeyCacheEntry{hash: hash, resp: resp, expiresAt: expiresAt}
+	c.entries[hash] = c.order.PushFront(entry)
+	if resp.Valid && resp.KeyId != "" {
+		c.byKeyID[resp.KeyId] = hash
+	}
+
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// removeElement must be called with mu held.
+func (c *APIKeyCache) removeElement(el *list.Element) {
+	entry := c.order.Remove(el).(*apiKeyCacheEntry)
+	delete(c.entries, entry.hash)
+	if entry.resp.KeyId != "" && c.byKeyID[entry.resp.KeyId] == entry.hash {
+		delete(c.byKeyID, entry.resp.KeyId)
+	}
+}
+
+// apiKeyRestrictionsAllow applies a cached valid key's IP and origin
+// allowlists to a request, with the backend's rules: empty lists allow
+// anything, else the request must carry a matching IP or origin.
+func apiKeyRestrictionsAllow(resp *backend.ValidateAPIKeyResponse, ip, origin string) bool {
+	if len(resp.AllowedCidrs) > 0 {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return false
+		}
+		addr = addr.Unmap()
+		inRange := false
+		for _, cidr := range resp.AllowedCidrs {
+			if prefix, err := netip.ParsePrefix(cidr); err == nil && prefix.Contains(addr) {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			return false
+		}
+	}
+
+	if len(resp.AllowedOrigins) > 0 {
+		u, err := url.Parse(strings.TrimSpace(origin))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return false
+		}
+		scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+		for _, allowed := range resp.AllowedOrigins {
+			allowedScheme, allowedHost, ok := strings.Cut(allowed, "://")
+			if !ok || allowedScheme != scheme {
+				continue
+			}
+			if allowedHost == host {
+				return true
+			}
+			if suffix, wildcard := strings.CutPrefix(allowedHost, "*"); wildcard && strings.HasSuffix(host, suffix) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func isRestrictionViolation(reason backend.APIKeyInvalidReason) bool {
+	return reason == backend.APIKeyInvalidReason_API_KEY_INVALID_REASON_IP_NOT_ALLOWED ||
+		reason == backend.APIKeyInvalidReason_API_KEY_INVALID_REASON_ORIGIN_NOT_ALLOWED
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"hash/crc32"
+	"strings"
+)
+
+// API key formats, as issued by the backend (business/api_key_format.go):
+//
+//	cfly_k1_{live|test}_{43 base62 payload}{6 base62 CRC32 of the rest}
+//	cfly_sk_{live|test}_{32 base62}   legacy, no checksum
+//
+// Keys that don't match are rejected without a cache lookup or backend call.
+const (
+	apiKeyPrefixV1         = "cfly_k1_"
+	apiKeyPrefixLegacy     = "cfly_sk_"
+	apiKeyPayloadLen       = 43
+	apiKeyChecksumLen      = 6
+	legacyAPIKeyPayloadLen = 32
+)
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// isAPIKey reports whether a bearer token is meant as an API key rather than a JWT.
+func isAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefixV1) || strings.HasPrefix(token, apiKeyPrefixLegacy)
+}
+
+// apiKeyWellFormed reports whether key is a current key with a valid checksum
+// or a legacy key.
+func apiKeyWellFormed(key string) bool {
+	if rest, ok := strings.CutPrefix(key, apiKeyPrefixLegacy); ok {
+		payload, ok := cutAPIKeyEnv(rest)
+		return ok && len(payload) == legacyAPIKeyPayloadLen && isBase62(payload)
+	}
+	rest, ok := strings.CutPrefix(key, apiKeyPrefixV1)
+	if !ok {
+		return false
+	}
+	payload, ok := cutAPIKeyEnv(rest)
+	if !ok || len(payload) != apiKeyPayloadLen+apiKeyChecksumLen || !isBase62(payload) {
+		return false
+	}
+	body := key[:len(key)-apiKeyChecksumLen]
+	return apiKeyChecksum(body) == key[len(body):]
+}
+
+func cutAPIKeyEnv(s string) (string, bool) {
+	if rest, ok := strings.CutPrefix(s, "live_"); ok {
+		return rest, true
+	}
+	return strings.CutPrefix(s, "test_")
+}
+
+// apiKeyChecksum is the CRC32 (IEEE) of s in fixed-width base62.
+func apiKeyChecksum(s string) string {
+	sum := crc32.ChecksumIEEE([]byte(s))
+	encoded := make([]byte, apiKeyChecksumLen)
+	for i := apiKeyChecksumLen - 1; i >= 0; i-- {
+		encoded[i] = base62Alphabet[sum%62]
+		sum /= 62
+	}
+	return string(encoded)
+}
+
+func isBase62(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z') {
+			return false
+		}
+	}
+	return true
+}
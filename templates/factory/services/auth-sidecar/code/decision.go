@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Default decision log sampling: every denial, 1% of allowed requests.
+const (
+	DefaultAllowSampleRate = 0.01
+	DefaultDenySampleRate  = 1.0
+)
+
+// Auth paths reported in decisions.
+const (
+	authPathAnonymous   = "anonymous"
+	authPathJWT         = "jwt"
+	authPathExternalJWT = "external_jwt"
+	authPathAPIKey      = "api_key"
+)
+
+// Decision is one Check outcome, as written to the decision log.
+type Decision struct {
+	Time time.Time `json:"time"`
+	// Principal is the user ID, or the API key ID for API keys.
+	Principal string `json:"principal,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+	OrgID     string `json:"org_id,omitempty"`
+	AuthPath  string `json:"auth_path"`
+	ClientIP  string `json:"client_ip,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Path      string `json:"path"`
+	// Route is the policy rule or public route that matched, if any.
+	Route    string `json:"route,omitempty"`
+	Allowed  bool   `json:"allowed"`
+	Status   int    `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+	Duration int64  `json:"duration_us"`
+	// Shadow is set when a denial was not enforced because its route is in
+	// shadow mode; ShadowStatus and ShadowReason describe the denial that
+	// would have been sent.
+	Shadow       bool   `json:"shadow,omitempty"`
+	ShadowStatus int    `json:"shadow_status,omitempty"`
+	ShadowReason string `json:"shadow_reason,omitempty"`
+}
+
+// DecisionSink receives sampled decisions. Implementations must be safe for
+// concurrent use and should not block: Record runs on the request path.
+type DecisionSink interface {
+	Record(d *Decision)
+}
+
+// JSONDecisionSink writes one JSON object per line.
+type JSONDecisionSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONDecisionSink writes decisions to w (stdout in production, for the
+// log collector to pick up).
+func NewJSONDecisionSink(w io.Writer) *JSONDecisionSink {
+	return &JSONDecisionSink{enc: json.NewEncoder(w)}
+}
+
+// Record implements DecisionSink.
+func (s *JSONDecisionSink) Record(d *Decision) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.enc.Encode(d); err != nil {
+		log.Printf("WARNING: cannot write decision log: %v", err)
+	}
+}
+
+// DecisionLogger samples decisions into a sink and counts shadow denials.
+//
+// Allowed requests are sampled at allowRate, denials at denyRate (usually 1).
+// Shadow denials are always recorded: they are what a rollout is watching.
+type DecisionLogger struct {
+	sink      DecisionSink
+	allowRate float64
+	denyRate  float64
+
+	mu            sync.Mutex
+	shadowDenials map[string]int64 // route → count
+}
+
+// NewDecisionLogger creates a logger; rates are between 0 (never) and 1 (always).
+func NewDecisionLogger(sink DecisionSink, allowRate, denyRate float64) *DecisionLogger {
+	return &DecisionLogger{
+		sink:          sink,
+		allowRate:     allowRate,
+		denyRate:      denyRate,
+		shadowDenials: make(map[string]int64),
+	}
+}
+
+// Log counts the decision and hands it to the sink if sampled.
+func (l *DecisionLogger) Log(d *Decision) {
+	if d.Shadow {
+		l.mu.Lock()
+		l.shadowDenials[d.Route]++
+		l.mu.Unlock()
+		l.sink.Record(d)
+		return
+	}
+	rate := l.allowRate
+	if !d.Allowed {
+		rate = l.denyRate
+	}
+	if rate >= 1 || (rate > 0 && rand.Float64() < rate) {
+		l.sink.Record(d)
+	}
+}
+
+// ShadowDenials returns how many requests each shadow-mode route would have denied.
+func (l *DecisionLogger) ShadowDenials() map[string]int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	counts := make(map[string]int64, len(l.shadowDenials))
+	for route, n := range l.shadowDenials {
+		counts[route] = n
+	}
+	return counts
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+)
+
+// ForwardAuthPath is where the HTTP forward-auth endpoint is served.
+const ForwardAuthPath = "/auth"
+
+// ForwardAuthHandler exposes Check over plain HTTP for proxies without
+// ext_authz support:
+//   - nginx: auth_request, original request in X-Original-Method / X-Original-URI
+//   - Traefik: ForwardAuth, original request in X-Forwarded-Method / X-Forwarded-Uri
+//
+// It answers 200 with X-User-Id / X-Org-Id / X-Roles (or X-Scopes) response
+// headers for the proxy to copy upstream, or the denial status (401, 403, 429)
+// with its body and headers. Identity headers the sidecar didn't set are sent
+// empty, so the proxy overwrites whatever the client sent. Every identity
+// header (X-User-Id, X-Org-Id, X-Roles, X-Scopes, X-Actor-Type, X-Actor-Id)
+// must therefore be copied from the response: list them all in Traefik's
+// authResponseHeaders, or proxy_set_header each from an auth_request_set
+// variable with nginx.
+//
+// The client address is the proxy's X-Real-IP when the proxy is one of the
+// trusted proxies (see SetTrustedProxies), else the peer's address.
+func (s *Sidecar) ForwardAuthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := s.Check(r.Context(), s.forwardAuthCheckRequest(r))
+		if err != nil {
+			log.Printf("ERROR forward-auth check: %v", err)
+			http.Error(w, "authorization check failed", http.StatusInternalServerError)
+			return
+		}
+
+		if ok := resp.GetOkResponse(); ok != nil {
+			setHeaders(w.Header(), ok.GetHeaders())
+			for _, name := range ok.GetHeadersToRemove() {
+				w.Header().Set(name, "")
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		denied := resp.GetDeniedResponse()
+		setHeaders(w.Header(), denied.GetHeaders())
+		code := int(denied.GetStatus().GetCode())
+		if code == 0 {
+			code = http.StatusForbidden
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(denied.GetBody()))
+	})
+}
+
+// forwardAuthCheckRequest rebuilds the ext_authz request for the original
+// request described by the proxy's headers. Header names are lowercased as
+// envoy does; x-envoy-* headers are dropped, as only envoy may set them and
+// here they come from the client.
+func (s *Sidecar) forwardAuthCheckRequest(r *http.Request) *authv3.CheckRequest {
+	headers := make(map[string]string, len(r.Header))
+	for name, values := range r.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-envoy-") {
+			continue
+		}
+		headers[name] = strings.Join(values, ",")
+	}
+
+	method := firstHeader(r.Header, "X-Forwarded-Method", "X-Original-Method")
+	if method == "" {
+		method = r.Method
+	}
+	path := firstHeader(r.Header, "X-Forwarded-Uri", "X-Original-URI")
+	if path == "" {
+		path = r.URL.RequestURI()
+	}
+
+	// The proxy is the peer; it passes the client's address in X-Real-IP,
+	// which anyone else could forge
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+	if realIP := firstHeader(r.Header, "X-Real-Ip"); realIP != "" && s.trustedProxy(ip) {
+		ip = realIP
+	}
+
+	return &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			Source: &authv3.AttributeContext_Peer{
+				Address: &corev3.Address{Address: &corev3.Address_SocketAddress{
+					SocketAddress: &corev3.SocketAddress{Address: ip},
+				}},
+			},
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{
+					Method:  method,
+					Path:    path,
+					Host:    firstHeader(r.Header, "X-Forwarded-Host"),
+					Headers: headers,
+				},
+			},
+		},
+	}
+}
+
+// ParseTrustedProxies parses a comma-separated list of CIDRs or IPs.
+func ParseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func (s *Sidecar) trustedProxy(address string) bool {
+	ip, err := netip.ParseAddr(address)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	return slices.ContainsFunc(s.trustedProxies, func(p netip.Prefix) bool { return p.Contains(ip) })
+}
+
+func firstHeader(h http.Header, names ...string) string {
+	for _, name := range names {
+		if v := h.Get(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func setHeaders(h http.Header, options []*corev3.HeaderValueOption) {
+	for _, opt := range options {
+		h.Set(opt.GetHeader().GetKey(), opt.GetHeader().GetValue())
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// providerPattern matches ResolveIdentityRequest.provider.
+var providerPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,50}$`)
+
+// IssuersConfig lists the third-party identity providers whose tokens are accepted.
+//
+// Example:
+//
+//	issuers:
+//	  - issuer: https://example.eu.auth0.com/
+//	    jwks_url: https://example.eu.auth0.com/.well-known/jwks.json
+//	    audience: [https://api.example.com]
+//	    provider: auth0
+//	  - issuer: https://keycloak.example.com/realms/main
+//	    jwks_url: https://keycloak.example.com/realms/main/protocol/openid-connect/certs
+//	    audience: [api]
+//	    provider: keycloak
+//	    claims:
+//	      subject: preferred_username
+//
+// A token is matched to its issuer by the iss claim, verified with the issuer's
+// JWKS, and its subject is mapped to an internal user with ResolveIdentity
+// (provider + subject), so the user must have linked that identity.
+type IssuersConfig struct {
+	Issuers []*IssuerConfig `yaml:"issuers"`
+}
+
+// IssuerConfig is one trusted issuer.
+type IssuerConfig struct {
+	// Issuer must equal the token's iss claim.
+	Issuer string `yaml:"issuer"`
+	// JWKSURL serves the issuer's signing keys.
+	JWKSURL string `yaml:"jwks_url"`
+	// Audience lists accepted aud values; the token must carry at least one.
+	Audience []string `yaml:"audience"`
+	// Provider is the identity provider name passed to ResolveIdentity.
+	Provider string `yaml:"provider"`
+	// Algorithms accepted for this issuer; defaults to DefaultAlgorithms.
+	Algorithms []string `yaml:"algorithms"`
+	// Claims maps the issuer's claims to ours.
+	Claims ClaimMapping `yaml:"claims"`
+}
+
+// ClaimMapping names the claims to read from an external token.
+type ClaimMapping struct {
+	// Subject is the claim holding the provider's user ID; defaults to "sub".
+	Subject string `yaml:"subject"`
+}
+
+// ParseIssuers parses and validates a YAML issuer list.
+func ParseIssuers(data []byte) (*IssuersConfig, error) {
+	config := &IssuersConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("cannot parse issuers: %w", err)
+	}
+	seen := make(map[string]bool)
+	for i, iss := range config.Issuers {
+		if iss.Issuer == "" {
+			return nil, fmt.Errorf("issuer %d: issuer is required", i)
+		}
+		if seen[iss.Issuer] {
+			return nil, fmt.Errorf("issuer %d: duplicate issuer %s", i, iss.Issuer)
+		}
+		seen[iss.Issuer] = true
+		if iss.Issuer == backendIssuer {
+			return nil, fmt.Errorf("issuer %d: %s is reserved for backend tokens", i, backendIssuer)
+		}
+		if u, err := url.Parse(iss.JWKSURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("issuer %d: jwks_url must be an http(s) URL", i)
+		}
+		if len(iss.Audience) == 0 {
+			return nil, fmt.Errorf("issuer %d: at least one audience is required", i)
+		}
+		if !providerPattern.MatchString(iss.Provider) {
+			return nil, fmt.Errorf("issuer %d: provider must match %s", i, providerPattern)
+		}
+		if len(iss.Algorithms) == 0 {
+			iss.Algorithms = DefaultAlgorithms
+		}
+		if iss.Claims.Subject == "" {
+			iss.Claims.Subject = "sub"
+		}
+	}
+	return config, nil
+}
+
+// LoadIssuers reads and parses an issuer list file.
+func LoadIssuers(path string) (*IssuersConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read issuers %s: %w", path, err)
+	}
+	return ParseIssuers(data)
+}
+
+// TrustedIssuers holds the configured issuers and their key sets.
+type TrustedIssuers struct {
+	byIssuer map[string]*trustedIssuer
+}
+
+type trustedIssuer struct {
+	config *IssuerConfig
+	jwks   *KeySet
+}
+
+// NewTrustedIssuers creates a JWKS cache per issuer. Keys are fetched on
+// first use, or in the background with Run.
+func NewTrustedIssuers(config *IssuersConfig, client *http.Client) *TrustedIssuers {
+	t := &TrustedIssuers{byIssuer: make(map[string]*trustedIssuer)}
+	for _, iss := range config.Issuers {
+		t.byIssuer[iss.Issuer] = &trustedIssuer{
+			config: iss,
+			jwks:   NewRemoteJWKS(client, iss.JWKSURL, iss.Algorithms, DefaultJWKSCacheTTL, DefaultJWKSMinRefresh),
+		}
+	}
+	return t
+}
+
+// Run keeps every issuer's keys fresh until ctx is done.
+func (t *TrustedIssuers) Run(ctx context.Context, interval time.Duration) {
+	for _, iss := range t.byIssuer {
+		go iss.jwks.Run(ctx, interval)
+	}
+}
+
+// lookup returns the issuer for an iss claim, or nil.
+func (t *TrustedIssuers) lookup(issuer string) *trustedIssuer {
+	if t == nil {
+		return nil
+	}
+	return t.byIssuer[issuer]
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// DefaultAlgorithms are the JWT algorithms accepted when none are configured.
+var DefaultAlgorithms = []string{"EdDSA", "ES256", "RS256"}
+
+// JWTKey is a verification key taken from the backend JWKS.
+type JWTKey struct {
+	KeyID     string
+	Algorithm string
+	PublicKey crypto.PublicKey
+}
+
+// jwk is the subset of RFC 7517 fields we understand (OKP, EC, RSA).
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKSet returns every key in the set whose algorithm is allowed, in order.
+// Keys with another algorithm or key type are skipped; a malformed allowed key is an error.
+func parseJWKSet(keysJSON []byte, allowed []string) ([]*JWTKey, error) {
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(keysJSON, &jwks); err != nil {
+		return nil, fmt.Errorf("cannot parse JWKS: %w", err)
+	}
+
+	var keys []*JWTKey
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if k.Alg == "" {
+			// alg is optional in RFC 7517; some IdPs only publish kty/crv
+			k.Alg = defaultAlgorithm(k)
+		}
+		if !slices.Contains(allowed, k.Alg) {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k.Kid, err)
+		}
+		keys = append(keys, &JWTKey{KeyID: k.Kid, Algorithm: k.Alg, PublicKey: pub})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key with an allowed algorithm %v in JWKS", allowed)
+	}
+	return keys, nil
+}
+
+// defaultAlgorithm picks the algorithm for a key that doesn't declare one.
+func defaultAlgorithm(k jwk) string {
+	switch {
+	case k.Kty == "OKP" && k.Crv == "Ed25519":
+		return "EdDSA"
+	case k.Kty == "EC" && k.Crv == "P-256":
+		return "ES256"
+	case k.Kty == "RSA":
+		return "RS256"
+	}
+	return ""
+}
+
+// publicKey decodes the key material, checking it matches the declared algorithm.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch {
+	case k.Alg == "EdDSA" && k.Kty == "OKP" && k.Crv == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size %d", len(x))
+		}
+		return ed25519.PublicKey(x), nil
+
+	case k.Alg == "ES256" && k.Kty == "EC" && k.Crv == "P-256":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode y: %w", err)
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, fmt.Errorf("EC point is not on P-256")
+		}
+		return pub, nil
+
+	case k.Alg == "RS256" && k.Kty == "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode n: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode e: %w", err)
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	}
+	return nil, fmt.Errorf("unsupported key kty=%s crv=%s alg=%s", k.Kty, k.Crv, k.Alg)
+}
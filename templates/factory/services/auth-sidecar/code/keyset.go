@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	backend "backend/pkg/gen"
+)
+
+// Defaults for JWKS refresh. Unknown kids trigger a refetch, at most once per
+// DefaultJWKSMinRefresh so bogus kids can't hammer the key source.
+const (
+	DefaultJWKSRefreshInterval = 5 * time.Minute
+	DefaultJWKSCacheTTL        = time.Hour
+	DefaultJWKSMinRefresh      = 30 * time.Second
+
+	jwksFetchTimeout = 5 * time.Second
+	jwksRetryMin     = time.Second
+	maxJWKSSize      = 1 << 20
+)
+
+// jwksSource returns a JWKS document.
+type jwksSource func(ctx context.Context) ([]byte, error)
+
+// KeySet is a JWKS indexed by kid, kept up to date from its source.
+//
+// Keys are refetched when a token names an unknown kid (key rotation), when
+// older than ttl if set, and periodically by Run. When the source is down the
+// last good set keeps being served.
+type KeySet struct {
+	name       string
+	source     jwksSource
+	allowed    []string
+	ttl        time.Duration // 0 → only Run and unknown kids refresh
+	minRefresh time.Duration
+
+	fetchMu sync.Mutex // one fetch at a time
+
+	mu          sync.RWMutex
+	keys        map[string]*JWTKey // kid → key
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+func newKeySet(name string, source jwksSource, allowed []string, ttl, minRefresh time.Duration) *KeySet {
+	return &KeySet{name: name, source: source, allowed: allowed, ttl: ttl, minRefresh: minRefresh}
+}
+
+// NewBackendJWKS creates the key set for the backend's access tokens, read with
+// AuthService.GetJWKS. Call Run to load and refresh it.
+func NewBackendJWKS(client backend.AuthServiceClient, allowed []string, minRefresh time.Duration) *KeySet {
+	return newKeySet("backend JWKS", func(ctx context.Context) ([]byte, error) {
+		resp, err := client.GetJWKS(ctx, &emptypb.Empty{})
+		if err != nil {
+			return nil, err
+		}
+		return []byte(resp.KeysJson), nil
+	}, allowed, 0, minRefresh)
+}
+
+// NewRemoteJWKS creates a key set fetched over HTTP from url, lazily on first use.
+func NewRemoteJWKS(client *http.Client, url string, allowed []string, ttl, minRefresh time.Duration) *KeySet {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return newKeySet("JWKS "+url, func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	}, allowed, ttl, minRefresh)
+}
+
+// Key returns the key with the given kid. An empty kid matches only when the
+// set holds a single key.
+func (k *KeySet) Key(ctx context.Context, kid string) (*JWTKey, error) {
+	k.mu.RLock()
+	key := k.find(kid)
+	stale := k.ttl > 0 && time.Since(k.fetchedAt) > k.ttl
+	k.mu.RUnlock()
+	if key != nil && !stale {
+		return key, nil
+	}
+
+	if k.tryAttempt() {
+		if err := k.Refresh(ctx); err != nil {
+			log.Printf("WARNING: cannot refresh %s: %v", k.name, err)
+		} else {
+			k.mu.RLock()
+			key = k.find(kid)
+			k.mu.RUnlock()
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("no key %q in %s", kid, k.name)
+	}
+	return key, nil
+}
+
+// Ready reports whether at least one key is loaded.
+func (k *KeySet) Ready() bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return len(k.keys) > 0
+}
+
+// Algorithms returns the algorithms accepted from this set.
+func (k *KeySet) Algorithms() []string {
+	return k.allowed
+}
+
+// Refresh fetches the set and replaces the keys. On error the current keys are kept.
+func (k *KeySet) Refresh(ctx context.Context) error {
+	k.fetchMu.Lock()
+	defer k.fetchMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+	defer cancel()
+
+	data, err := k.source(ctx)
+	if err != nil {
+		return err
+	}
+	list, err := parseJWKSet(data, k.allowed)
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]*JWTKey, len(list))
+	for _, key := range list {
+		keys[key.KeyID] = key
+	}
+
+	k.mu.Lock()
+	changed := !sameKeyIDs(k.keys, keys)
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	k.mu.Unlock()
+
+	if changed {
+		log.Printf("%s loaded (%d keys)", k.name, len(keys))
+	}
+	return nil
+}
+
+// Run refreshes the set every interval until ctx is done. Until the first
+// successful fetch it retries with backoff from jwksRetryMin up to interval.
+func (k *KeySet) Run(ctx context.Context, interval time.Duration) {
+	retry := jwksRetryMin
+	for {
+		wait := interval
+		if err := k.Refresh(ctx); err != nil {
+			log.Printf("WARNING: cannot refresh %s: %v", k.name, err)
+			if !k.Ready() {
+				wait = retry
+				retry = min(retry*2, interval)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// tryAttempt reports whether an on-demand refresh may run now, and records it.
+func (k *KeySet) tryAttempt() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if time.Since(k.lastAttempt) < k.minRefresh {
+		return false
+	}
+	k.lastAttempt = time.Now()
+	return true
+}
+
+// find must be called with mu held.
+func (k *KeySet) find(kid string) *JWTKey {
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key
+		}
+	}
+	return k.keys[kid]
+}
+
+func sameKeyIDs(a, b map[string]*JWTKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for kid := range a {
+		if _, ok := b[kid]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -2,14 +2,15 @@ package main
 
 import (
 	"context"
-	"crypto/ed25519"
-	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -17,11 +18,13 @@ import (
 	"github.com/codefly-dev/core/standards"
 
 	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
-	"google.golang.org/protobuf/types/known/emptypb"
 
+	sidecarpb "auth-sidecar/pkg/gen"
 	backend "backend/pkg/gen"
 )
 
@@ -48,12 +51,77 @@ func main() {
 	}
 	defer backendConn.Close()
 
-	publicKey := fetchPublicKey(ctx, backendConn)
+	// Keys load in the background; health reports not-ready until they do
+	jwtKeys := NewBackendJWKS(backend.NewAuthServiceClient(backendConn), jwtAlgorithms(ctx), DefaultJWKSMinRefresh)
+	go jwtKeys.Run(ctx, DefaultJWKSRefreshInterval)
 
-	sidecar := NewSidecar(backendConn, publicKey)
+	sidecar := NewSidecar(backendConn, jwtKeys)
+
+	cache := connectCache(ctx)
+
+	revocations := NewRevocationChecker(cache)
+	go revocations.Run(ctx)
+	sidecar.SetRevocations(revocations)
+
+	apiKeyCache := NewAPIKeyCache(cache, DefaultAPIKeyCacheSize, DefaultAPIKeyPositiveTTL, DefaultAPIKeyNegativeTTL)
+	go apiKeyCache.Run(ctx)
+	sidecar.SetAPIKeyCache(apiKeyCache)
+
+	rateLimiter := NewRateLimiter(backend.NewMeteringServiceClient(backendConn), DefaultRateLimitRefresh)
+	usageFlushed := make(chan struct{})
+	go func() {
+		rateLimiter.Run(ctx, DefaultUsageFlushInterval)
+		close(usageFlushed)
+	}()
+	sidecar.SetRateLimiter(rateLimiter)
+
+	resolver := NewResolver(backend.NewIdentityServiceClient(backendConn), sidecar,
+		NewResolveCache(DefaultResolveCacheSize, DefaultResolvePositiveTTL, DefaultResolveNegativeTTL))
+
+	// Third-party IdP tokens are opt-in: point the "issuers" configuration at a YAML file
+	if issuersFile, err := codefly.For(ctx).Configuration("issuers", "file"); err == nil && issuersFile != "" {
+		issuers, err := LoadIssuers(issuersFile)
+		if err != nil {
+			panic(fmt.Sprintf("cannot load trusted issuers: %v", err))
+		}
+		trusted := NewTrustedIssuers(issuers, &http.Client{Timeout: jwksFetchTimeout})
+		trusted.Run(ctx, DefaultJWKSRefreshInterval)
+		sidecar.SetTrustedIssuers(trusted, resolver)
+		log.Printf("accepting tokens from %d third-party issuers", len(issuers.Issuers))
+	}
+
+	// Route-level authorization is opt-in: point the "policy" configuration at a YAML file
+	if policyFile, err := codefly.For(ctx).Configuration("policy", "file"); err == nil && policyFile != "" {
+		policy, err := NewPolicyWatcher(policyFile)
+		if err != nil {
+			panic(fmt.Sprintf("cannot load authorization policy: %v", err))
+		}
+		go func() {
+			if err := policy.Run(ctx); err != nil {
+				log.Printf("WARNING: policy hot-reload disabled: %v", err)
+			}
+		}()
+		sidecar.SetPolicy(policy)
+	}
+
+	// Forward-auth believes X-Real-IP only from the proxies listed in the
+	// "forward_auth" configuration
+	if value, err := codefly.For(ctx).Configuration("forward_auth", "trusted_proxies"); err == nil && value != "" {
+		proxies, err := ParseTrustedProxies(value)
+		if err != nil {
+			panic(fmt.Sprintf("cannot parse trusted proxies: %v", err))
+		}
+		sidecar.SetTrustedProxies(proxies)
+	}
+
+	// Decision logs go to stdout as JSON lines, sampled per the "decisions" configuration
+	sidecar.SetDecisionLogger(NewDecisionLogger(NewJSONDecisionSink(os.Stdout),
+		sampleRate(ctx, "sample_rate", DefaultAllowSampleRate),
+		sampleRate(ctx, "deny_sample_rate", DefaultDenySampleRate)))
 
 	grpcServer := grpc.NewServer()
 	authv3.RegisterAuthorizationServer(grpcServer, sidecar)
+	sidecarpb.RegisterAuthSidecarServiceServer(grpcServer, resolver)
 	reflection.Register(grpcServer)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
@@ -61,63 +129,118 @@ func main() {
 		panic(fmt.Sprintf("failed to listen: %v", err))
 	}
 
-	fmt.Printf("auth-sidecar listening on :%d (backend: %s, jwt: %v)\n",
-		grpcPort, backendAddr, publicKey != nil)
+	fmt.Printf("auth-sidecar listening on :%d (backend: %s)\n", grpcPort, backendAddr)
+
+	// Leaked-key reports are opt-in: point the "secret_scanning" configuration at
+	// the partner's PEM public key
+	var secretScanning *SecretScanningHandler
+	if keyFile, err := codefly.For(ctx).Configuration("secret_scanning", "public_key_file"); err == nil && keyFile != "" {
+		keyID, _ := codefly.For(ctx).Configuration("secret_scanning", "key_id")
+		secretScanning, err = LoadSecretScanningHandler(backend.NewAPIKeyServiceClient(backendConn), keyFile, keyID)
+		if err != nil {
+			panic(fmt.Sprintf("cannot load secret scanning key: %v", err))
+		}
+	}
+
+	// HTTP: forward-auth for nginx/Traefik, plus Resolve and Health over REST
+	var httpServer *http.Server
+	if restNet := codefly.For(ctx).WithDefaultNetwork().API(standards.REST).NetworkInstance(); restNet != nil {
+		httpServer, err = newHTTPServer(ctx, restNet.Port, sidecar, resolver, secretScanning)
+		if err != nil {
+			panic(fmt.Sprintf("cannot create HTTP server: %v", err))
+		}
+		go func() {
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fmt.Fprintf(os.Stderr, "HTTP server error: %v\n", err)
+			}
+		}()
+		fmt.Printf("auth-sidecar forward-auth listening on :%d%s\n", restNet.Port, ForwardAuthPath)
+	}
 
 	go func() {
 		<-ctx.Done()
+		if httpServer != nil {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_ = httpServer.Shutdown(shutdownCtx)
+			cancel()
+		}
 		grpcServer.GracefulStop()
 	}()
 
 	if err := grpcServer.Serve(lis); err != nil {
 		fmt.Fprintf(os.Stderr, "server error: %v\n", err)
 	}
+
+	// Don't lose the last batch of metered usage
+	stop()
+	<-usageFlushed
 }
 
-// fetchPublicKey calls backend's GetJWKS to get the Ed25519 public key.
-func fetchPublicKey(ctx context.Context, conn *grpc.ClientConn) ed25519.PublicKey {
-	client := backend.NewAuthServiceClient(conn)
-
-	// Retry — backend may still be starting
-	var resp *backend.JWKSResponse
-	var err error
-	for i := 0; i < 30; i++ {
-		resp, err = client.GetJWKS(ctx, &emptypb.Empty{})
-		if err == nil {
-			break
-		}
-		time.Sleep(500 * time.Millisecond)
+// newHTTPServer serves forward-auth on ForwardAuthPath, leaked-key reports on
+// SecretScanningPath when enabled, and the AuthSidecarService REST gateway
+// (/v1/resolve, /healthz) on every other path.
+func newHTTPServer(ctx context.Context, port uint16, sidecar *Sidecar, resolver *Resolver, secretScanning *SecretScanningHandler) (*http.Server, error) {
+	gwMux := runtime.NewServeMux()
+	if err := sidecarpb.RegisterAuthSidecarServiceHandlerServer(ctx, gwMux, resolver); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(ForwardAuthPath, sidecar.ForwardAuthHandler())
+	if secretScanning != nil {
+		mux.Handle(SecretScanningPath, secretScanning)
 	}
+	mux.Handle("/", gwMux)
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}, nil
+}
+
+// connectCache opens the Redis cache used for the token denylist and the shared API key cache.
+// Returns nil when the cache is not configured; both then work from local state only.
+func connectCache(ctx context.Context) *redis.Client {
+	connection, err := codefly.For(ctx).Service("cache").Secret("redis", "write")
 	if err != nil {
-		log.Printf("WARNING: cannot fetch JWKS from backend: %v (JWT validation disabled)", err)
+		log.Printf("WARNING: cache not configured: %v (using local state only)", err)
 		return nil
 	}
+	opts, err := redis.ParseURL(connection)
+	if err != nil {
+		log.Printf("WARNING: cannot parse cache connection: %v (using local state only)", err)
+		return nil
+	}
+	return redis.NewClient(opts)
+}
 
-	var jwks struct {
-		Keys []struct {
-			Kty string `json:"kty"`
-			Crv string `json:"crv"`
-			X   string `json:"x"`
-			Alg string `json:"alg"`
-		} `json:"keys"`
+// sampleRate reads a decision log sample rate in [0, 1] from the "decisions" configuration.
+func sampleRate(ctx context.Context, name string, fallback float64) float64 {
+	value, err := codefly.For(ctx).Configuration("decisions", name)
+	if err != nil || value == "" {
+		return fallback
 	}
-	if err := json.Unmarshal([]byte(resp.KeysJson), &jwks); err != nil {
-		log.Printf("WARNING: cannot parse JWKS: %v (JWT validation disabled)", err)
-		return nil
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		log.Printf("WARNING: invalid decisions %s %q, using %g", name, value, fallback)
+		return fallback
 	}
+	return rate
+}
 
-	for _, key := range jwks.Keys {
-		if key.Kty == "OKP" && key.Crv == "Ed25519" && key.Alg == "EdDSA" {
-			pubBytes, err := base64.RawURLEncoding.DecodeString(key.X)
-			if err != nil {
-				log.Printf("WARNING: cannot decode public key: %v", err)
-				return nil
-			}
-			log.Printf("JWT public key loaded from backend JWKS")
-			return ed25519.PublicKey(pubBytes)
+// jwtAlgorithms reads the accepted JWT algorithms from the "jwt" configuration
+// (comma-separated), falling back to DefaultAlgorithms.
+func jwtAlgorithms(ctx context.Context) []string {
+	value, err := codefly.For(ctx).Configuration("jwt", "algorithms")
+	if err != nil || value == "" {
+		return DefaultAlgorithms
+	}
+	var algorithms []string
+	for _, alg := range strings.Split(value, ",") {
+		if alg = strings.TrimSpace(alg); alg != "" {
+			algorithms = append(algorithms, alg)
 		}
 	}
-
-	log.Printf("WARNING: no Ed25519 key found in JWKS (JWT validation disabled)")
-	return nil
+	return algorithms
 }
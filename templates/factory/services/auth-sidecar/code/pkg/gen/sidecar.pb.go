@@ -189,8 +189,11 @@ func (*HealthRequest) Descriptor() ([]byte, []int) {
 }
 
 type HealthResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// Requests each shadow-mode route would have denied since startup,
+	// to review before enforcing it.
+	ShadowDenials map[string]int64 `protobuf:"bytes,2,rep,name=shadow_denials,json=shadowDenials,proto3" json:"shadow_denials,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -232,6 +235,13 @@ func (x *HealthResponse) GetStatus() string {
 	return ""
 }
 
+func (x *HealthResponse) GetShadowDenials() map[string]int64 {
+	if x != nil {
+		return x.ShadowDenials
+	}
+	return nil
+}
+
 var File_sidecar_proto protoreflect.FileDescriptor
 
 const file_sidecar_proto_rawDesc = "" +
@@ -246,9 +256,13 @@ const file_sidecar_proto_rawDesc = "" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x15\n" +
 	"\x06org_id\x18\x03 \x01(\tR\x05orgId\x12\x14\n" +
 	"\x05roles\x18\x04 \x03(\tR\x05roles\"\x0f\n" +
-	"\rHealthRequest\"(\n" +
+	"\rHealthRequest\"\xc1\x01\n" +
 	"\x0eHealthResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status2\xc7\x01\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12U\n" +
+	"\x0eshadow_denials\x18\x02 \x03(\v2..authsidecar.HealthResponse.ShadowDenialsEntryR\rshadowDenials\x1a@\n" +
+	"\x12ShadowDenialsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x012\xc7\x01\n" +
 	"\x12AuthSidecarService\x12\\\n" +
 	"\aResolve\x12\x1b.authsidecar.ResolveRequest\x1a\x1c.authsidecar.ResolveResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/resolve\x12S\n" +
 	"\x06Health\x12\x1a.authsidecar.HealthRequest\x1a\x1b.authsidecar.HealthResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
@@ -267,23 +281,25 @@ func file_sidecar_proto_rawDescGZIP() []byte {
 	return file_sidecar_proto_rawDescData
 }
 
-var file_sidecar_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_sidecar_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_sidecar_proto_goTypes = []any{
 	(*ResolveRequest)(nil),  // 0: authsidecar.ResolveRequest
 	(*ResolveResponse)(nil), // 1: authsidecar.ResolveResponse
 	(*HealthRequest)(nil),   // 2: authsidecar.HealthRequest
 	(*HealthResponse)(nil),  // 3: authsidecar.HealthResponse
+	nil,                     // 4: authsidecar.HealthResponse.ShadowDenialsEntry
 }
 var file_sidecar_proto_depIdxs = []int32{
-	0, // 0: authsidecar.AuthSidecarService.Resolve:input_type -> authsidecar.ResolveRequest
-	2, // 1: authsidecar.AuthSidecarService.Health:input_type -> authsidecar.HealthRequest
-	1, // 2: authsidecar.AuthSidecarService.Resolve:output_type -> authsidecar.ResolveResponse
-	3, // 3: authsidecar.AuthSidecarService.Health:output_type -> authsidecar.HealthResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	4, // 0: authsidecar.HealthResponse.shadow_denials:type_name -> authsidecar.HealthResponse.ShadowDenialsEntry
+	0, // 1: authsidecar.AuthSidecarService.Resolve:input_type -> authsidecar.ResolveRequest
+	2, // 2: authsidecar.AuthSidecarService.Health:input_type -> authsidecar.HealthRequest
+	1, // 3: authsidecar.AuthSidecarService.Resolve:output_type -> authsidecar.ResolveResponse
+	3, // 4: authsidecar.AuthSidecarService.Health:output_type -> authsidecar.HealthResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_sidecar_proto_init() }
@@ -297,7 +313,7 @@ func file_sidecar_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sidecar_proto_rawDesc), len(file_sidecar_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy maps routes to the permission a caller needs.
+//
+// Example:
+//
+//	rules:
+//	  - methods: [GET]
+//	    path: /v1/organizations/*/members
+//	    permission: members:read
+//	  - path: /v1/api-keys/**
+//	    permission: api_keys:manage
+//	    scope: api_keys:write        # API keys need this scope instead
+//	  - grpc: /customers.APIKeyService/CreateAPIKey
+//	    permission: api_keys:create
+//
+// Rules are evaluated in order; the first match wins. Routes with no matching
+// rule only need authentication, and are open to anonymous callers unless
+// default_deny is set:
+//
+//	default_deny: true
+//	public:
+//	  - path: /v1/auth/**
+//	  - methods: [GET]
+//	    path: /healthz
+//
+// With default_deny, only public routes may be called without credentials.
+//
+// New rules can be rolled out in shadow mode: their denials are logged as
+// decisions and counted in /healthz, but the request is allowed.
+//
+//	rules:
+//	  - path: /v1/billing/**
+//	    permission: billing:read
+//	    shadow: true
+//
+// default_deny_shadow does the same for default-deny.
+type Policy struct {
+	Rules             []*PolicyRule `yaml:"rules"`
+	DefaultDeny       bool          `yaml:"default_deny"`
+	DefaultDenyShadow bool          `yaml:"default_deny_shadow"`
+	Public            []*Route      `yaml:"public"`
+}
+
+// defaultDenyRoute is the decision log route for default-deny denials.
+const defaultDenyRoute = "default_deny"
+
+// Route selects requests by HTTP method and path, or by gRPC method.
+type Route struct {
+	// Methods restricts an HTTP route to these methods; empty means any.
+	Methods []string `yaml:"methods"`
+	// Path is an HTTP path pattern: "*" matches one segment, a trailing "**" the rest.
+	Path string `yaml:"path"`
+	// GRPC is a full gRPC method name, e.g. /customers.UserService/GetUser.
+	GRPC string `yaml:"grpc"`
+}
+
+// PolicyRule is one route → permission mapping.
+type PolicyRule struct {
+	Route `yaml:",inline"`
+	// Permission is the resource:action checked for users.
+	Permission string `yaml:"permission"`
+	// Scope is the API key scope required; defaults to Permission.
+	Scope string `yaml:"scope"`
+	// Shadow logs denials instead of enforcing them.
+	Shadow bool `yaml:"shadow"`
+}
+
+// ParsePolicy parses and validates a YAML policy.
+func ParsePolicy(data []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("cannot parse policy: %w", err)
+	}
+	for i, rule := range policy.Rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		if _, _, ok := splitPermission(rule.Permission); !ok {
+			return nil, fmt.Errorf("rule %d: permission must be resource:action, got %q", i, rule.Permission)
+		}
+		if rule.Scope == "" {
+			rule.Scope = rule.Permission
+		}
+	}
+	for i, route := range policy.Public {
+		if err := route.validate(); err != nil {
+			return nil, fmt.Errorf("public route %d: %w", i, err)
+		}
+	}
+	if len(policy.Public) > 0 && !policy.DefaultDeny {
+		return nil, fmt.Errorf("public routes only apply with default_deny")
+	}
+	if policy.DefaultDenyShadow && !policy.DefaultDeny {
+		return nil, fmt.Errorf("default_deny_shadow only applies with default_deny")
+	}
+	return policy, nil
+}
+
+// Match returns the first rule for the request, or nil.
+// gRPC requests reach ext_authz as POST with the full method name as path.
+func (p *Policy) Match(method, path string, grpcRequest bool) *PolicyRule {
+	if p == nil {
+		return nil
+	}
+	path = normalizePath(path)
+	for _, rule := range p.Rules {
+		if rule.match(method, path, grpcRequest) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// IsPublic reports whether anonymous callers may reach the route.
+func (p *Policy) IsPublic(method, path string, grpcRequest bool) bool {
+	if p == nil || !p.DefaultDeny {
+		return true
+	}
+	path = normalizePath(path)
+	for _, route := range p.Public {
+		if route.match(method, path, grpcRequest) {
+			return true
+		}
+	}
+	return false
+}
+
+// String identifies the route in decision logs, e.g. "GET,HEAD /v1/users/*".
+func (r *Route) String() string {
+	if r.GRPC != "" {
+		return r.GRPC
+	}
+	if len(r.Methods) == 0 {
+		return r.Path
+	}
+	return strings.Join(r.Methods, ",") + " " + r.Path
+}
+
+func (r *Route) validate() error {
+	if (r.Path == "") == (r.GRPC == "") {
+		return fmt.Errorf("exactly one of path or grpc is required")
+	}
+	if r.GRPC != "" && len(r.Methods) > 0 {
+		return fmt.Errorf("methods do not apply to grpc routes")
+	}
+	for j, m := range r.Methods {
+		r.Methods[j] = strings.ToUpper(m)
+	}
+	return nil
+}
+
+func (r *Route) match(method, path string, grpcRequest bool) bool {
+	if r.GRPC != "" {
+		return grpcRequest && r.GRPC == path
+	}
+	if len(r.Methods) > 0 && !slices.Contains(r.Methods, method) {
+		return false
+	}
+	return matchPath(r.Path, path)
+}
+
+// checkPath rejects request paths that upstreams may route differently from
+// the policy: dot segments and encoded slashes (or backslashes), raw or
+// percent-encoded. Such paths are denied rather than normalized, since
+// upstreams route on the raw path.
+func checkPath(raw string) error {
+	if i := strings.IndexByte(raw, '?'); i >= 0 {
+		raw = raw[:i]
+	}
+	lower := strings.ToLower(raw)
+	if strings.Contains(lower, "%2f") || strings.Contains(lower, "%5c") {
+		return fmt.Errorf("encoded slash in path")
+	}
+	for _, seg := range strings.Split(raw, "/") {
+		decoded, err := url.PathUnescape(seg)
+		if err != nil {
+			return fmt.Errorf("invalid percent-encoding in path")
+		}
+		if decoded == "." || decoded == ".." {
+			return fmt.Errorf("dot segment in path")
+		}
+	}
+	return nil
+}
+
+// normalizePath drops the query, decodes percent-encoding and collapses
+// repeated slashes: "//v1/%61pi-keys/" is "/v1/api-keys". Paths with dot
+// segments or encoded slashes are refused by checkPath before matching.
+func normalizePath(raw string) string {
+	if i := strings.IndexByte(raw, '?'); i >= 0 {
+		raw = raw[:i]
+	}
+	if decoded, err := url.PathUnescape(raw); err == nil {
+		raw = decoded
+	}
+	return "/" + strings.Join(strings.FieldsFunc(raw, func(r rune) bool { return r == '/' }), "/")
+}
+
+// matchPath matches a normalized path against a pattern segment by segment.
+func matchPath(pattern, path string) bool {
+	patternSegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range patternSegs {
+		if seg == "**" && i == len(patternSegs)-1 {
+			return true
+		}
+		if i >= len(pathSegs) {
+			return false
+		}
+		if seg != "*" && seg != pathSegs[i] {
+			return false
+		}
+	}
+	return len(patternSegs) == len(pathSegs)
+}
+
+// splitPermission splits "resource:action".
+func splitPermission(permission string) (resource, action string, ok bool) {
+	resource, action, ok = strings.Cut(permission, ":")
+	return resource, action, ok && resource != "" && action != ""
+}
+
+// hasScope reports whether the granted scopes cover the required one,
+// honoring "*" on resource or action like the backend's CheckPermission.
+func hasScope(granted []string, required string) bool {
+	resource, action, _ := splitPermission(required)
+	for _, g := range granted {
+		gr, ga, ok := splitPermission(g)
+		if !ok {
+			continue
+		}
+		if (gr == "*" || gr == resource) && (ga == "*" || ga == action) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyWatcher holds the current policy and reloads it when the file changes.
+// A policy that fails to parse is logged and the previous one is kept.
+type PolicyWatcher struct {
+	path    string
+	current atomic.Pointer[Policy]
+	raw     []byte
+}
+
+// NewPolicyWatcher loads the policy file; it must be valid at startup.
+func NewPolicyWatcher(path string) (*PolicyWatcher, error) {
+	w := &PolicyWatcher{path: path}
+	if err := w.reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Policy returns the current policy.
+func (w *PolicyWatcher) Policy() *Policy {
+	return w.current.Load()
+}
+
+// Run watches the file's directory until ctx is done. Watching the directory
+// catches editors that replace the file and Kubernetes ConfigMap symlink swaps.
+func (w *PolicyWatcher) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("cannot create policy watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		return fmt.Errorf("cannot watch policy directory: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if err := w.reload(); err != nil {
+				log.Printf("WARNING: keeping previous policy: %v", err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("WARNING: policy watcher error: %v", err)
+		}
+	}
+}
+
+// reload re-reads the file and swaps the policy if the content changed.
+func (w *PolicyWatcher) reload() error {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return fmt.Errorf("cannot read policy %s: %w", w.path, err)
+	}
+	if w.current.Load() != nil && bytes.Equal(data, w.raw) {
+		return nil
+	}
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		return err
+	}
+	w.raw = data
+	w.current.Store(policy)
+	log.Printf("authorization policy loaded from %s (%d rules)", w.path, len(policy.Rules))
+	return nil
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	backend "backend/pkg/gen"
+)
+
+const (
+	// apiCallsFeature is the metered feature recorded for every API key request.
+	apiCallsFeature = "api_calls_monthly"
+
+	// DefaultRateLimitRefresh is how long an org's limits are cached.
+	DefaultRateLimitRefresh = time.Minute
+	// DefaultUsageFlushInterval is how often buffered usage is sent to the backend.
+	DefaultUsageFlushInterval = 10 * time.Second
+
+	// The org bucket refills at the monthly allowance spread over the month and
+	// holds up to an hour of it, so bursts are absorbed but a month's quota
+	// can't be burned in minutes.
+	orgBurstWindow = time.Hour
+	orgMinBurst    = 10
+	usagePeriod    = 30 * 24 * time.Hour
+
+	// maxUsageRecordsPerFlush matches RecordUsageRequest's max_items.
+	maxUsageRecordsPerFlush = 1000
+)
+
+// RateLimiter enforces per-key and per-org token buckets for API key traffic,
+// with limits from the org's entitlements:
+//   - api_key_requests_per_minute → one bucket per API key
+//   - api_calls_monthly → one bucket per org, plus a hard stop once the period's quota is used
+//
+// A feature missing from the org's plan (a limit of 0) is not enforced.
+// Allowed calls are buffered and recorded with MeteringService.RecordUsage in batches.
+// If limits can't be fetched, requests are allowed (fail open) and the lookup is retried.
+type RateLimiter struct {
+	metering backend.MeteringServiceClient
+	refresh  time.Duration
+	fetches  singleflight.Group // org ID → in-flight GetRateLimits
+
+	mu      sync.Mutex
+	limits  map[string]*orgLimits   // org ID → limits
+	orgs    map[string]*tokenBucket // org ID → bucket
+	keys    map[string]*tokenBucket // API key ID → bucket
+	pending map[string]int64        // org ID → calls not yet recorded
+}
+
+type orgLimits struct {
+	monthly   int64 // -1 unlimited, 0 not in plan
+	used      int64 // recorded usage at fetch time
+	perMinute int64 // -1 unlimited, 0 not in plan
+	periodEnd time.Time
+	fetchedAt time.Time
+}
+
+// NewRateLimiter creates a limiter that caches each org's limits for refresh.
+func NewRateLimiter(metering backend.MeteringServiceClient, refresh time.Duration) *RateLimiter {
+	return &RateLimiter{
+		metering: metering,
+		refresh:  refresh,
+		limits:   make(map[string]*orgLimits),
+		orgs:     make(map[string]*tokenBucket),
+		keys:     make(map[string]*tokenBucket),
+		pending:  make(map[string]int64),
+	}
+}
+
+// Allow takes one token from the key's and the org's buckets.
+// When denied, retryAfter says when a token will be available.
+func (r *RateLimiter) Allow(ctx context.Context, orgID, keyID string) (allowed bool, retryAfter time.Duration) {
+	limits := r.limitsFor(ctx, orgID)
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limits == nil {
+		r.pending[orgID]++
+		return true, 0
+	}
+
+	// Monthly quota: no refill until the next period
+	if limits.monthly > 0 && limits.used+r.pending[orgID] >= limits.monthly {
+		return false, limits.periodEnd.Sub(now)
+	}
+
+	var buckets []*tokenBucket
+	if limits.perMinute > 0 && keyID != "" {
+		rate := float64(limits.perMinute) / 60
+		buckets = append(buckets, bucketFor(r.keys, keyID, rate, float64(limits.perMinute), now))
+	}
+	if limits.monthly > 0 {
+		rate := float64(limits.monthly) / usagePeriod.Seconds()
+		burst := math.Max(rate*orgBurstWindow.Seconds(), orgMinBurst)
+		buckets = append(buckets, bucketFor(r.orgs, orgID, rate, burst, now))
+	}
+
+	// Only spend tokens if every bucket has one
+	for _, b := range buckets {
+		if wait := b.wait(now); wait > 0 {
+			retryAfter = max(retryAfter, wait)
+		}
+	}
+	if retryAfter > 0 {
+		return false, retryAfter
+	}
+	for _, b := range buckets {
+		b.tokens--
+	}
+	r.pending[orgID]++
+	return true, 0
+}
+
+// limitsFor returns the org's cached limits, fetching them when stale.
+// Concurrent requests for an org share one fetch.
+// On fetch failure the stale value is kept; nil means nothing is known yet.
+func (r *RateLimiter) limitsFor(ctx context.Context, orgID string) *orgLimits {
+	r.mu.Lock()
+	cached := r.limits[orgID]
+	r.mu.Unlock()
+	if cached != nil && time.Since(cached.fetchedAt) < r.refresh {
+		return cached
+	}
+
+	fresh, err, _ := r.fetches.Do(orgID, func() (any, error) {
+		return r.fetchLimits(ctx, orgID)
+	})
+	if err != nil {
+		log.Printf("WARNING: cannot fetch rate limits for org %s: %v", orgID, err)
+		return cached
+	}
+	return fresh.(*orgLimits)
+}
+
+// fetchLimits gets the org's limits from the backend and caches them.
+func (r *RateLimiter) fetchLimits(ctx context.Context, orgID string) (*orgLimits, error) {
+	resp, err := r.metering.GetRateLimits(ctx, &backend.GetRateLimitsRequest{OrgId: orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := &orgLimits{
+		monthly:   resp.ApiCallsMonthly,
+		used:      resp.ApiCallsUsed,
+		perMinute: resp.ApiKeyRequestsPerMinute,
+		periodEnd: resp.PeriodEnd.AsTime(),
+		fetchedAt: time.Now(),
+	}
+	r.mu.Lock()
+	r.limits[orgID] = fresh
+	r.mu.Unlock()
+	return fresh, nil
+}
+
+// Run flushes buffered usage every interval, and once more when ctx is done.
+func (r *RateLimiter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Final flush must not use the cancelled context
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			r.Flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			r.Flush(ctx)
+			r.prune()
+		}
+	}
+}
+
+// Flush sends buffered usage to the backend. On failure the counts are put
+// back and retried on the next flush.
+func (r *RateLimiter) Flush(ctx context.Context) {
+	r.mu.Lock()
+	batch := r.pending
+	r.pending = make(map[string]int64)
+	r.mu.Unlock()
+
+	var records []*backend.UsageRecord
+	for orgID, n := range batch {
+		if n > 0 {
+			records = append(records, &backend.UsageRecord{OrgId: orgID, Feature: apiCallsFeature, Quantity: n})
+		}
+	}
+
+	for start := 0; start < len(records); start += maxUsageRecordsPerFlush {
+		chunk := records[start:min(start+maxUsageRecordsPerFlush, len(records))]
+		if _, err := r.metering.RecordUsage(ctx, &backend.RecordUsageRequest{Records: chunk}); err != nil {
+			log.Printf("WARNING: cannot record API usage (%d orgs), will retry: %v", len(chunk), err)
+			r.requeue(chunk)
+			continue
+		}
+		r.markRecorded(chunk)
+	}
+}
+
+func (r *RateLimiter) requeue(records []*backend.UsageRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rec := range records {
+		r.pending[rec.OrgId] += rec.Quantity
+	}
+}
+
+// markRecorded moves flushed counts into the cached usage so the monthly
+// quota stays accurate until the next limits refresh.
+func (r *RateLimiter) markRecorded(records []*backend.UsageRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rec := range records {
+		if l := r.limits[rec.OrgId]; l != nil {
+			l.used += rec.Quantity
+		}
+	}
+}
+
+// prune drops buckets that are full again, and limits no longer refreshed,
+// so memory tracks active keys and orgs only.
+func (r *RateLimiter) prune() {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, buckets := range []map[string]*tokenBucket{r.keys, r.orgs} {
+		for id, b := range buckets {
+			if b.refill(now); b.tokens >= b.capacity {
+				delete(buckets, id)
+			}
+		}
+	}
+	for id, l := range r.limits {
+		if now.Sub(l.fetchedAt) > 10*r.refresh && r.pending[id] == 0 {
+			delete(r.limits, id)
+		}
+	}
+}
+
+// tokenBucket refills continuously at rate tokens per second up to capacity.
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64
+	last     time.Time
+}
+
+// bucketFor returns the bucket for id, creating a full one if needed.
+// Rate and capacity follow the latest limits.
+func bucketFor(buckets map[string]*tokenBucket, id string, rate, capacity float64, now time.Time) *tokenBucket {
+	b, ok := buckets[id]
+	if !ok {
+		b = &tokenBucket{tokens: capacity, last: now}
+		buckets[id] = b
+	}
+	b.rate = rate
+	b.capacity = capacity
+	return b
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// wait refills the bucket and returns how long until one token is available.
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
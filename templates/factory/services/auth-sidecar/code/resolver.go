@@ -0,0 +1,157 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sidecarpb "auth-sidecar/pkg/gen"
+	backend "backend/pkg/gen"
+)
+
+// Defaults for the identity resolution cache. Unknown identities are cached
+// briefly so a first login shows up quickly.
+const (
+	DefaultResolveCacheSize   = 10000
+	DefaultResolvePositiveTTL = time.Minute
+	DefaultResolveNegativeTTL = 5 * time.Second
+)
+
+// healthOK is the status reported by AuthSidecarService.Health when ready.
+const healthOK = "ok"
+
+// Resolver implements AuthSidecarService for gateways that inject identity
+// headers themselves (Krakend, custom filters) instead of using ext_authz:
+// they extract the provider's user ID and call Resolve.
+type Resolver struct {
+	sidecarpb.UnimplementedAuthSidecarServiceServer
+
+	identity backend.IdentityServiceClient
+	sidecar  *Sidecar
+	cache    *ResolveCache
+}
+
+// NewResolver creates a resolver backed by IdentityService.ResolveIdentity.
+// The sidecar is only used for health reporting.
+func NewResolver(identity backend.IdentityServiceClient, sidecar *Sidecar, cache *ResolveCache) *Resolver {
+	return &Resolver{identity: identity, sidecar: sidecar, cache: cache}
+}
+
+// Resolve maps a provider identity to the internal user, org and roles.
+func (r *Resolver) Resolve(ctx context.Context, req *sidecarpb.ResolveRequest) (*sidecarpb.ResolveResponse, error) {
+	if req.Provider == "" || req.ProviderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "provider and provider_id are required")
+	}
+
+	key := req.Provider + "\x00" + req.ProviderId
+	if r.cache != nil {
+		if resp, ok := r.cache.get(key); ok {
+			return resp, nil
+		}
+	}
+
+	identity, err := r.identity.ResolveIdentity(ctx, &backend.ResolveIdentityRequest{
+		Provider:   req.Provider,
+		ProviderId: req.ProviderId,
+	})
+	if err != nil {
+		// Keep the backend's code (InvalidArgument, Unavailable, ...)
+		return nil, err
+	}
+
+	resp := &sidecarpb.ResolveResponse{
+		Found:  identity.Found,
+		UserId: identity.UserId,
+		OrgId:  identity.OrgId,
+		Roles:  identity.Roles,
+	}
+	if r.cache != nil {
+		r.cache.put(key, resp)
+	}
+	return resp, nil
+}
+
+// Health reports whether the sidecar can serve traffic. It is not ready
+// (Unavailable, HTTP 503) until the backend's JWT keys are loaded. It also
+// reports what shadow-mode routes would have denied.
+func (r *Resolver) Health(_ context.Context, _ *sidecarpb.HealthRequest) (*sidecarpb.HealthResponse, error) {
+	if r.sidecar == nil || r.sidecar.jwtKeys == nil || !r.sidecar.jwtKeys.Ready() {
+		return nil, status.Error(codes.Unavailable, "not ready: no JWT keys loaded")
+	}
+	resp := &sidecarpb.HealthResponse{Status: healthOK}
+	if r.sidecar.decisions != nil {
+		resp.ShadowDenials = r.sidecar.decisions.ShadowDenials()
+	}
+	return resp, nil
+}
+
+// ResolveCache is a bounded LRU of Resolve results with separate TTLs for
+// found and unknown identities.
+type ResolveCache struct {
+	capacity    int
+	positiveTTL time.Duration
+	negativeTTL time.Duration
+
+	mu      sync.Mutex
+	order   *list.List               // front = most recently used
+	entries map[string]*list.Element // provider\x00providerID → element
+}
+
+type resolveCacheEntry struct {
+	key       string
+	resp      *sidecarpb.ResolveResponse
+	expiresAt time.Time
+}
+
+// NewResolveCache creates a cache holding up to capacity identities.
+func NewResolveCache(capacity int, positiveTTL, negativeTTL time.Duration) *ResolveCache {
+	return &ResolveCache{
+		capacity:    capacity,
+		positiveTTL: positiveTTL,
+		negativeTTL: negativeTTL,
+		order:       list.New(),
+		entries:     make(map[string]*list.Element),
+	}
+}
+
+func (c *ResolveCache) get(key string) (*sidecarpb.ResolveResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*resolveCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.resp, true
+}
+
+func (c *ResolveCache) put(key string, resp *sidecarpb.ResolveResponse) {
+	ttl := c.positiveTTL
+	if !resp.Found {
+		ttl = c.negativeTTL
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+	}
+	c.entries[key] = c.order.PushFront(&resolveCacheEntry{key: key, resp: resp, expiresAt: time.Now().Add(ttl)})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Remove(c.order.Back()).(*resolveCacheEntry)
+		delete(c.entries, oldest.key)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keys and channel written by the backend's RevocationList.
+const (
+	revokedTokenPrefix = "revoked:jti:"
+	revokedUserPrefix  = "revoked:user:"
+	revocationChannel  = "token-revocations"
+)
+
+// accessTokenTTL bounds how long a per-user cutoff stays relevant.
+const accessTokenTTL = 15 * time.Minute
+
+// revocationLookupTimeout keeps a slow Redis from stalling every request.
+const revocationLookupTimeout = 100 * time.Millisecond
+
+// revocation mirrors the backend's Revocation pub/sub message.
+type revocation struct {
+	TokenID   string `json:"jti,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+	Before    int64  `json:"before,omitempty"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// RevocationChecker answers "has this access token been revoked?".
+//
+// Redis is the source of truth. A local copy, fed by the revocation channel
+// and by lookups, is used when Redis is unreachable: revocations already seen
+// keep being enforced, unseen ones fail open until Redis is back.
+type RevocationChecker struct {
+	client *redis.Client // nil → local only
+
+	mu       sync.RWMutex
+	tokens   map[string]time.Time  // jti → expiry
+	users    map[string]userCutoff // user ID → cutoff
+	expiries expiryQueue           // entries of both maps, soonest expiry first
+}
+
+type userCutoff struct {
+	before    time.Time
+	expiresAt time.Time
+}
+
+// revocationExpiry is when a jti (or a user's cutoff) may be dropped.
+type revocationExpiry struct {
+	expiresAt time.Time
+	tokenID   string
+	userID    string
+}
+
+// expiryQueue is a min-heap on expiresAt, so expired entries are found
+// without scanning the maps.
+type expiryQueue []revocationExpiry
+
+func (q expiryQueue) Len() int           { return len(q) }
+func (q expiryQueue) Less(i, j int) bool { return q[i].expiresAt.Before(q[j].expiresAt) }
+func (q expiryQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *expiryQueue) Push(x any)        { *q = append(*q, x.(revocationExpiry)) }
+func (q *expiryQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// NewRevocationChecker creates a checker backed by the given Redis client (may be nil).
+func NewRevocationChecker(client *redis.Client) *RevocationChecker {
+	return &RevocationChecker{
+		client: client,
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]userCutoff),
+	}
+}
+
+// Run subscribes to the revocation channel until ctx is done.
+// go-redis re-subscribes on its own after a dropped connection.
+func (r *RevocationChecker) Run(ctx context.Context) {
+	if r.client == nil {
+		return
+	}
+	sub := r.client.Subscribe(ctx, revocationChannel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var rev revocation
+			if err := json.Unmarshal([]byte(msg.Payload), &rev); err != nil {
+				log.Printf("WARNING: malformed revocation message: %v", err)
+				continue
+			}
+			r.apply(rev)
+		}
+	}
+}
+
+// IsRevoked reports whether the token (by jti) or all of the user's tokens issued
+// at or before issuedAt have been revoked.
+func (r *RevocationChecker) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) bool {
+	if r.isRevokedLocally(tokenID, userID, issuedAt) {
+		return true
+	}
+	if r.client == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, revocationLookupTimeout)
+	defer cancel()
+
+	values, err := r.client.MGet(ctx, revokedTokenPrefix+tokenID, revokedUserPrefix+userID).Result()
+	if err != nil {
+		log.Printf("WARNING: revocation lookup failed, using local list: %v", err)
+		return false
+	}
+
+	revoked := false
+	if values[0] != nil && tokenID != "" {
+		r.apply(revocation{TokenID: tokenID, ExpiresAt: time.Now().Add(accessTokenTTL).Unix()})
+		revoked = true
+	}
+	if s, ok := values[1].(string); ok {
+		if before, err := strconv.ParseInt(s, 10, 64); err == nil {
+			r.apply(revocation{UserID: userID, Before: before, ExpiresAt: before + int64(accessTokenTTL/time.Second)})
+			if !issuedAt.After(time.Unix(before, 0)) {
+				revoked = true
+			}
+		}
+	}
+	return revoked
+}
+
+func (r *RevocationChecker) isRevokedLocally(tokenID, userID string, issuedAt time.Time) bool {
+	now := time.Now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if exp, ok := r.tokens[tokenID]; ok && tokenID != "" && now.Before(exp) {
+		return true
+	}
+	if cut, ok := r.users[userID]; ok && now.Before(cut.expiresAt) && !issuedAt.After(cut.before) {
+		return true
+	}
+	return false
+}
+
+func (r *RevocationChecker) apply(rev revocation) {
+	now := time.Now()
+	expiresAt := time.Unix(rev.ExpiresAt, 0)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rev.TokenID != "" {
+		if cur, ok := r.tokens[rev.TokenID]; !ok || expiresAt.After(cur) {
+			r.tokens[rev.TokenID] = expiresAt
+			heap.Push(&r.expiries, revocationExpiry{expiresAt: expiresAt, tokenID: rev.TokenID})
+		}
+	}
+	if rev.UserID != "" {
+		before := time.Unix(rev.Before, 0)
+		// Keep the latest cutoff if several arrive
+		if cur, ok := r.users[rev.UserID]; !ok || before.After(cur.before) {
+			r.users[rev.UserID] = userCutoff{before: before, expiresAt: expiresAt}
+			heap.Push(&r.expiries, revocationExpiry{expiresAt: expiresAt, userID: rev.UserID})
+		}
+	}
+
+	r.prune(now)
+}
+
+// prune drops expired entries so the local list stays bounded by the token
+// TTL. Only the expired head of the queue is visited; a queued expiry that
+// was since replaced by a later one leaves its entry in place.
+func (r *RevocationChecker) prune(now time.Time) {
+	for len(r.expiries) > 0 && now.After(r.expiries[0].expiresAt) {
+		e := heap.Pop(&r.expiries).(revocationExpiry)
+		if e.tokenID != "" {
+			if exp, ok := r.tokens[e.tokenID]; ok && now.After(exp) {
+				delete(r.tokens, e.tokenID)
+			}
+			continue
+		}
+		if cut, ok := r.users[e.userID]; ok && now.After(cut.expiresAt) {
+			delete(r.users, e.userID)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	backend "backend/pkg/gen"
+)
+
+// SecretScanningPath receives leaked-key reports from secret-scanning partners.
+const SecretScanningPath = "/v1/secret-scanning/report"
+
+// Headers of the GitHub secret scanning partner program: the signing key's
+// identifier and the base64 ECDSA (P-256, SHA-256) signature of the body.
+const (
+	secretScanningKeyIDHeader     = "Github-Public-Key-Identifier"
+	secretScanningSignatureHeader = "Github-Public-Key-Signature"
+)
+
+const (
+	maxSecretScanningBody = 1 << 20
+	// secretScanningBatch is ReportLeakedAPIKeysRequest's max_items.
+	secretScanningBatch = 1000
+)
+
+// SecretScanningHandler accepts reports in the GitHub secret scanning partner
+// format:
+//
+//	[{"token": "cfly_k1_live_...", "type": "codefly_api_key", "url": "https://...", "source": "content"}]
+//
+// Reports are only trusted with a valid signature from the configured public
+// key. They are forwarded to APIKeyService.ReportLeakedAPIKeys, which revokes
+// the live keys among them, and the answer labels every token:
+//
+//	[{"token_raw": "cfly_k1_live_...", "token_type": "codefly_api_key", "label": "true_positive"}]
+type SecretScanningHandler struct {
+	apiKey    backend.APIKeyServiceClient
+	publicKey *ecdsa.PublicKey
+	keyID     string // when set, reports must be signed with this key
+}
+
+// NewSecretScanningHandler verifies reports with an ECDSA public key in PEM.
+func NewSecretScanningHandler(apiKey backend.APIKeyServiceClient, publicKeyPEM []byte, keyID string) (*SecretScanningHandler, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM block in secret scanning public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse secret scanning public key: %w", err)
+	}
+	ecKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("secret scanning public key must be ECDSA, got %T", key)
+	}
+	return &SecretScanningHandler{apiKey: apiKey, publicKey: ecKey, keyID: keyID}, nil
+}
+
+// LoadSecretScanningHandler reads the public key from a PEM file.
+func LoadSecretScanningHandler(apiKey backend.APIKeyServiceClient, path, keyID string) (*SecretScanningHandler, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read secret scanning public key %s: %w", path, err)
+	}
+	return NewSecretScanningHandler(apiKey, data, keyID)
+}
+
+type leakedTokenReport struct {
+	Token  string `json:"token"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	Source string `json:"source"`
+}
+
+type leakedTokenLabel struct {
+	TokenRaw  string `json:"token_raw"`
+	TokenType string `json:"token_type"`
+	Label     string `json:"label"` // true_positive or false_positive
+}
+
+// ServeHTTP implements http.Handler.
+func (h *SecretScanningHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSecretScanningBody+1))
+	if err != nil {
+		http.Error(w, "cannot read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxSecretScanningBody {
+		http.Error(w, "report too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := h.verify(r.Header, body); err != nil {
+		log.Printf("WARNING: rejected secret scanning report: %v", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var reports []leakedTokenReport
+	if err := json.Unmarshal(body, &reports); err != nil {
+		http.Error(w, "invalid report", http.StatusBadRequest)
+		return
+	}
+
+	labels := make([]leakedTokenLabel, 0, len(reports))
+	for start := 0; start < len(reports); start += secretScanningBatch {
+		batch := reports[start:min(start+secretScanningBatch, len(reports))]
+		req := &backend.ReportLeakedAPIKeysRequest{}
+		for _, report := range batch {
+			req.Reports = append(req.Reports, &backend.LeakedAPIKeyReport{
+				Token: report.Token, Type: report.Type, Url: report.URL, Source: report.Source,
+			})
+		}
+		resp, err := h.apiKey.ReportLeakedAPIKeys(r.Context(), req)
+		if err != nil {
+			log.Printf("ERROR reporting leaked API keys: %v", err)
+			http.Error(w, "cannot process report", http.StatusBadGateway)
+			return
+		}
+		for i, report := range batch {
+			label := "false_positive"
+			if i < len(resp.Results) && resp.Results[i].TruePositive {
+				label = "true_positive"
+			}
+			labels = append(labels, leakedTokenLabel{TokenRaw: report.Token, TokenType: report.Type, Label: label})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(labels)
+}
+
+// verify checks the body's signature against the configured key.
+func (h *SecretScanningHandler) verify(header http.Header, body []byte) error {
+	if h.keyID != "" && header.Get(secretScanningKeyIDHeader) != h.keyID {
+		return fmt.Errorf("unknown key identifier %q", header.Get(secretScanningKeyIDHeader))
+	}
+	signature, err := base64.StdEncoding.DecodeString(header.Get(secretScanningSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return errors.New("missing or malformed signature")
+	}
+	digest := sha256.Sum256(body)
+	if !ecdsa.VerifyASN1(h.publicKey, digest[:], signature) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
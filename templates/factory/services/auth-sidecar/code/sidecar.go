@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
-	"crypto/ed25519"
 	"fmt"
 	"log"
+	"math"
+	"net/netip"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 
@@ -15,111 +19,530 @@ import (
 	"google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 
+	sidecarpb "auth-sidecar/pkg/gen"
 	backend "backend/pkg/gen"
 )
 
+// backendIssuer is the iss claim of the backend's access tokens (infra.TokenIssuer).
+const backendIssuer = "codefly-user-mgmt"
+
+// identityHeaders are set by the sidecar only. Whatever the client sent is
+// overwritten or removed on every allowed request, so upstreams can trust them.
+// x-actor-type and x-actor-id name who acts: the user, the API key, the
+// service account (a client credentials token), or the admin impersonating
+// the user.
+var identityHeaders = []string{"x-user-id", "x-org-id", "x-roles", "x-scopes", "x-actor-type", "x-actor-id"}
+
+// Response headers sent to clients whose API key was rotated and is in its
+// grace period: the warning and when the key stops working (RFC 3339).
+const (
+	apiKeyWarningHeader  = "x-api-key-warning"
+	apiKeyRevokeAtHeader = "x-api-key-revoke-at"
+)
+
 // AccessClaims mirrors the JWT claims from the backend's TokenService.
 type AccessClaims struct {
 	jwt.RegisteredClaims
 	OrgID string   `json:"org,omitempty"`
 	Roles []string `json:"roles,omitempty"`
+	// Act is set on tokens issued to an admin impersonating the subject (RFC 8693)
+	Act *ActorClaim `json:"act,omitempty"`
+}
+
+// ActorClaim is the party acting on behalf of a token's subject.
+type ActorClaim struct {
+	Subject string `json:"sub"`
 }
 
 // Sidecar implements envoy ext_authz with two auth paths:
-//  1. JWT (local Ed25519 validation, no network call)
-//  2. API key (cfly_sk_ prefix, validated via backend RPC)
+//  1. JWT (local EdDSA/ES256/RS256 validation, no network call), issued by
+//     the backend or by a trusted third-party issuer
+//  2. API key (cfly_k1_ or legacy cfly_sk_ prefix, validated via backend RPC)
+//
+// When a route policy is set, authenticated callers are then authorized
+// against it: users via CheckPermission, API keys via their scopes.
 type Sidecar struct {
-	apiKey    backend.APIKeyServiceClient
-	publicKey ed25519.PublicKey
+	apiKey      backend.APIKeyServiceClient
+	permissions backend.PermissionServiceClient
+	jwtKeys     *KeySet
+	policy      *PolicyWatcher
+	revocations *RevocationChecker
+	apiKeyCache *APIKeyCache
+	rateLimiter *RateLimiter
+	issuers     *TrustedIssuers
+	resolver    *Resolver
+	decisions   *DecisionLogger
+
+	trustedProxies []netip.Prefix
 }
 
 // NewSidecar creates a sidecar with JWT and API key validation.
-func NewSidecar(backendConn *grpc.ClientConn, publicKey ed25519.PublicKey) *Sidecar {
+// jwtKeys holds the backend's verification keys (nil disables JWT validation).
+func NewSidecar(backendConn *grpc.ClientConn, jwtKeys *KeySet) *Sidecar {
 	return &Sidecar{
-		apiKey:    backend.NewAPIKeyServiceClient(backendConn),
-		publicKey: publicKey,
+		apiKey:      backend.NewAPIKeyServiceClient(backendConn),
+		permissions: backend.NewPermissionServiceClient(backendConn),
+		jwtKeys:     jwtKeys,
 	}
 }
 
+// SetPolicy enables route-level authorization.
+func (s *Sidecar) SetPolicy(p *PolicyWatcher) {
+	s.policy = p
+}
+
+// SetRevocations enables the access token denylist check.
+func (s *Sidecar) SetRevocations(r *RevocationChecker) {
+	s.revocations = r
+}
+
+// SetAPIKeyCache enables caching of API key validation results.
+func (s *Sidecar) SetAPIKeyCache(c *APIKeyCache) {
+	s.apiKeyCache = c
+}
+
+// SetRateLimiter enables per-key and per-org rate limiting of API key requests.
+func (s *Sidecar) SetRateLimiter(r *RateLimiter) {
+	s.rateLimiter = r
+}
+
+// SetTrustedIssuers accepts tokens from third-party issuers. Their subjects
+// are mapped to internal users through the resolver.
+func (s *Sidecar) SetTrustedIssuers(issuers *TrustedIssuers, resolver *Resolver) {
+	s.issuers = issuers
+	s.resolver = resolver
+}
+
+// SetTrustedProxies sets the proxies whose X-Real-IP forward-auth believes.
+func (s *Sidecar) SetTrustedProxies(prefixes []netip.Prefix) {
+	s.trustedProxies = prefixes
+}
+
+// SetDecisionLogger enables decision logging.
+func (s *Sidecar) SetDecisionLogger(l *DecisionLogger) {
+	s.decisions = l
+}
+
 // Check implements envoy.service.auth.v3.Authorization.
 //
 // Auth paths:
 //  1. Authorization: Bearer <jwt> → local JWT validation (fast, no network)
-//  2. Authorization: Bearer cfly_sk_... → API key validation (backend RPC)
-//  3. No auth → pass through (public endpoint)
+//  2. Authorization: Bearer cfly_k1_... (or cfly_sk_...) → API key validation
+//     (backend RPC; malformed keys are rejected locally)
+//  3. No auth → pass through (public endpoint), unless the policy covers the
+//     route or denies by default and the route isn't public
+//
+// Identity headers from the client are never passed upstream (see allow).
+// With a policy, paths with dot segments or encoded slashes are refused (400).
+// Policy denials on shadow-mode routes are logged but not enforced.
 func (s *Sidecar) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
-	headers := req.GetAttributes().GetRequest().GetHttp().GetHeaders()
+	start := time.Now()
+	httpReq := req.GetAttributes().GetRequest().GetHttp()
+	rule := s.matchRule(httpReq)
+
+	d := &Decision{
+		Time: start, AuthPath: authPathAnonymous, ClientIP: clientIP(req),
+		Method: httpReq.GetMethod(), Path: httpReq.GetPath(),
+	}
+	if rule != nil {
+		d.Route = rule.String()
+	}
+
+	resp, err := s.check(ctx, httpReq, rule, d)
+	s.logDecision(d, resp, start)
+	return resp, err
+}
 
-	if auth := headers["authorization"]; auth != "" {
+func (s *Sidecar) check(ctx context.Context, httpReq *authv3.AttributeContext_HttpRequest, rule *PolicyRule, d *Decision) (*authv3.CheckResponse, error) {
+	if s.policy != nil {
+		if err := checkPath(httpReq.GetPath()); err != nil {
+			return deny(400, err.Error()), nil
+		}
+	}
+
+	if auth := httpReq.GetHeaders()["authorization"]; auth != "" {
 		token := strings.TrimPrefix(auth, "Bearer ")
 		if token != auth { // had "Bearer " prefix
-			if strings.HasPrefix(token, "cfly_sk_") {
-				return s.checkAPIKey(ctx, token)
+			if isAPIKey(token) {
+				return s.checkAPIKey(ctx, token, requestOrigin(httpReq), rule, d)
 			}
-			return s.checkJWT(token)
+			return s.checkJWT(ctx, token, rule, d)
 		}
 	}
 
-	// No auth → pass through (public endpoint)
+	if rule != nil {
+		if denied := enforce(d, rule.Shadow, deny(401, "authentication required")); denied != nil {
+			return denied, nil
+		}
+	} else if !s.isPublic(httpReq) {
+		d.Route = defaultDenyRoute
+		if denied := enforce(d, s.policy.Policy().DefaultDenyShadow, deny(401, "authentication required")); denied != nil {
+			return denied, nil
+		}
+	}
+
+	// No auth → pass through (public endpoint), without any identity
 	return allow(nil), nil
 }
 
-// checkJWT validates a JWT locally using the Ed25519 public key.
-// No network call — just crypto verification.
-func (s *Sidecar) checkJWT(tokenString string) (*authv3.CheckResponse, error) {
-	if s.publicKey == nil {
+// enforce returns the denial (nil if none), or nil when the route is in shadow
+// mode; the shadowed denial is kept on the decision for logging.
+func enforce(d *Decision, shadow bool, denial *authv3.CheckResponse) *authv3.CheckResponse {
+	if denial == nil || !shadow {
+		return denial
+	}
+	d.Shadow = true
+	d.ShadowStatus = int(denial.GetDeniedResponse().GetStatus().GetCode())
+	d.ShadowReason = denial.GetDeniedResponse().GetBody()
+	return nil
+}
+
+// logDecision completes the decision from the response and logs it.
+func (s *Sidecar) logDecision(d *Decision, resp *authv3.CheckResponse, start time.Time) {
+	if s.decisions == nil || resp == nil {
+		return
+	}
+	d.Duration = time.Since(start).Microseconds()
+	if denied := resp.GetDeniedResponse(); denied != nil {
+		d.Status = int(denied.GetStatus().GetCode())
+		d.Reason = denied.GetBody()
+	} else {
+		d.Allowed = true
+		d.Status = 200
+	}
+	s.decisions.Log(d)
+}
+
+// matchRule returns the policy rule covering the request, if any.
+func (s *Sidecar) matchRule(httpReq *authv3.AttributeContext_HttpRequest) *PolicyRule {
+	if s.policy == nil {
+		return nil
+	}
+	grpcRequest := strings.HasPrefix(httpReq.GetHeaders()["content-type"], "application/grpc")
+	return s.policy.Policy().Match(httpReq.GetMethod(), httpReq.GetPath(), grpcRequest)
+}
+
+// isPublic reports whether anonymous requests may reach the route.
+// Everything is public unless the policy denies by default.
+func (s *Sidecar) isPublic(httpReq *authv3.AttributeContext_HttpRequest) bool {
+	if s.policy == nil {
+		return true
+	}
+	grpcRequest := strings.HasPrefix(httpReq.GetHeaders()["content-type"], "application/grpc")
+	return s.policy.Policy().IsPublic(httpReq.GetMethod(), httpReq.GetPath(), grpcRequest)
+}
+
+// checkJWT validates a JWT locally using the backend's public keys, matched by kid.
+// Only the matched key's own algorithm is accepted, so a token can't switch alg.
+// Crypto verification is local; the denylist lookup is the only network call,
+// plus a rate-limited JWKS refetch when a token names an unknown kid.
+func (s *Sidecar) checkJWT(ctx context.Context, tokenString string, rule *PolicyRule, d *Decision) (*authv3.CheckResponse, error) {
+	if issuer := s.issuers.lookup(unverifiedIssuer(tokenString)); issuer != nil {
+		return s.checkExternalJWT(ctx, issuer, tokenString, rule, d)
+	}
+	d.AuthPath = authPathJWT
+	if s.jwtKeys == nil {
 		return deny(500, "JWT validation not configured"), nil
 	}
+	if !s.jwtKeys.Ready() {
+		return deny(503, "JWT keys not loaded yet"), nil
+	}
 
 	claims := &AccessClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, err := s.jwtKeys.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Algorithm {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return s.publicKey, nil
+		return key.PublicKey, nil
 	},
-		jwt.WithValidMethods([]string{"EdDSA"}),
+		jwt.WithValidMethods(s.jwtKeys.Algorithms()),
 		jwt.WithExpirationRequired(),
 	)
 	if err != nil || !token.Valid {
 		return deny(401, "invalid or expired token"), nil
 	}
+	d.Principal, d.UserID, d.OrgID = claims.Subject, claims.Subject, claims.OrgID
 
+	if s.revocations != nil {
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		if s.revocations.IsRevoked(ctx, claims.ID, claims.Subject, issuedAt) {
+			return deny(401, "token revoked"), nil
+		}
+	}
+
+	if rule != nil {
+		if denied := enforce(d, rule.Shadow, s.authorizeUser(ctx, rule, claims.Subject, claims.OrgID)); denied != nil {
+			return denied, nil
+		}
+	}
+
+	actorType, actorID := "user", claims.Subject
+	if claims.Act != nil && claims.Act.Subject != "" {
+		actorType, actorID = "impersonator", claims.Act.Subject
+	}
 	return allow([]*corev3.HeaderValueOption{
 		hdr("x-user-id", claims.Subject),
 		hdr("x-org-id", claims.OrgID),
 		hdr("x-roles", strings.Join(claims.Roles, ",")),
+		hdr("x-actor-type", actorType),
+		hdr("x-actor-id", actorID),
 	}), nil
 }
 
-// checkAPIKey validates an API key by calling the backend.
-func (s *Sidecar) checkAPIKey(ctx context.Context, key string) (*authv3.CheckResponse, error) {
-	resp, err := s.apiKey.ValidateAPIKey(ctx, &backend.ValidateAPIKeyRequest{
-		KeyHash: key,
-	})
+// checkExternalJWT validates a third-party token against its issuer's JWKS
+// and maps its subject to an internal user.
+func (s *Sidecar) checkExternalJWT(ctx context.Context, issuer *trustedIssuer, tokenString string, rule *PolicyRule, d *Decision) (*authv3.CheckResponse, error) {
+	d.AuthPath = authPathExternalJWT
+	config := issuer.config
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := issuer.jwks.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key.PublicKey, nil
+	},
+		jwt.WithValidMethods(config.Algorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(config.Issuer),
+		jwt.WithAudience(config.Audience...),
+	)
+	if err != nil || !token.Valid {
+		return deny(401, "invalid or expired token"), nil
+	}
+
+	subject, _ := claims[config.Claims.Subject].(string)
+	if subject == "" {
+		return deny(401, fmt.Sprintf("token has no %s claim", config.Claims.Subject)), nil
+	}
+
+	if s.resolver == nil {
+		return deny(500, "identity resolution not configured"), nil
+	}
+	identity, err := s.resolver.Resolve(ctx, &sidecarpb.ResolveRequest{Provider: config.Provider, ProviderId: subject})
+	if err != nil {
+		log.Printf("ERROR resolving %s identity %s: %v", config.Provider, subject, err)
+		return deny(500, "identity resolution failed"), nil
+	}
+	if !identity.Found {
+		return deny(401, "unknown user"), nil
+	}
+	d.Principal, d.UserID, d.OrgID = identity.UserId, identity.UserId, identity.OrgId
+
+	// Per-user cutoffs (logout everywhere, refresh token reuse) apply to external tokens too
+	if s.revocations != nil {
+		var issuedAt time.Time
+		if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+			issuedAt = iat.Time
+		}
+		if s.revocations.IsRevoked(ctx, "", identity.UserId, issuedAt) {
+			return deny(401, "token revoked"), nil
+		}
+	}
+
+	if rule != nil {
+		if denied := enforce(d, rule.Shadow, s.authorizeUser(ctx, rule, identity.UserId, identity.OrgId)); denied != nil {
+			return denied, nil
+		}
+	}
+
+	actorType := "user"
+	if serviceAccountToken(claims) {
+		actorType = "service_account"
+	}
+	return allow([]*corev3.HeaderValueOption{
+		hdr("x-user-id", identity.UserId),
+		hdr("x-org-id", identity.OrgId),
+		hdr("x-roles", strings.Join(identity.Roles, ",")),
+		hdr("x-actor-type", actorType),
+		hdr("x-actor-id", identity.UserId),
+	}), nil
+}
+
+// serviceAccountToken reports whether an external token was issued to a
+// client on its own behalf (client credentials grant) rather than to a user:
+// RFC 9068 then sets sub to the client ID, in client_id (or azp).
+func serviceAccountToken(claims jwt.MapClaims) bool {
+	subject, _ := claims["sub"].(string)
+	clientID, _ := claims["client_id"].(string)
+	if clientID == "" {
+		clientID, _ = claims["azp"].(string)
+	}
+	return subject != "" && subject == clientID
+}
+
+// unverifiedIssuer reads the iss claim without verifying the token, only to
+// pick the key set to verify it with.
+func unverifiedIssuer(tokenString string) string {
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, &claims); err != nil {
+		return ""
+	}
+	return claims.Issuer
+}
+
+// checkAPIKey validates an API key by calling the backend, through the cache when enabled.
+func (s *Sidecar) checkAPIKey(ctx context.Context, key, origin string, rule *PolicyRule, d *Decision) (*authv3.CheckResponse, error) {
+	d.AuthPath = authPathAPIKey
+	if !apiKeyWellFormed(key) {
+		return deny(401, "malformed API key"), nil
+	}
+	resp, err := s.validateAPIKey(ctx, key, d.ClientIP, origin)
 	if err != nil {
 		log.Printf("ERROR validating API key: %v", err)
 		return deny(500, "API key validation failed"), nil
 	}
 	if !resp.Valid {
+		switch resp.Reason {
+		case backend.APIKeyInvalidReason_API_KEY_INVALID_REASON_IP_NOT_ALLOWED:
+			return deny(403, "API key not allowed from this IP address"), nil
+		case backend.APIKeyInvalidReason_API_KEY_INVALID_REASON_ORIGIN_NOT_ALLOWED:
+			return deny(403, "API key not allowed from this origin"), nil
+		}
 		return deny(401, "invalid API key"), nil
 	}
+	d.Principal, d.UserID, d.OrgID = resp.KeyId, resp.UserId, resp.OrganizationId
 
-	return allow([]*corev3.HeaderValueOption{
+	if rule != nil && !hasScope(resp.Scopes, rule.Scope) {
+		if denied := enforce(d, rule.Shadow, deny(403, fmt.Sprintf("API key lacks scope %s", rule.Scope))); denied != nil {
+			return denied, nil
+		}
+	}
+	if s.rateLimiter != nil {
+		if ok, retryAfter := s.rateLimiter.Allow(ctx, resp.OrganizationId, resp.KeyId); !ok {
+			return tooManyRequests(retryAfter), nil
+		}
+	}
+
+	allowed := allow([]*corev3.HeaderValueOption{
 		hdr("x-user-id", resp.UserId),
 		hdr("x-org-id", resp.OrganizationId),
 		hdr("x-scopes", strings.Join(resp.Scopes, ",")),
-	}), nil
+		hdr("x-actor-type", "api_key"),
+		hdr("x-actor-id", resp.KeyId),
+	})
+	if resp.Deprecated {
+		// Tell the client, on the response, that its key is going away
+		ok := allowed.GetOkResponse()
+		ok.ResponseHeadersToAdd = append(ok.ResponseHeadersToAdd,
+			hdr(apiKeyWarningHeader, resp.Warning),
+			hdr(apiKeyRevokeAtHeader, resp.RevokeAt.AsTime().UTC().Format(time.RFC3339)))
+	}
+	return allowed, nil
+}
+
+// authorizeUser checks the rule's permission for a user via the backend.
+// Returns nil when allowed, else the denial to send.
+func (s *Sidecar) authorizeUser(ctx context.Context, rule *PolicyRule, userID, orgID string) *authv3.CheckResponse {
+	resource, action, _ := splitPermission(rule.Permission)
+	resp, err := s.permissions.CheckPermission(ctx, &backend.CheckPermissionRequest{
+		SubjectId:   userID,
+		SubjectKind: backend.SubjectKind_SUBJECT_KIND_USER,
+		Resource:    resource,
+		Action:      action,
+		OrgId:       orgID,
+	})
+	if err != nil {
+		log.Printf("ERROR checking permission %s for %s: %v", rule.Permission, userID, err)
+		return deny(500, "authorization check failed")
+	}
+	if !resp.Allowed {
+		reason := fmt.Sprintf("missing permission %s", rule.Permission)
+		if resp.Reason != "" {
+			reason += ": " + resp.Reason
+		}
+		return deny(403, reason)
+	}
+	return nil
+}
+
+// validateAPIKey returns the cached result if any, else asks the backend,
+// forwarding the client IP and origin for the key's restrictions and usage
+// tracking. A cached valid key used from outside its allowlists goes to the
+// backend, which audits the violation. Backend errors and violations (they
+// depend on the request, not the key) are not cached.
+func (s *Sidecar) validateAPIKey(ctx context.Context, key, ip, origin string) (*backend.ValidateAPIKeyResponse, error) {
+	var hash string
+	if s.apiKeyCache != nil {
+		hash = apiKeyCacheKey(key)
+		if resp, ok := s.apiKeyCache.Get(ctx, hash); ok && (!resp.Valid || apiKeyRestrictionsAllow(resp, ip, origin)) {
+			return resp, nil
+		}
+	}
+
+	if ip != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-forwarded-for", ip)
+	}
+	resp, err := s.apiKey.ValidateAPIKey(ctx, &backend.ValidateAPIKeyRequest{
+		KeyHash: key,
+		Origin:  origin,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.apiKeyCache != nil && !isRestrictionViolation(resp.Reason) {
+		s.apiKeyCache.Put(ctx, hash, resp)
+	}
+	return resp, nil
+}
+
+// requestOrigin returns the Origin header, else the Referer, for API keys
+// restricted to some origins.
+func requestOrigin(httpReq *authv3.AttributeContext_HttpRequest) string {
+	headers := httpReq.GetHeaders()
+	if origin := headers["origin"]; origin != "" && origin != "null" {
+		return origin
+	}
+	return headers["referer"]
 }
 
 // --- helpers ---
 
+// clientIP returns the downstream client's address: the source of the check
+// request. With use_remote_address, envoy sets it to the real client; no
+// request header is believed, as clients can send any.
+func clientIP(req *authv3.CheckRequest) string {
+	return req.GetAttributes().GetSource().GetAddress().GetSocketAddress().GetAddress()
+}
+
+// allow overwrites the identity headers given and removes every other one,
+// so a client can't smuggle its own. Empty values are removed too.
 func allow(headers []*corev3.HeaderValueOption) *authv3.CheckResponse {
+	var set []*corev3.HeaderValueOption
+	var remove []string
+	for _, name := range identityHeaders {
+		i := slices.IndexFunc(headers, func(h *corev3.HeaderValueOption) bool { return h.GetHeader().GetKey() == name })
+		if i < 0 || headers[i].GetHeader().GetValue() == "" {
+			remove = append(remove, name)
+		}
+	}
+	for _, h := range headers {
+		if h.GetHeader().GetValue() == "" {
+			continue
+		}
+		h.AppendAction = corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD
+		set = append(set, h)
+	}
+
 	return &authv3.CheckResponse{
 		Status: &status.Status{Code: int32(codes.OK)},
 		HttpResponse: &authv3.CheckResponse_OkResponse{
-			OkResponse: &authv3.OkHttpResponse{Headers: headers},
+			OkResponse: &authv3.OkHttpResponse{Headers: set, HeadersToRemove: remove},
 		},
 	}
 }
@@ -136,6 +559,16 @@ func deny(httpCode int, body string) *authv3.CheckResponse {
 	}
 }
 
+// tooManyRequests is a 429 with Retry-After in whole seconds (at least 1).
+func tooManyRequests(retryAfter time.Duration) *authv3.CheckResponse {
+	resp := deny(429, "rate limit exceeded")
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	resp.GetDeniedResponse().Headers = []*corev3.HeaderValueOption{
+		hdr("retry-after", strconv.FormatInt(max(seconds, 1), 10)),
+	}
+	return resp
+}
+
 func hdr(key, value string) *corev3.HeaderValueOption {
 	return &corev3.HeaderValueOption{
 		Header: &corev3.HeaderValue{Key: key, Value: value},
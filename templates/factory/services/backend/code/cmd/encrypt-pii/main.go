@@ -0,0 +1,75 @@
+// Command encrypt-pii encrypts, in place, the PII stored in plaintext before
+// field-level encryption (store migration 18) and fills its blind indexes.
+// It reads the backend's store and secrets configuration, works in small
+// transactions and can run, or be re-run, while the backend is serving.
+//
+//	go run ./cmd/encrypt-pii -batch-size 500
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	codefly "github.com/codefly-dev/sdk-go"
+
+	"backend/pkg/infra"
+)
+
+func main() {
+	batchSize := flag.Int("batch-size", 500, "rows encrypted per transaction")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, *batchSize); err != nil {
+		fmt.Fprintf(os.Stderr, "encrypt-pii: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, batchSize int) error {
+	if batchSize < 1 {
+		return fmt.Errorf("batch size must be positive")
+	}
+
+	provider, err := codefly.Init(ctx)
+	if err != nil {
+		return err
+	}
+	ctx = provider.Inject(ctx)
+
+	store, err := infra.NewPostgresStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	secrets, err := infra.NewSecretsProvider(ctx)
+	if err != nil {
+		return err
+	}
+	defer secrets.Close()
+
+	cipher, err := secrets.PIICipher(ctx)
+	if err != nil {
+		return err
+	}
+	store.SetPIICipher(cipher)
+
+	counts, err := store.EncryptPII(ctx, batchSize)
+	columns := make([]string, 0, len(counts))
+	for column := range counts {
+		columns = append(columns, column)
+	}
+	slices.Sort(columns)
+	for _, column := range columns {
+		fmt.Printf("%s: %d encrypted\n", column, counts[column])
+	}
+	return err
+}
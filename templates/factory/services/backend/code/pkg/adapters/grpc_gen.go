@@ -81,6 +81,11 @@ type APIKeyServer struct {
 	gen.UnsafeAPIKeyServiceServer
 }
 
+// MeteringServer handles MeteringService RPCs.
+type MeteringServer struct {
+	gen.UnsafeMeteringServiceServer
+}
+
 // AuthServer handles AuthService RPCs.
 type AuthServer struct {
 	gen.UnsafeAuthServiceServer
@@ -109,6 +114,7 @@ type GrpcServer struct {
 	Perm   *PermServer
 	Ident  *IdentServer
 	APIKey *APIKeyServer
+	Metering *MeteringServer
 	Auth   *AuthServer
 	Audit      *AuditServer
 	Invitation *InvitationServer
@@ -129,7 +135,7 @@ func (s *UserServer) Version(ctx context.Context, req *gen.VersionRequest) (*gen
 }
 
 func NewGrpServer(c *Configuration) (*GrpcServer, error) {
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(serverOptions()...)
 	v, err := protovalidate.New()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create validator: %w", err)
@@ -142,6 +148,7 @@ func NewGrpServer(c *Configuration) (*GrpcServer, error) {
 		Perm:          &PermServer{},
 		Ident:         &IdentServer{},
 		APIKey:        &APIKeyServer{},
+		Metering:      &MeteringServer{},
 		Auth:          &AuthServer{},
 		Audit:         &AuditServer{},
 		Invitation:    &InvitationServer{},
@@ -157,6 +164,7 @@ func NewGrpServer(c *Configuration) (*GrpcServer, error) {
 	gen.RegisterPermissionServiceServer(grpcServer, s.Perm)
 	gen.RegisterIdentityServiceServer(grpcServer, s.Ident)
 	gen.RegisterAPIKeyServiceServer(grpcServer, s.APIKey)
+	gen.RegisterMeteringServiceServer(grpcServer, s.Metering)
 	gen.RegisterAuthServiceServer(grpcServer, s.Auth)
 	gen.RegisterAuditServiceServer(grpcServer, s.Audit)
 	gen.RegisterInvitationServiceServer(grpcServer, s.Invitation)
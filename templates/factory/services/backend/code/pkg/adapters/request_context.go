@@ -0,0 +1,199 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"backend/pkg/business"
+)
+
+// serverOptions configure the gRPC server: every call gets its request
+// context, for audit events.
+func serverOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(requestContextUnaryInterceptor),
+		grpc.ChainStreamInterceptor(requestContextStreamInterceptor),
+	}
+}
+
+func requestContextUnaryInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(business.WithRequestContext(ctx, requestContext(ctx)), req)
+}
+
+func requestContextStreamInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := business.WithRequestContext(ss.Context(), requestContext(ss.Context()))
+	return handler(srv, &requestContextStream{ServerStream: ss, ctx: ctx})
+}
+
+type requestContextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestContextStream) Context() context.Context {
+	return s.ctx
+}
+
+// forwardedHeaders are the HTTP headers the gateway passes on as metadata, for
+// requestContext. The gateway itself sets x-forwarded-for and
+// grpcgateway-user-agent.
+var forwardedHeaders = []string{"x-user-id", "x-actor-type", "x-actor-id", "traceparent", "x-b3-traceid", "x-request-id"}
+
+func forwardRequestHeaders(md metadata.MD, req *http.Request) metadata.MD {
+	for _, name := range forwardedHeaders {
+		if value := req.Header.Get(name); value != "" {
+			md.Set(name, value)
+		}
+	}
+	return md
+}
+
+// actorTypes are the x-actor-type values the auth sidecar sets.
+var actorTypes = []string{
+	business.ActorUser, business.ActorAPIKey, business.ActorServiceAccount, business.ActorImpersonator,
+}
+
+// requestContext extracts who is calling from the identity metadata set by
+// the auth sidecar (x-user-id, x-actor-type, x-actor-id), and from where
+// from the metadata set by the proxy and the gateway. IDs that are not UUIDs
+// are dropped, as audit events could not record them.
+func requestContext(ctx context.Context) business.RequestContext {
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+		return ""
+	}
+
+	rc := business.RequestContext{
+		UserID:    get("x-user-id"),
+		ClientIP:  clientIP(ctx),
+		UserAgent: get("grpcgateway-user-agent"),
+		TraceID:   traceID(get("traceparent"), get("x-b3-traceid")),
+		RequestID: get("x-request-id"),
+	}
+	if rc.UserAgent == "" {
+		rc.UserAgent = get("user-agent")
+	}
+	if uuid.Validate(rc.UserID) != nil {
+		rc.UserID = ""
+	}
+
+	actorType, actorID := get("x-actor-type"), get("x-actor-id")
+	if actorType == "" && rc.UserID != "" {
+		actorType, actorID = business.ActorUser, rc.UserID
+	}
+	if uuid.Validate(actorID) == nil && slices.Contains(actorTypes, actorType) {
+		rc.ActorType, rc.ActorID = actorType, actorID
+	}
+	return rc
+}
+
+// trustedProxies are the peers whose x-forwarded-for is believed: loopback,
+// where the REST gateway calls from, and those given to WithTrustedProxies
+// (the auth sidecar, the ingress).
+var trustedProxies = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+
+// WithTrustedProxies adds proxies whose forwarded client addresses are believed.
+func WithTrustedProxies(prefixes []netip.Prefix) {
+	trustedProxies = append(trustedProxies, prefixes...)
+}
+
+// ParseTrustedProxies parses a comma-separated list of CIDRs or IPs.
+func ParseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func trustedProxy(ip netip.Addr) bool {
+	return slices.ContainsFunc(trustedProxies, func(p netip.Prefix) bool { return p.Contains(ip) })
+}
+
+// clientIP is the address of the client as seen by the first trusted proxy.
+// Starting from the peer, it walks x-forwarded-for right to left while the hop
+// is a trusted proxy: entries further left were written by the client and can
+// be anything. Without a peer nothing vouches for the metadata.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	var hops []string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get("x-forwarded-for") {
+			hops = append(hops, strings.Split(value, ",")...)
+		}
+	}
+	hops = append(hops, p.Addr.String())
+
+	var client netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, ok := parseHop(hops[i])
+		if !ok {
+			break
+		}
+		client = ip
+		if !trustedProxy(ip) {
+			break
+		}
+	}
+	if !client.IsValid() {
+		return ""
+	}
+	return client.String()
+}
+
+// parseHop parses an address with or without a port.
+func parseHop(hop string) (netip.Addr, bool) {
+	hop = strings.TrimSpace(hop)
+	if ip, err := netip.ParseAddr(hop); err == nil {
+		return ip.Unmap(), true
+	}
+	if host, _, err := net.SplitHostPort(hop); err == nil {
+		if ip, err := netip.ParseAddr(host); err == nil {
+			return ip.Unmap(), true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// traceID reads the trace ID of a W3C traceparent (version-traceid-spanid-flags),
+// else of a B3 header.
+func traceID(traceparent, b3 string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) == 4 && len(parts[1]) == 32 {
+		return parts[1]
+	}
+	return b3
+}
@@ -36,7 +36,7 @@ func NewRestServer(c *Configuration) (*RestServer, error) {
 }
 
 func CustomHeaderToGRPCMetadataAnnotator(ctx context.Context, req *http.Request) metadata.MD {
-	return wool.MetadataFromRequest(ctx, req)
+	return forwardRequestHeaders(wool.MetadataFromRequest(ctx, req), req)
 }
 
 func customErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
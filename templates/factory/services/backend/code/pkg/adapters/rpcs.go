@@ -4,9 +4,11 @@ import (
 	"context"
 	"time"
 
+	"google.golang.org/genproto/googleapis/api/httpbody"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/codefly-dev/core/wool"
 
@@ -25,6 +27,15 @@ func WithService(s *business.Service) {
 // UserService RPCs (on UserServer)
 // ============================================================================
 
+// Health reports "degraded" (still HTTP 200) with the failing dependencies,
+// so monitoring sees a Vault or database outage the service is riding out.
+func (s *UserServer) Health(ctx context.Context, req *gen.HealthRequest) (*gen.HealthResponse, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	return service.Health(ctx), nil
+}
+
 func (s *UserServer) GetSelf(ctx context.Context, _ *gen.GetSelfRequest) (*gen.GetSelfResponse, error) {
 	w := wool.Get(ctx).In("GetSelf")
 	w.GRPC().Inject()
@@ -257,6 +268,19 @@ func (s *APIKeyServer) ListAPIKeys(ctx context.Context, req *gen.ListAPIKeysRequ
 	return service.ListAPIKeys(ctx, req)
 }
 
+func (s *APIKeyServer) UpdateAPIKey(ctx context.Context, req *gen.UpdateAPIKeyRequest) (*gen.APIKey, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	w := wool.Get(ctx).In("UpdateAPIKey")
+	w.GRPC().Inject()
+	userID, found := w.UserAuthID()
+	if !found {
+		return nil, status.Error(codes.Unauthenticated, "user id not found")
+	}
+	return service.UpdateAPIKey(ctx, userID, req)
+}
+
 func (s *APIKeyServer) RevokeAPIKey(ctx context.Context, req *gen.RevokeAPIKeyRequest) (*emptypb.Empty, error) {
 	if err := Validate(req); err != nil {
 		return nil, err
@@ -267,11 +291,78 @@ func (s *APIKeyServer) RevokeAPIKey(ctx context.Context, req *gen.RevokeAPIKeyRe
 	return &emptypb.Empty{}, nil
 }
 
+func (s *APIKeyServer) RevokeAPIKeys(ctx context.Context, req *gen.RevokeAPIKeysRequest) (*gen.RevokeAPIKeysResponse, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	w := wool.Get(ctx).In("RevokeAPIKeys")
+	w.GRPC().Inject()
+	userID, found := w.UserAuthID()
+	if !found {
+		return nil, status.Error(codes.Unauthenticated, "user id not found")
+	}
+	return service.RevokeAPIKeys(ctx, userID, req)
+}
+
+func (s *APIKeyServer) RotateAPIKey(ctx context.Context, req *gen.RotateAPIKeyRequest) (*gen.RotateAPIKeyResponse, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	w := wool.Get(ctx).In("RotateAPIKey")
+	w.GRPC().Inject()
+	userID, found := w.UserAuthID()
+	if !found {
+		return nil, status.Error(codes.Unauthenticated, "user id not found")
+	}
+	return service.RotateAPIKey(ctx, userID, req)
+}
+
+func (s *APIKeyServer) ReevaluateAPIKeys(ctx context.Context, req *gen.ReevaluateAPIKeysRequest) (*gen.ReevaluateAPIKeysResponse, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	w := wool.Get(ctx).In("ReevaluateAPIKeys")
+	w.GRPC().Inject()
+	userID, found := w.UserAuthID()
+	if !found {
+		return nil, status.Error(codes.Unauthenticated, "user id not found")
+	}
+	return service.ReevaluateAPIKeys(ctx, userID, req.OrganizationId)
+}
+
 func (s *APIKeyServer) ValidateAPIKey(ctx context.Context, req *gen.ValidateAPIKeyRequest) (*gen.ValidateAPIKeyResponse, error) {
 	if err := Validate(req); err != nil {
 		return nil, err
 	}
-	return service.ValidateAPIKey(ctx, req.KeyHash)
+	return service.ValidateAPIKey(ctx, req.KeyHash, clientIP(ctx), req.Origin)
+}
+
+func (s *APIKeyServer) ReportLeakedAPIKeys(ctx context.Context, req *gen.ReportLeakedAPIKeysRequest) (*gen.ReportLeakedAPIKeysResponse, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	return service.ReportLeakedAPIKeys(ctx, req)
+}
+
+// ============================================================================
+// MeteringService RPCs (on MeteringServer)
+// ============================================================================
+
+func (s *MeteringServer) GetRateLimits(ctx context.Context, req *gen.GetRateLimitsRequest) (*gen.GetRateLimitsResponse, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	return service.GetRateLimits(ctx, req.OrgId)
+}
+
+func (s *MeteringServer) RecordUsage(ctx context.Context, req *gen.RecordUsageRequest) (*emptypb.Empty, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	if err := service.RecordUsageBatch(ctx, req.Records); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
 }
 
 // ============================================================================
@@ -319,15 +410,7 @@ func (s *AuditServer) QueryAuditLog(ctx context.Context, req *gen.QueryAuditLogR
 		return nil, err
 	}
 
-	var from, to *time.Time
-	if req.From != nil {
-		t := req.From.AsTime()
-		from = &t
-	}
-	if req.To != nil {
-		t := req.To.AsTime()
-		to = &t
-	}
+	from, to := timeRange(req.From, req.To)
 
 	entries, nextToken, totalCount, err := service.QueryAuditLog(ctx,
 		req.OrgId, req.ActorId, req.Action, req.Resource, req.ResourceId,
@@ -348,6 +431,87 @@ func (s *AuditServer) QueryAuditLog(ctx context.Context, req *gen.QueryAuditLogR
 	}, nil
 }
 
+func (s *AuditServer) VerifyAuditChain(ctx context.Context, req *gen.VerifyAuditChainRequest) (*gen.VerifyAuditChainResponse, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	from, to := timeRange(req.From, req.To)
+	return service.VerifyAuditChain(ctx, req.OrgId, from, to)
+}
+
+func (s *AuditServer) ListAuditCheckpoints(ctx context.Context, req *gen.ListAuditCheckpointsRequest) (*gen.ListAuditCheckpointsResponse, error) {
+	if err := Validate(req); err != nil {
+		return nil, err
+	}
+	from, to := timeRange(req.From, req.To)
+	checkpoints, err := service.ListAuditCheckpoints(ctx, req.OrgId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &gen.ListAuditCheckpointsResponse{}
+	for _, cp := range checkpoints {
+		resp.Checkpoints = append(resp.Checkpoints, infra.AuditCheckpointToProto(cp))
+	}
+	return resp, nil
+}
+
+func (s *AuditServer) WatchAuditLog(req *gen.WatchAuditLogRequest, stream gen.AuditService_WatchAuditLogServer) error {
+	if err := Validate(req); err != nil {
+		return err
+	}
+	from, to := timeRange(req.From, req.To)
+	filter := business.AuditFilter{
+		OrgID: req.OrgId, ActorID: req.ActorId, Action: req.Action, Resource: req.Resource, ResourceID: req.ResourceId,
+		From: from, To: to,
+	}
+	return service.WatchAuditLog(stream.Context(), filter, req.ResumeToken, func(e business.AuditEntry, resumeToken string) error {
+		return stream.Send(&gen.WatchAuditLogResponse{Event: infra.AuditEntryToProto(e), ResumeToken: resumeToken})
+	})
+}
+
+func (s *AuditServer) ExportAuditLog(req *gen.ExportAuditLogRequest, stream gen.AuditService_ExportAuditLogServer) error {
+	if err := Validate(req); err != nil {
+		return err
+	}
+	from, to := timeRange(req.From, req.To)
+	filter := business.AuditFilter{
+		OrgID: req.OrgId, ActorID: req.ActorId, Action: req.Action, Resource: req.Resource, ResourceID: req.ResourceId,
+		From: from, To: to,
+	}
+	contentType := "application/x-ndjson"
+	if req.Format == gen.AuditExportFormat_AUDIT_EXPORT_FORMAT_CSV {
+		contentType = "text/csv"
+	}
+	return service.ExportAuditLog(stream.Context(), filter, req.Format, &httpBodyWriter{stream: stream, contentType: contentType})
+}
+
+// httpBodyWriter sends each write as an HttpBody chunk.
+type httpBodyWriter struct {
+	stream      gen.AuditService_ExportAuditLogServer
+	contentType string
+}
+
+func (w *httpBodyWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&httpbody.HttpBody{ContentType: w.contentType, Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// timeRange converts optional request bounds.
+func timeRange(fromTS, toTS *timestamppb.Timestamp) (from, to *time.Time) {
+	if fromTS != nil {
+		t := fromTS.AsTime()
+		from = &t
+	}
+	if toTS != nil {
+		t := toTS.AsTime()
+		to = &t
+	}
+	return from, to
+}
+
 // ============================================================================
 // InvitationService RPCs (on InvitationServer)
 // ============================================================================
@@ -0,0 +1,121 @@
+package business
+
+import (
+	"crypto/rand"
+	"hash/crc32"
+	"strings"
+
+	"backend/pkg/gen"
+)
+
+// API key format, version 1:
+//
+//	cfly_k1_{live|test}_{payload}{checksum}
+//
+// The payload is 43 base62 characters (256 bits) drawn without modulo bias,
+// and the checksum is the CRC32 of everything before it, as 6 base62
+// characters. Malformed keys are rejected without a store or Vault call, and
+// secret scanners can match issued keys with
+//
+//	cfly_k1_(live|test)_[0-9A-Za-z]{49}
+//
+// Keys issued before it (cfly_sk_{live|test}_ and 32 base62 characters, no
+// checksum) keep validating. The auth-sidecar checks the same format.
+const (
+	apiKeyPrefixV1         = "cfly_k1_"
+	apiKeyPrefixLegacy     = "cfly_sk_"
+	apiKeyPayloadLen       = 43
+	apiKeyChecksumLen      = 6
+	legacyAPIKeyPayloadLen = 32
+
+	// apiKeyDisplayLen is how much of a key is stored and shown as its prefix:
+	// the format prefix and 4 payload characters.
+	apiKeyDisplayLen = len(apiKeyPrefixV1) + len("live_") + 4
+)
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// generateAPIKey returns a new key in the current format and its displayable prefix.
+func generateAPIKey(env gen.APIKeyEnvironment) (plaintext, prefix string, err error) {
+	payload, err := randomBase62(apiKeyPayloadLen)
+	if err != nil {
+		return "", "", err
+	}
+	body := apiKeyPrefixV1 + apiKeyEnvName(env) + "_" + payload
+	plaintext = body + apiKeyChecksum(body)
+	return plaintext, plaintext[:apiKeyDisplayLen], nil
+}
+
+// apiKeyWellFormed reports whether key is a current key with a valid checksum
+// or a legacy key. It says nothing about whether the key exists.
+func apiKeyWellFormed(key string) bool {
+	if rest, ok := strings.CutPrefix(key, apiKeyPrefixLegacy); ok {
+		payload, ok := cutAPIKeyEnv(rest)
+		return ok && len(payload) == legacyAPIKeyPayloadLen && isBase62(payload)
+	}
+	rest, ok := strings.CutPrefix(key, apiKeyPrefixV1)
+	if !ok {
+		return false
+	}
+	payload, ok := cutAPIKeyEnv(rest)
+	if !ok || len(payload) != apiKeyPayloadLen+apiKeyChecksumLen || !isBase62(payload) {
+		return false
+	}
+	body := key[:len(key)-apiKeyChecksumLen]
+	return apiKeyChecksum(body) == key[len(body):]
+}
+
+func apiKeyEnvName(env gen.APIKeyEnvironment) string {
+	if env == gen.APIKeyEnvironment_API_KEY_ENVIRONMENT_TEST {
+		return "test"
+	}
+	return "live"
+}
+
+// cutAPIKeyEnv strips the live_ or test_ part of a key.
+func cutAPIKeyEnv(s string) (string, bool) {
+	if rest, ok := strings.CutPrefix(s, "live_"); ok {
+		return rest, true
+	}
+	return strings.CutPrefix(s, "test_")
+}
+
+// apiKeyChecksum is the CRC32 (IEEE) of s in fixed-width base62.
+func apiKeyChecksum(s string) string {
+	sum := crc32.ChecksumIEEE([]byte(s))
+	encoded := make([]byte, apiKeyChecksumLen)
+	for i := apiKeyChecksumLen - 1; i >= 0; i-- {
+		encoded[i] = base62Alphabet[sum%62]
+		sum /= 62
+	}
+	return string(encoded)
+}
+
+// randomBase62 returns n uniformly random base62 characters. Bytes of 248 and
+// above are discarded, so that byte % 62 is unbiased.
+func randomBase62(n int) (string, error) {
+	const limit = 256 - 256%len(base62Alphabet)
+	out := make([]byte, 0, n)
+	buf := make([]byte, n+n/4)
+	for len(out) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(out) < n {
+				out = append(out, base62Alphabet[int(b)%len(base62Alphabet)])
+			}
+		}
+	}
+	return string(out), nil
+}
+
+func isBase62(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package business
+
+import (
+	"context"
+
+	"github.com/codefly-dev/core/wool"
+
+	"backend/pkg/gen"
+)
+
+// apiKeyHashes records which hash a key was found under during a lookup.
+type apiKeyHashes struct {
+	current string // hash with the hasher's current scheme
+	matched string // stored hash that matched, current or older
+}
+
+// stale reports whether the key was stored with an older scheme.
+func (h apiKeyHashes) stale() bool {
+	return h.matched != "" && h.matched != h.current
+}
+
+// lookupAPIKey finds the key for plaintext by its hash with the current
+// scheme. Only on a miss does it hash with the older schemes (earlier Vault
+// key versions and legacy SHA-256), which keys stored before a rotation may
+// still use, and look those up in a single query.
+// Hashing failures are errors: there is no fallback to a weaker scheme.
+func (s *Service) lookupAPIKey(ctx context.Context, plaintext string) (*gen.APIKey, apiKeyHashes, error) {
+	w := wool.Get(ctx).In("lookupAPIKey")
+
+	var hashes apiKeyHashes
+	current, err := s.hasher.HashKey(ctx, plaintext)
+	if err != nil {
+		return nil, hashes, w.Wrapf(err, "cannot hash key")
+	}
+	hashes.current = current
+
+	key, err := s.store.GetAPIKeyByHash(ctx, current)
+	if err != nil {
+		return nil, hashes, err
+	}
+	if key != nil {
+		hashes.matched = current
+		return key, hashes, nil
+	}
+
+	previous, err := s.hasher.PreviousHashKeys(ctx, plaintext)
+	if err != nil {
+		return nil, hashes, w.Wrapf(err, "cannot hash key with previous schemes")
+	}
+	if len(previous) == 0 {
+		return nil, hashes, nil
+	}
+
+	key, matched, err := s.store.GetAPIKeyByHashes(ctx, previous)
+	if err != nil {
+		return nil, hashes, err
+	}
+	hashes.matched = matched
+	return key, hashes, nil
+}
+
+// rehashAPIKey moves a key that just validated to the current scheme, so
+// older schemes are retired as keys get used. A failure leaves the key on its
+// old hash until the next validation.
+func (s *Service) rehashAPIKey(ctx context.Context, key *gen.APIKey, hashes apiKeyHashes) {
+	w := wool.Get(ctx).In("rehashAPIKey")
+	if err := s.store.UpdateAPIKeyHash(ctx, key.Id, hashes.matched, hashes.current); err != nil {
+		w.Warn("cannot re-hash API key", wool.ErrField(err), wool.Field("key_id", key.Id))
+	}
+}
@@ -0,0 +1,83 @@
+package business
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/codefly-dev/core/wool"
+
+	"backend/pkg/gen"
+)
+
+// ReportLeakedAPIKeys handles a secret-scanning report. Each token that is
+// one of our keys is a true positive; live ones are revoked, audited as
+// api_key.leaked and their org's admins notified. Tokens are matched by hash,
+// so reports of malformed or unknown tokens cost no lookup beyond that.
+func (s *Service) ReportLeakedAPIKeys(ctx context.Context, req *gen.ReportLeakedAPIKeysRequest) (*gen.ReportLeakedAPIKeysResponse, error) {
+	w := wool.Get(ctx).In("ReportLeakedAPIKeys")
+
+	if s.hasher == nil {
+		return nil, w.NewError("key hasher not configured")
+	}
+
+	resp := &gen.ReportLeakedAPIKeysResponse{}
+	for _, report := range req.Reports {
+		result := &gen.LeakedAPIKeyResult{Token: report.Token}
+		resp.Results = append(resp.Results, result)
+		if !apiKeyWellFormed(report.Token) {
+			continue
+		}
+
+		key, _, err := s.lookupAPIKey(ctx, report.Token)
+		if err != nil {
+			return nil, w.Wrapf(err, "cannot look up key")
+		}
+		if key == nil {
+			continue
+		}
+		result.TruePositive = true
+		if key.RevokedAt != nil {
+			// Already revoked, e.g. reported twice
+			continue
+		}
+
+		details := map[string]string{"url": report.Url, "source": report.Source, "type": report.Type}
+		err = s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+			if err := s.store.RevokeAPIKey(ctx, key.Id); err != nil {
+				return err
+			}
+			if s.audit == nil {
+				return nil
+			}
+			return s.audit.Emit(ctx, AuditEntry{
+				ActorType:  "system",
+				Action:     "api_key.leaked",
+				Resource:   "api_key",
+				ResourceID: key.Id,
+				OrgID:      key.OrganizationId,
+				Metadata:   details,
+			})
+		})
+		if err != nil {
+			return nil, w.Wrapf(err, "cannot revoke leaked API key %s", key.Id)
+		}
+		if s.apiKeyInvalidator != nil {
+			if err := s.apiKeyInvalidator.InvalidateAPIKey(ctx, key.Id); err != nil {
+				w.Warn("cannot push API key invalidation", wool.ErrField(err))
+			}
+		}
+
+		err = s.notifyOrgAdmins(ctx, Notification{
+			Kind:    "api_key.leaked",
+			OrgID:   key.OrganizationId,
+			Subject: fmt.Sprintf("API key %q was leaked and has been revoked", key.Name),
+			Body: fmt.Sprintf("The API key %q (%s...) was found publicly at %s and has been revoked. "+
+				"Create a new key for the applications that used it.", key.Name, key.Prefix, report.Url),
+			Data: map[string]string{"api_key_id": key.Id, "url": report.Url, "source": report.Source},
+		})
+		if err != nil {
+			w.Warn("cannot notify admins of leaked API key", wool.ErrField(err))
+		}
+	}
+	return resp, nil
+}
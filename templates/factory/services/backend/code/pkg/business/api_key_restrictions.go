@@ -0,0 +1,213 @@
+package business
+
+import (
+	"context"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/codefly-dev/core/wool"
+	"google.golang.org/protobuf/proto"
+
+	"backend/pkg/gen"
+)
+
+// UpdateAPIKey edits the fields named in the update mask (all editable fields
+// when empty): name, allowed_cidrs and allowed_origins. The user needs
+// api_keys:manage in the key's org. Cached validations are dropped so new
+// restrictions apply right away.
+func (s *Service) UpdateAPIKey(ctx context.Context, userID string, req *gen.UpdateAPIKeyRequest) (*gen.APIKey, error) {
+	w := wool.Get(ctx).In("UpdateAPIKey")
+
+	key, err := s.store.GetAPIKey(ctx, req.Id)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot look up key")
+	}
+	if key == nil || key.RevokedAt != nil {
+		return nil, w.NewError("API key not found")
+	}
+	if err := s.requireAPIKeyManager(ctx, userID, key.OrganizationId); err != nil {
+		return nil, err
+	}
+	before := proto.Clone(key)
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"name", "allowed_cidrs", "allowed_origins"}
+	}
+	update := req.GetKey()
+	for _, path := range paths {
+		switch path {
+		case "name":
+			if update.GetName() == "" || len(update.GetName()) > 255 {
+				return nil, w.NewError("name must be 1 to 255 characters")
+			}
+			key.Name = update.GetName()
+		case "allowed_cidrs":
+			cidrs, err := normalizeCIDRs(ctx, update.GetAllowedCidrs())
+			if err != nil {
+				return nil, err
+			}
+			key.AllowedCidrs = cidrs
+		case "allowed_origins":
+			origins, err := normalizeOrigins(ctx, update.GetAllowedOrigins())
+			if err != nil {
+				return nil, err
+			}
+			key.AllowedOrigins = origins
+		default:
+			return nil, w.NewError("field %s cannot be updated", path)
+		}
+	}
+
+	err = s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.UpdateAPIKey(ctx, key); err != nil {
+			return w.Wrapf(err, "cannot update API key")
+		}
+		return s.emit(ctx, userID, "user", "api_key.updated", "api_key", key.Id, key.OrganizationId,
+			WithAuditDiff(before, key))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.apiKeyInvalidator != nil {
+		if err := s.apiKeyInvalidator.InvalidateAPIKey(ctx, key.Id); err != nil {
+			w.Warn("cannot push API key invalidation", wool.ErrField(err))
+		}
+	}
+	return key, nil
+}
+
+// maxAPIKeyRestrictions bounds each allowlist.
+const maxAPIKeyRestrictions = 100
+
+// normalizeCIDRs validates an allowlist of CIDRs. A bare IP is taken as a
+// single-address prefix; prefixes are masked (10.1.2.3/8 → 10.0.0.0/8).
+func normalizeCIDRs(ctx context.Context, values []string) ([]string, error) {
+	w := wool.Get(ctx).In("normalizeCIDRs")
+	if len(values) > maxAPIKeyRestrictions {
+		return nil, w.NewError("at most %d allowed CIDRs", maxAPIKeyRestrictions)
+	}
+	cidrs := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		var prefix netip.Prefix
+		if strings.Contains(value, "/") {
+			p, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, w.NewError("invalid CIDR %q", value)
+			}
+			prefix = p.Masked()
+		} else {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, w.NewError("invalid CIDR %q", value)
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		if !slices.Contains(cidrs, prefix.String()) {
+			cidrs = append(cidrs, prefix.String())
+		}
+	}
+	return cidrs, nil
+}
+
+// normalizeOrigins validates an allowlist of origins: scheme://host[:port],
+// where host may start with a "*." wildcard for any subdomain.
+func normalizeOrigins(ctx context.Context, values []string) ([]string, error) {
+	w := wool.Get(ctx).In("normalizeOrigins")
+	if len(values) > maxAPIKeyRestrictions {
+		return nil, w.NewError("at most %d allowed origins", maxAPIKeyRestrictions)
+	}
+	origins := make([]string, 0, len(values))
+	for _, value := range values {
+		u, err := url.Parse(strings.TrimSpace(value))
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" ||
+			(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil ||
+			strings.Contains(strings.TrimPrefix(u.Host, "*."), "*") {
+			return nil, w.NewError("invalid origin %q: expected scheme://host[:port]", value)
+		}
+		origin := u.Scheme + "://" + strings.ToLower(u.Host)
+		if !slices.Contains(origins, origin) {
+			origins = append(origins, origin)
+		}
+	}
+	return origins, nil
+}
+
+// apiKeyIPAllowed reports whether ip is in one of the CIDRs. No CIDRs allows
+// any caller; with CIDRs, an unknown or unparsable IP is refused.
+func apiKeyIPAllowed(cidrs []string, ip string) bool {
+	if len(cidrs) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyOriginAllowed reports whether the request origin (an Origin header or
+// a Referer URL) matches one of the allowed origins. No origins allows any
+// request; with origins, a request without one is refused.
+func apiKeyOriginAllowed(origins []string, origin string) bool {
+	if len(origins) == 0 {
+		return true
+	}
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+	for _, allowed := range origins {
+		allowedScheme, allowedHost, ok := strings.Cut(allowed, "://")
+		if !ok || allowedScheme != scheme {
+			continue
+		}
+		if allowedHost == host {
+			return true
+		}
+		if suffix, wildcard := strings.CutPrefix(allowedHost, "*"); wildcard && strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// emitAPIKeyDenied audits a use of a valid key from outside its allowlists.
+// The key is denied even when the event can't be recorded.
+func (s *Service) emitAPIKeyDenied(ctx context.Context, key *gen.APIKey, action, clientIP, origin string) {
+	w := wool.Get(ctx).In("emitAPIKeyDenied")
+
+	if s.audit == nil {
+		return
+	}
+	metadata := map[string]string{}
+	if origin != "" {
+		metadata["origin"] = origin
+	}
+	err := s.audit.Emit(ctx, AuditEntry{
+		ActorID:    key.Id,
+		ActorType:  "api_key",
+		Action:     action,
+		Resource:   "api_key",
+		ResourceID: key.Id,
+		OrgID:      key.OrganizationId,
+		Metadata:   metadata,
+		IPAddress:  clientIP,
+	})
+	if err != nil {
+		w.Warn("cannot record denied API key use", wool.ErrField(err))
+	}
+}
@@ -0,0 +1,126 @@
+package business
+
+import (
+	"context"
+	"time"
+
+	"github.com/codefly-dev/core/wool"
+
+	"backend/pkg/gen"
+)
+
+// uncoveredScope returns the first scope the user isn't granted in the org, or
+// nil when the user holds every one. Wildcards are contained only by
+// wildcards: users:* needs a users:* (or *:*) grant.
+func (s *Service) uncoveredScope(ctx context.Context, userID, orgID string, scopes []*gen.Permission) (*gen.Permission, error) {
+	for _, scope := range scopes {
+		allowed, _, err := s.store.CheckPermission(ctx, userID, gen.SubjectKind_SUBJECT_KIND_USER,
+			scope.Resource, scope.Action, orgID, "")
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return scope, nil
+		}
+	}
+	return nil, nil
+}
+
+// ReevaluateAPIKeys re-evaluates the keys of the org on behalf of a user
+// holding api_keys:manage there.
+func (s *Service) ReevaluateAPIKeys(ctx context.Context, userID, orgID string) (*gen.ReevaluateAPIKeysResponse, error) {
+	if err := s.requireAPIKeyManager(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+	return s.reevaluateAPIKeys(ctx, orgID)
+}
+
+// reevaluateAPIKeys re-checks the scopes of every live key in the org (all
+// orgs when orgID is empty) against its owner's current permissions. Keys
+// whose owner lost a permission are disabled and dropped from validation
+// caches; disabled keys whose owner has the permissions again are re-enabled.
+// A key that fails is logged and reported, and the others still go through.
+func (s *Service) reevaluateAPIKeys(ctx context.Context, orgID string) (*gen.ReevaluateAPIKeysResponse, error) {
+	w := wool.Get(ctx).In("reevaluateAPIKeys")
+
+	keys, err := s.store.ListLiveAPIKeys(ctx, orgID)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot list API keys")
+	}
+
+	resp := &gen.ReevaluateAPIKeysResponse{Checked: int32(len(keys))}
+	for _, key := range keys {
+		missing, err := s.uncoveredScope(ctx, key.UserId, key.OrganizationId, key.Scopes)
+		if err != nil {
+			w.Warn("cannot check scopes of API key", wool.ErrField(err), wool.Field("key_id", key.Id))
+			resp.FailedKeyIds = append(resp.FailedKeyIds, key.Id)
+			continue
+		}
+
+		switch {
+		case missing != nil && key.DisabledAt == nil:
+			reason := "owner lacks permission " + missing.Resource + ":" + missing.Action
+			err := s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+				if err := s.store.SetAPIKeyDisabled(ctx, key.Id, reason); err != nil {
+					return err
+				}
+				return s.emit(ctx, "", "system", "api_key.disabled", "api_key", key.Id, key.OrganizationId)
+			})
+			if err != nil {
+				w.Warn("cannot disable API key", wool.ErrField(err), wool.Field("key_id", key.Id))
+				resp.FailedKeyIds = append(resp.FailedKeyIds, key.Id)
+				continue
+			}
+			if s.apiKeyInvalidator != nil {
+				if err := s.apiKeyInvalidator.InvalidateAPIKey(ctx, key.Id); err != nil {
+					w.Warn("cannot push API key invalidation", wool.ErrField(err))
+				}
+			}
+			key.DisabledReason = reason
+			resp.Disabled = append(resp.Disabled, key)
+
+		case missing == nil && key.DisabledAt != nil:
+			err := s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+				if err := s.store.SetAPIKeyDisabled(ctx, key.Id, ""); err != nil {
+					return err
+				}
+				return s.emit(ctx, "", "system", "api_key.enabled", "api_key", key.Id, key.OrganizationId)
+			})
+			if err != nil {
+				w.Warn("cannot enable API key", wool.ErrField(err), wool.Field("key_id", key.Id))
+				resp.FailedKeyIds = append(resp.FailedKeyIds, key.Id)
+				continue
+			}
+			// Negative cache entries expire on their own
+			key.DisabledAt, key.DisabledReason = nil, ""
+			resp.Enabled = append(resp.Enabled, key)
+		}
+	}
+	return resp, nil
+}
+
+// RunAPIKeyReevaluation re-evaluates the keys of all orgs every interval
+// until ctx is done.
+func (s *Service) RunAPIKeyReevaluation(ctx context.Context, interval time.Duration) {
+	w := wool.Get(ctx).In("RunAPIKeyReevaluation")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		resp, err := s.reevaluateAPIKeys(ctx, "")
+		if err != nil {
+			w.Warn("cannot re-evaluate API keys", wool.ErrField(err))
+			continue
+		}
+		if len(resp.Disabled) > 0 || len(resp.Enabled) > 0 || len(resp.FailedKeyIds) > 0 {
+			w.Info("re-evaluated API keys",
+				wool.Field("disabled", len(resp.Disabled)), wool.Field("enabled", len(resp.Enabled)),
+				wool.Field("failed", len(resp.FailedKeyIds)))
+		}
+	}
+}
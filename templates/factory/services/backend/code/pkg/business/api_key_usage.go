@@ -0,0 +1,130 @@
+package business
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/codefly-dev/core/wool"
+)
+
+// maxAPIKeyUsagePerFlush bounds a single RecordAPIKeyUsage batch.
+const maxAPIKeyUsagePerFlush = 1000
+
+// APIKeyUsageRecorder records successful API key validations. Implementations must be non-blocking.
+type APIKeyUsageRecorder interface {
+	RecordAPIKeyUse(keyID, ip string, at time.Time)
+}
+
+// BatchedAPIKeyUsage aggregates API key use in memory, per key and day, and
+// writes it to the store in batches.
+type BatchedAPIKeyUsage struct {
+	store      Store
+	maxPending int
+
+	mu      sync.Mutex
+	pending map[apiKeyUsageKey]*APIKeyUsage
+	dropped int64
+}
+
+type apiKeyUsageKey struct {
+	keyID string
+	day   time.Time
+}
+
+// NewBatchedAPIKeyUsage creates a recorder holding at most maxPending
+// (key, day) entries between flushes; use of other keys is dropped beyond that.
+func NewBatchedAPIKeyUsage(store Store, maxPending int) *BatchedAPIKeyUsage {
+	return &BatchedAPIKeyUsage{
+		store:      store,
+		maxPending: maxPending,
+		pending:    make(map[apiKeyUsageKey]*APIKeyUsage),
+	}
+}
+
+// RecordAPIKeyUse implements APIKeyUsageRecorder.
+func (b *BatchedAPIKeyUsage) RecordAPIKeyUse(keyID, ip string, at time.Time) {
+	at = at.UTC()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.add(APIKeyUsage{
+		KeyID:      keyID,
+		Day:        at.Truncate(24 * time.Hour),
+		Requests:   1,
+		LastUsedAt: at,
+		LastUsedIP: ip,
+	})
+}
+
+// add merges usage into the pending entries; mu must be held.
+func (b *BatchedAPIKeyUsage) add(u APIKeyUsage) {
+	k := apiKeyUsageKey{keyID: u.KeyID, day: u.Day}
+	entry, ok := b.pending[k]
+	if !ok {
+		if len(b.pending) >= b.maxPending {
+			// Full: drop rather than grow without bound while the store is down
+			b.dropped += u.Requests
+			return
+		}
+		b.pending[k] = &u
+		return
+	}
+	entry.Requests += u.Requests
+	if u.LastUsedAt.After(entry.LastUsedAt) {
+		entry.LastUsedAt, entry.LastUsedIP = u.LastUsedAt, u.LastUsedIP
+	}
+}
+
+// Dropped returns how many uses were not recorded because the buffer was full.
+func (b *BatchedAPIKeyUsage) Dropped() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// Flush writes pending usage to the store. Batches that fail are put back
+// and retried on the next flush.
+func (b *BatchedAPIKeyUsage) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	batch := make([]APIKeyUsage, 0, len(b.pending))
+	for _, u := range b.pending {
+		batch = append(batch, *u)
+	}
+	b.pending = make(map[apiKeyUsageKey]*APIKeyUsage)
+	b.mu.Unlock()
+
+	var firstErr error
+	for start := 0; start < len(batch); start += maxAPIKeyUsagePerFlush {
+		chunk := batch[start:min(start+maxAPIKeyUsagePerFlush, len(batch))]
+		if err := b.store.RecordAPIKeyUsage(ctx, chunk); err != nil {
+			b.mu.Lock()
+			for _, u := range chunk {
+				b.add(u)
+			}
+			b.mu.Unlock()
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// Run flushes every interval until ctx is done. Call Flush once more after
+// it returns to write what is left.
+func (b *BatchedAPIKeyUsage) Run(ctx context.Context, interval time.Duration) {
+	w := wool.Get(ctx).In("BatchedAPIKeyUsage.Run")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.Flush(ctx); err != nil {
+				w.Warn("cannot record API key usage, will retry", wool.ErrField(err))
+			}
+		}
+	}
+}
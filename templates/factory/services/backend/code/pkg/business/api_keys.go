@@ -2,19 +2,29 @@ package business
 
 import (
 	"context"
-	"crypto/rand"
 	"fmt"
 	"time"
 
 	"github.com/codefly-dev/core/wool"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"backend/pkg/gen"
 )
 
-// KeyHasher hashes API key plaintext into a storable hash.
+// KeyHasher hashes API key plaintext into a storable hash prefixed with its
+// scheme and key version, e.g. "vault-hmac:v2:..." or "sha256:...".
 type KeyHasher interface {
+	// HashKey hashes with the current scheme, used for new keys.
 	HashKey(ctx context.Context, plaintext string) (string, error)
+	// PreviousHashKeys hashes with the older schemes stored keys may still
+	// use, newest first.
+	PreviousHashKeys(ctx context.Context, plaintext string) ([]string, error)
+}
+
+// APIKeyInvalidator tells caches of ValidateAPIKey results (e.g. the auth-sidecar) that a key is gone.
+type APIKeyInvalidator interface {
+	InvalidateAPIKey(ctx context.Context, keyID string) error
 }
 
 // CreateAPIKey generates a new API key, hashes it via vault, and stores the hash.
@@ -36,20 +46,28 @@ func (s *Service) CreateAPIKey(ctx context.Context, userID string, req *gen.Crea
 		}
 	}
 
-	// Generate random key material (32 bytes = 256 bits)
-	raw := make([]byte, 32)
-	if _, err := rand.Read(raw); err != nil {
-		return nil, w.Wrapf(err, "cannot generate random key")
+	// A key can't do more than its creator
+	missing, err := s.uncoveredScope(ctx, userID, req.OrganizationId, req.Scopes)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot check scopes")
+	}
+	if missing != nil {
+		return nil, w.NewError("scope %s:%s exceeds your permissions in this organization", missing.Resource, missing.Action)
+	}
+
+	allowedCIDRs, err := normalizeCIDRs(ctx, req.AllowedCidrs)
+	if err != nil {
+		return nil, err
+	}
+	allowedOrigins, err := normalizeOrigins(ctx, req.AllowedOrigins)
+	if err != nil {
+		return nil, err
 	}
 
-	// Format: cfly_sk_{env}_{base62}
-	envPrefix := "live"
-	if req.Environment == gen.APIKeyEnvironment_API_KEY_ENVIRONMENT_TEST {
-		envPrefix = "test"
+	plaintext, prefix, err := generateAPIKey(req.Environment)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot generate key")
 	}
-	encoded := base62Encode(raw)
-	plaintext := fmt.Sprintf("cfly_sk_%s_%s", envPrefix, encoded)
-	prefix := plaintext[:12]
 
 	// Hash via vault transit
 	keyHash, err := s.hasher.HashKey(ctx, plaintext)
@@ -67,49 +85,261 @@ func (s *Service) CreateAPIKey(ctx context.Context, userID string, req *gen.Crea
 		Scopes:         req.Scopes,
 		Environment:    req.Environment,
 		ExpiresAt:      req.ExpiresAt,
+		AllowedCidrs:   allowedCIDRs,
+		AllowedOrigins: allowedOrigins,
 	}
 
-	if err := s.store.CreateAPIKey(ctx, key, keyHash); err != nil {
-		return nil, w.Wrapf(err, "cannot store API key")
+	err = s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.CreateAPIKey(ctx, key, keyHash); err != nil {
+			return w.Wrapf(err, "cannot store API key")
+		}
+		return s.emit(ctx, userID, "user", "api_key.created", "api_key", keyID, req.OrganizationId)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	s.emit(ctx, userID, "user", "api_key.created", "api_key", keyID, req.OrganizationId)
-
 	return &gen.CreateAPIKeyResponse{
 		Key:          key,
 		PlaintextKey: plaintext,
 	}, nil
 }
 
-// ValidateAPIKey checks a hashed key against the store.
-func (s *Service) ValidateAPIKey(ctx context.Context, plaintextKey string) (*gen.ValidateAPIKeyResponse, error) {
-	w := wool.Get(ctx).In("ValidateAPIKey")
+// DefaultAPIKeyRotationGrace is how long a rotated key keeps working when the
+// request doesn't say.
+const DefaultAPIKeyRotationGrace = 24 * time.Hour
+
+// apiKeyDeprecatedWarning is returned by ValidateAPIKey during the grace period.
+const apiKeyDeprecatedWarning = "deprecated, rotate soon"
+
+// RotateAPIKey issues a successor with the same name, scopes, environment and restrictions,
+// and schedules the old key's revocation at the end of the grace period.
+// Rotation replaces a key, so it is not counted against the API key quota.
+func (s *Service) RotateAPIKey(ctx context.Context, userID string, req *gen.RotateAPIKeyRequest) (*gen.RotateAPIKeyResponse, error) {
+	w := wool.Get(ctx).In("RotateAPIKey")
 
 	if s.hasher == nil {
 		return nil, w.NewError("key hasher not configured")
 	}
 
-	keyHash, err := s.hasher.HashKey(ctx, plaintextKey)
+	old, err := s.store.GetAPIKey(ctx, req.Id)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot look up key")
+	}
+	if old == nil || old.RevokedAt != nil {
+		return nil, w.NewError("API key not found")
+	}
+	if old.RevokeAt != nil {
+		return nil, w.NewError("API key is already being rotated")
+	}
+	if old.DisabledAt != nil {
+		return nil, w.NewError("API key is disabled: %s", old.DisabledReason)
+	}
+	if err := s.requireAPIKeyManager(ctx, userID, old.OrganizationId); err != nil {
+		return nil, err
+	}
+
+	// The successor is issued by the caller, so it can't do more than they can
+	missing, err := s.uncoveredScope(ctx, userID, old.OrganizationId, old.Scopes)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot check scopes")
+	}
+	if missing != nil {
+		return nil, w.NewError("scope %s:%s exceeds your permissions in this organization", missing.Resource, missing.Action)
+	}
+
+	grace := DefaultAPIKeyRotationGrace
+	if req.GracePeriodSeconds > 0 {
+		grace = time.Duration(req.GracePeriodSeconds) * time.Second
+	}
+	revokeAt := time.Now().Add(grace)
+
+	plaintext, prefix, err := generateAPIKey(old.Environment)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot generate key")
+	}
+	keyHash, err := s.hasher.HashKey(ctx, plaintext)
 	if err != nil {
 		return nil, w.Wrapf(err, "cannot hash key")
 	}
 
-	key, err := s.store.GetAPIKeyByHash(ctx, keyHash)
+	expiresAt := old.ExpiresAt
+	if req.ExpiresAt != nil {
+		expiresAt = req.ExpiresAt
+	}
+	key := &gen.APIKey{
+		Id:             uuid.New().String(),
+		OrganizationId: old.OrganizationId,
+		UserId:         old.UserId,
+		Name:           old.Name,
+		Prefix:         prefix,
+		Scopes:         old.Scopes,
+		Environment:    old.Environment,
+		ExpiresAt:      expiresAt,
+		RotatedFromId:  old.Id,
+		AllowedCidrs:   old.AllowedCidrs,
+		AllowedOrigins: old.AllowedOrigins,
+	}
+
+	err = s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		scheduled, err := s.store.ScheduleAPIKeyRevocation(ctx, old.Id, revokeAt)
+		if err != nil {
+			return err
+		}
+		if !scheduled {
+			// Revoked or rotated since we read it
+			return w.NewError("API key is already being rotated")
+		}
+		if err := s.store.CreateAPIKey(ctx, key, keyHash); err != nil {
+			return err
+		}
+		if err := s.emit(ctx, userID, "user", "api_key.rotated", "api_key", old.Id, old.OrganizationId); err != nil {
+			return err
+		}
+		return s.emit(ctx, userID, "user", "api_key.created", "api_key", key.Id, old.OrganizationId)
+	})
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot rotate API key")
+	}
+	old.RevokeAt = timestamppb.New(revokeAt)
+
+	// Cached validations of the old key don't carry the deprecation yet
+	if s.apiKeyInvalidator != nil {
+		if err := s.apiKeyInvalidator.InvalidateAPIKey(ctx, old.Id); err != nil {
+			w.Warn("cannot push API key invalidation", wool.ErrField(err))
+		}
+	}
+
+	return &gen.RotateAPIKeyResponse{
+		Key:          key,
+		PlaintextKey: plaintext,
+		PreviousKey:  old,
+	}, nil
+}
+
+// requireAPIKeyManager checks that the user holds api_keys:manage in the org,
+// as the admin role does.
+func (s *Service) requireAPIKeyManager(ctx context.Context, userID, orgID string) error {
+	w := wool.Get(ctx).In("requireAPIKeyManager")
+
+	allowed, _, err := s.store.CheckPermission(ctx, userID, gen.SubjectKind_SUBJECT_KIND_USER,
+		"api_keys", "manage", orgID, "")
+	if err != nil {
+		return w.Wrapf(err, "cannot check permission")
+	}
+	if !allowed {
+		return w.NewError("permission api_keys:manage required in this organization")
+	}
+	return nil
+}
+
+// RevokeDueAPIKeys revokes rotated keys whose grace period is over and pushes
+// their invalidation to validation caches. It returns how many were revoked.
+func (s *Service) RevokeDueAPIKeys(ctx context.Context) (int, error) {
+	w := wool.Get(ctx).In("RevokeDueAPIKeys")
+
+	var keys []*gen.APIKey
+	err := s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		keys, err = s.store.RevokeDueAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := s.emit(ctx, "", "system", "api_key.revoked", "api_key", key.Id, key.OrganizationId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, w.Wrapf(err, "cannot revoke rotated keys")
+	}
+	for _, key := range keys {
+		if s.apiKeyInvalidator != nil {
+			if err := s.apiKeyInvalidator.InvalidateAPIKey(ctx, key.Id); err != nil {
+				w.Warn("cannot push API key invalidation", wool.ErrField(err))
+			}
+		}
+	}
+	return len(keys), nil
+}
+
+// RunAPIKeyRevocations calls RevokeDueAPIKeys every interval until ctx is done.
+func (s *Service) RunAPIKeyRevocations(ctx context.Context, interval time.Duration) {
+	w := wool.Get(ctx).In("RunAPIKeyRevocations")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RevokeDueAPIKeys(ctx); err != nil {
+			w.Warn("cannot revoke rotated API keys", wool.ErrField(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ValidateAPIKey checks a hashed key against the store and its network
+// restrictions, and records the use of valid keys. clientIP is the caller's
+// address as forwarded by the sidecar and origin the request's Origin or
+// Referer, if known. Uses from outside the allowlists are audited.
+func (s *Service) ValidateAPIKey(ctx context.Context, plaintextKey, clientIP, origin string) (*gen.ValidateAPIKeyResponse, error) {
+	w := wool.Get(ctx).In("ValidateAPIKey")
+
+	// Typos and garbage don't cost a Vault call and a lookup
+	if !apiKeyWellFormed(plaintextKey) {
+		return invalidAPIKey(gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_MALFORMED), nil
+	}
+
+	if s.hasher == nil {
+		return nil, w.NewError("key hasher not configured")
+	}
+
+	key, hashes, err := s.lookupAPIKey(ctx, plaintextKey)
 	if err != nil {
 		return nil, w.Wrapf(err, "cannot look up key")
 	}
 	if key == nil {
-		return &gen.ValidateAPIKeyResponse{Valid: false}, nil
+		return invalidAPIKey(gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_NOT_FOUND), nil
 	}
 
 	// Check revoked
 	if key.RevokedAt != nil {
-		return &gen.ValidateAPIKeyResponse{Valid: false}, nil
+		return invalidAPIKey(gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_REVOKED), nil
 	}
 
 	// Check expired
 	if key.ExpiresAt != nil && key.ExpiresAt.AsTime().Before(time.Now()) {
-		return &gen.ValidateAPIKeyResponse{Valid: false}, nil
+		return invalidAPIKey(gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_EXPIRED), nil
+	}
+
+	// Check disabled (owner lost a permission behind the scopes)
+	if key.DisabledAt != nil {
+		return invalidAPIKey(gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_DISABLED), nil
+	}
+
+	// Rotated keys: invalid once the grace period is over, even if the
+	// revocation sweep hasn't run yet
+	var revokeAt *timestamppb.Timestamp
+	if key.RevokeAt != nil {
+		if !key.RevokeAt.AsTime().After(time.Now()) {
+			return invalidAPIKey(gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_REVOKED), nil
+		}
+		revokeAt = key.RevokeAt
+	}
+
+	// Check network restrictions
+	if !apiKeyIPAllowed(key.AllowedCidrs, clientIP) {
+		s.emitAPIKeyDenied(ctx, key, "api_key.ip_denied", clientIP, origin)
+		return invalidAPIKey(gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_IP_NOT_ALLOWED), nil
+	}
+	if !apiKeyOriginAllowed(key.AllowedOrigins, origin) {
+		s.emitAPIKeyDenied(ctx, key, "api_key.origin_denied", clientIP, origin)
+		return invalidAPIKey(gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_ORIGIN_NOT_ALLOWED), nil
 	}
 
 	// Build scopes list
@@ -118,34 +348,116 @@ func (s *Service) ValidateAPIKey(ctx context.Context, plaintextKey string) (*gen
 		scopes = append(scopes, fmt.Sprintf("%s:%s", p.Resource, p.Action))
 	}
 
-	return &gen.ValidateAPIKeyResponse{
+	if s.apiKeyUsage != nil {
+		s.apiKeyUsage.RecordAPIKeyUse(key.Id, clientIP, time.Now())
+	}
+	if hashes.stale() {
+		s.rehashAPIKey(ctx, key, hashes)
+	}
+
+	resp := &gen.ValidateAPIKeyResponse{
 		Valid:          true,
 		UserId:         key.UserId,
 		OrganizationId: key.OrganizationId,
 		Scopes:         scopes,
-	}, nil
+		KeyId:          key.Id,
+		AllowedCidrs:   key.AllowedCidrs,
+		AllowedOrigins: key.AllowedOrigins,
+	}
+	if revokeAt != nil {
+		resp.Deprecated = true
+		resp.RevokeAt = revokeAt
+		resp.Warning = apiKeyDeprecatedWarning
+	}
+	return resp, nil
+}
+
+func invalidAPIKey(reason gen.APIKeyInvalidReason) *gen.ValidateAPIKeyResponse {
+	return &gen.ValidateAPIKeyResponse{Valid: false, Reason: reason}
 }
 
-// ListAPIKeys returns non-revoked API keys for an org.
+// ListAPIKeys returns non-revoked API keys for an org, with their daily usage
+// when requested.
 func (s *Service) ListAPIKeys(ctx context.Context, req *gen.ListAPIKeysRequest) (*gen.ListAPIKeysResponse, error) {
-	keys, nextToken, err := s.store.ListAPIKeys(ctx, req.OrganizationId, req.PageSize, req.PageToken)
+	w := wool.Get(ctx).In("ListAPIKeys")
+
+	var unusedSince *time.Time
+	if req.UnusedSince != nil {
+		t := req.UnusedSince.AsTime()
+		unusedSince = &t
+	}
+	keys, nextToken, err := s.store.ListAPIKeys(ctx, req.OrganizationId, unusedSince, req.PageSize, req.PageToken)
 	if err != nil {
 		return nil, err
 	}
+
+	if req.UsageDays > 0 && len(keys) > 0 {
+		ids := make([]string, len(keys))
+		for i, key := range keys {
+			ids[i] = key.Id
+		}
+		since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -int(req.UsageDays-1))
+		usage, err := s.store.GetAPIKeyDailyUsage(ctx, ids, since)
+		if err != nil {
+			return nil, w.Wrapf(err, "cannot load API key usage")
+		}
+		for _, key := range keys {
+			key.DailyUsage = usage[key.Id]
+		}
+	}
 	return &gen.ListAPIKeysResponse{Keys: keys, NextPageToken: nextToken}, nil
 }
 
-// RevokeAPIKey marks a key as revoked.
+// RevokeAPIKey marks a key as revoked and pushes the invalidation to validation caches.
 func (s *Service) RevokeAPIKey(ctx context.Context, req *gen.RevokeAPIKeyRequest) error {
-	return s.store.RevokeAPIKey(ctx, req.Id)
+	w := wool.Get(ctx).In("RevokeAPIKey")
+
+	if err := s.store.RevokeAPIKey(ctx, req.Id); err != nil {
+		return err
+	}
+
+	// Best effort: caches still drop the key when their short TTL runs out
+	if s.apiKeyInvalidator != nil {
+		if err := s.apiKeyInvalidator.InvalidateAPIKey(ctx, req.Id); err != nil {
+			w.Warn("cannot push API key invalidation", wool.ErrField(err))
+		}
+	}
+	return nil
 }
 
-// base62Encode encodes bytes to a base62 string (alphanumeric).
-func base62Encode(data []byte) string {
-	const charset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
-	encoded := make([]byte, len(data))
-	for i, b := range data {
-		encoded[i] = charset[int(b)%len(charset)]
+// RevokeAPIKeys revokes many keys of an org, for a user holding
+// api_keys:manage there, and pushes their invalidation to validation caches.
+// Keys already revoked or in another org are skipped.
+func (s *Service) RevokeAPIKeys(ctx context.Context, userID string, req *gen.RevokeAPIKeysRequest) (*gen.RevokeAPIKeysResponse, error) {
+	w := wool.Get(ctx).In("RevokeAPIKeys")
+
+	if err := s.requireAPIKeyManager(ctx, userID, req.OrganizationId); err != nil {
+		return nil, err
+	}
+
+	var revoked []string
+	err := s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		revoked, err = s.store.RevokeAPIKeys(ctx, req.OrganizationId, req.Ids)
+		if err != nil {
+			return err
+		}
+		for _, id := range revoked {
+			if err := s.emit(ctx, userID, "user", "api_key.revoked", "api_key", id, req.OrganizationId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot revoke API keys")
+	}
+	for _, id := range revoked {
+		if s.apiKeyInvalidator != nil {
+			if err := s.apiKeyInvalidator.InvalidateAPIKey(ctx, id); err != nil {
+				w.Warn("cannot push API key invalidation", wool.ErrField(err))
+			}
+		}
 	}
-	return string(encoded)
+	return &gen.RevokeAPIKeysResponse{RevokedIds: revoked}, nil
 }
@@ -2,14 +2,18 @@ package business
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/codefly-dev/core/wool"
 )
 
 // AuditEntry is the domain representation of an audit event.
 type AuditEntry struct {
 	ID         string
 	ActorID    string
-	ActorType  string // "user", "api_key", "system"
+	ActorType  string // ActorUser, ActorAPIKey, ActorSystem, etc.
 	Action     string // "user.registered", "api_key.created", etc.
 	Resource   string
 	ResourceID string
@@ -17,48 +21,157 @@ type AuditEntry struct {
 	Metadata   map[string]string
 	IPAddress  string
 	CreatedAt  time.Time
+
+	// Position in the org's hash chain (see AuditEventHash); zero for events
+	// recorded before chaining
+	ChainSeq int64
+	PrevHash string
+	Hash     string
 }
 
-// AuditEmitter writes audit events. Implementations must be non-blocking.
+// AuditEmitter records audit events. Emit within RunInTransaction so the
+// event is recorded if and only if the change it describes commits.
 type AuditEmitter interface {
-	Emit(ctx context.Context, entry AuditEntry)
+	Emit(ctx context.Context, entry AuditEntry) error
+}
+
+// OutboxAuditEmitter writes events to the store's outbox, in the transaction
+// of ctx; an AuditRelay then moves them to the audit log.
+type OutboxAuditEmitter struct {
+	store   Store
+	dropped atomic.Int64
+}
+
+func NewOutboxAuditEmitter(store Store) *OutboxAuditEmitter {
+	return &OutboxAuditEmitter{store: store}
+}
+
+// Emit implements AuditEmitter. An error fails the caller's transaction.
+func (e *OutboxAuditEmitter) Emit(ctx context.Context, entry AuditEntry) error {
+	if err := e.store.EnqueueAuditEvent(ctx, entry); err != nil {
+		e.dropped.Add(1)
+		return err
+	}
+	return nil
+}
+
+// Counters implements HealthCounter: events that could not be written.
+func (e *OutboxAuditEmitter) Counters() map[string]int64 {
+	return map[string]int64{"audit_events_dropped": e.dropped.Load()}
+}
+
+// AuditRelay moves events from the outbox to the audit log. Events that fail
+// to move stay in the outbox and are retried by the store with backoff.
+type AuditRelay struct {
+	store     Store
+	batchSize int
+
+	relayed  atomic.Int64
+	failures atomic.Int64
+	pending  atomic.Int64
+	failing  atomic.Int64
+
+	mu      sync.Mutex
+	lastErr error
 }
 
-// AsyncAuditEmitter buffers events and writes them asynchronously.
-type AsyncAuditEmitter struct {
-	store Store
-	ch    chan auditWork
+// NewAuditRelay creates a relay moving batchSize events per transaction.
+func NewAuditRelay(store Store, batchSize int) *AuditRelay {
+	return &AuditRelay{store: store, batchSize: batchSize}
 }
 
-type auditWork struct {
-	entry AuditEntry
+// Relay moves due events until the outbox has none left, and returns how
+// many were moved.
+func (r *AuditRelay) Relay(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		moved, failed, err := r.store.RelayAuditEvents(ctx, r.batchSize)
+		r.relayed.Add(int64(moved))
+		r.failures.Add(int64(failed))
+		total += moved
+		if err != nil {
+			r.failures.Add(1)
+			r.setErr(err)
+			return total, err
+		}
+		if moved+failed < r.batchSize {
+			break
+		}
+	}
+
+	pending, failing, err := r.store.CountAuditOutbox(ctx)
+	if err == nil {
+		r.pending.Store(pending)
+		r.failing.Store(failing)
+	}
+	r.setErr(err)
+	return total, err
+}
+
+// Run relays every interval until ctx is done. Call Drain after it returns
+// to move what is left.
+func (r *AuditRelay) Run(ctx context.Context, interval time.Duration) {
+	w := wool.Get(ctx).In("AuditRelay.Run")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Relay(ctx); err != nil {
+				w.Warn("cannot relay audit events, will retry", wool.ErrField(err))
+			}
+		}
+	}
 }
 
-func NewAsyncAuditEmitter(store Store, bufferSize int) *AsyncAuditEmitter {
-	e := &AsyncAuditEmitter{
-		store: store,
-		ch:    make(chan auditWork, bufferSize),
+// Drain relays what is left at shutdown, until ctx is done. Events still in
+// the outbox are relayed after the next start.
+func (r *AuditRelay) Drain(ctx context.Context) error {
+	w := wool.Get(ctx).In("AuditRelay.Drain")
+
+	moved, err := r.Relay(ctx)
+	if err != nil {
+		return w.Wrapf(err, "cannot drain audit outbox")
 	}
-	go e.drain()
-	return e
+	if pending := r.pending.Load(); pending > 0 {
+		w.Warn("audit events left in outbox", wool.Field("relayed", moved), wool.Field("pending", pending))
+	}
+	return nil
 }
 
-func (e *AsyncAuditEmitter) Emit(_ context.Context, entry AuditEntry) {
-	select {
-	case e.ch <- auditWork{entry: entry}:
-	default:
-		// Buffer full — drop rather than block business logic
+// Counters implements HealthCounter. Failures count events that failed to
+// move and relay runs that failed.
+func (r *AuditRelay) Counters() map[string]int64 {
+	return map[string]int64{
+		"audit_events_relayed": r.relayed.Load(),
+		"audit_relay_failures": r.failures.Load(),
+		"audit_outbox_pending": r.pending.Load(),
+		"audit_outbox_failing": r.failing.Load(),
 	}
 }
 
-func (e *AsyncAuditEmitter) drain() {
-	for w := range e.ch {
-		_ = e.store.InsertAuditEvent(context.Background(), w.entry)
+// Health implements HealthChecker: the last run failed, or events can't be
+// moved.
+func (r *AuditRelay) Health(ctx context.Context) error {
+	r.mu.Lock()
+	lastErr := r.lastErr
+	r.mu.Unlock()
+	if lastErr != nil {
+		return lastErr
+	}
+	if failing := r.failing.Load(); failing > 0 {
+		return wool.Get(ctx).In("AuditRelay.Health").NewError("%d audit events cannot be relayed", failing)
 	}
+	return nil
 }
 
-func (e *AsyncAuditEmitter) Close() {
-	close(e.ch)
+func (r *AuditRelay) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastErr = err
 }
 
 // QueryAuditLog delegates to the store.
@@ -67,17 +180,27 @@ func (s *Service) QueryAuditLog(ctx context.Context, orgID, actorID, action, res
 	return s.store.QueryAuditLog(ctx, orgID, actorID, action, resource, resourceID, from, to, pageSize, pageToken)
 }
 
-// emit is a convenience method on Service for audit emission.
-func (s *Service) emit(ctx context.Context, actorID, actorType, action, resource, resourceID, orgID string) {
+// emit is a convenience method on Service for audit emission. Call it in the
+// transaction of the change it records. The request context of ctx, when set,
+// gives the actor (unless the event is the system's), the client's address,
+// user agent, trace and request IDs.
+func (s *Service) emit(ctx context.Context, actorID, actorType, action, resource, resourceID, orgID string, opts ...AuditOption) error {
 	if s.audit == nil {
-		return
+		return nil
 	}
-	s.audit.Emit(ctx, AuditEntry{
+	entry := AuditEntry{
 		ActorID:    actorID,
 		ActorType:  actorType,
 		Action:     action,
 		Resource:   resource,
 		ResourceID: resourceID,
 		OrgID:      orgID,
-	})
+	}
+	if rc, ok := RequestContextFrom(ctx); ok {
+		rc.applyTo(&entry)
+	}
+	for _, opt := range opts {
+		opt(&entry)
+	}
+	return s.audit.Emit(ctx, entry)
 }
@@ -0,0 +1,268 @@
+package business
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/codefly-dev/core/wool"
+	"github.com/google/uuid"
+
+	"backend/pkg/gen"
+)
+
+// auditVerifyPageSize is how many chained events VerifyAuditChain reads at once.
+const auditVerifyPageSize = 1000
+
+// AuditCheckpoint commits to an org's audit chain up to an event. Signature
+// is made with the token signing key, so checkpoints can be verified offline
+// against the JWKS.
+type AuditCheckpoint struct {
+	ID        string
+	OrgID     string
+	ChainSeq  int64
+	Hash      string
+	Signature string
+	CreatedAt time.Time
+}
+
+// AuditEventHash chains an event to the previous one of its org: the hex
+// SHA-256 of the previous hash, a newline, and the event as JSON with fixed
+// field order, no metadata as {} and the time in UTC microseconds.
+func AuditEventHash(e AuditEntry) string {
+	metadata := e.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	canonical, _ := json.Marshal(struct {
+		ID         string            `json:"id"`
+		ChainSeq   int64             `json:"chain_seq"`
+		OrgID      string            `json:"org_id"`
+		ActorID    string            `json:"actor_id"`
+		ActorType  string            `json:"actor_type"`
+		Action     string            `json:"action"`
+		Resource   string            `json:"resource"`
+		ResourceID string            `json:"resource_id"`
+		Metadata   map[string]string `json:"metadata"`
+		IPAddress  string            `json:"ip_address"`
+		CreatedAt  string            `json:"created_at"`
+	}{
+		ID:         e.ID,
+		ChainSeq:   e.ChainSeq,
+		OrgID:      e.OrgID,
+		ActorID:    e.ActorID,
+		ActorType:  e.ActorType,
+		Action:     e.Action,
+		Resource:   e.Resource,
+		ResourceID: e.ResourceID,
+		Metadata:   metadata,
+		IPAddress:  e.IPAddress,
+		CreatedAt:  e.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000Z"),
+	})
+	h := sha256.New()
+	h.Write([]byte(e.PrevHash))
+	h.Write([]byte("\n"))
+	h.Write(canonical)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyAuditChain recomputes the chain of an org (events without an org when
+// orgID is empty) over the events recorded between from and to, and reports
+// the first break: an event altered, removed, or not matching a signed
+// checkpoint. Without to, the chain must also reach its head and cover every
+// checkpoint, so events removed from the end are detected. Archived events
+// are not checked: the chain starts at its oldest retained event.
+func (s *Service) VerifyAuditChain(ctx context.Context, orgID string, from, to *time.Time) (*gen.VerifyAuditChainResponse, error) {
+	w := wool.Get(ctx).In("VerifyAuditChain")
+
+	resp := &gen.VerifyAuditChainResponse{Valid: true}
+	first, last, err := s.store.AuditChainRange(ctx, orgID, from, to)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot find audit events")
+	}
+	fail := func(seq int64, eventID, reason string) (*gen.VerifyAuditChainResponse, error) {
+		resp.Valid = false
+		resp.FirstBreak = &gen.AuditChainBreak{ChainSeq: seq, EventId: eventID, Reason: reason}
+		return resp, nil
+	}
+
+	var headSeq int64
+	var headHash string
+	if to == nil {
+		headSeq, headHash, err = s.store.GetAuditChainHead(ctx, orgID)
+		if err != nil {
+			return nil, w.Wrapf(err, "cannot get audit chain head")
+		}
+		if first == 0 && headSeq > 0 {
+			// Nothing since from: the chain must still end at its head
+			first, last = headSeq+1, headSeq
+		}
+	}
+	if first == 0 {
+		return resp, nil
+	}
+
+	// The event before the range anchors the first link, unless archived:
+	// the chain then starts unanchored at its oldest retained event
+	start := max(first-1, 1)
+	oldest := first
+	if from != nil && start > 1 {
+		oldest, _, err = s.store.AuditChainRange(ctx, orgID, nil, nil)
+		if err != nil {
+			return nil, w.Wrapf(err, "cannot find audit events")
+		}
+	}
+	start = max(start, oldest)
+	maxSeq := last
+	if to == nil {
+		maxSeq = math.MaxInt64
+	}
+	checkpoints, err := s.store.ListAuditCheckpoints(ctx, orgID, start, maxSeq)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot list audit checkpoints")
+	}
+	bySeq := map[int64]*AuditCheckpoint{}
+	for _, cp := range checkpoints {
+		if cp.ChainSeq > last {
+			return fail(last+1, "", "event missing: a signed checkpoint covers the chain up to "+formatSeq(cp.ChainSeq))
+		}
+		bySeq[cp.ChainSeq] = cp
+	}
+
+	expected, prevHash := start, ""
+	for expected <= last {
+		events, err := s.store.ListAuditChain(ctx, orgID, expected-1, auditVerifyPageSize)
+		if err != nil {
+			return nil, w.Wrapf(err, "cannot list audit events")
+		}
+		if len(events) == 0 {
+			return fail(expected, "", "event missing")
+		}
+		for _, e := range events {
+			if e.ChainSeq > last {
+				break
+			}
+			if e.ChainSeq != expected {
+				return fail(expected, "", "event missing")
+			}
+			if e.ChainSeq > start || start == 1 {
+				if e.PrevHash != prevHash {
+					return fail(e.ChainSeq, e.ID, "previous hash does not match the previous event")
+				}
+			}
+			if AuditEventHash(e) != e.Hash {
+				return fail(e.ChainSeq, e.ID, "event does not match its hash")
+			}
+			if cp, ok := bySeq[e.ChainSeq]; ok {
+				if reason := s.checkAuditCheckpoint(cp, e); reason != "" {
+					return fail(e.ChainSeq, e.ID, reason)
+				}
+				resp.CheckpointsChecked++
+			}
+			prevHash = e.Hash
+			expected++
+			resp.Checked++
+		}
+	}
+
+	if to == nil {
+		if headSeq > last {
+			return fail(last+1, "", "event missing: the chain head is at "+formatSeq(headSeq))
+		}
+		// Nothing is checked when every event up to the head is archived
+		if headSeq == last && resp.Checked > 0 && headHash != prevHash {
+			return fail(last, "", "chain head does not match the last event")
+		}
+	}
+	return resp, nil
+}
+
+// checkAuditCheckpoint checks that a checkpoint commits to the event and,
+// with a token signer, its signature; it returns why it doesn't, or "".
+func (s *Service) checkAuditCheckpoint(cp *AuditCheckpoint, e AuditEntry) string {
+	if cp.Hash != e.Hash {
+		return "event does not match the signed checkpoint"
+	}
+	if s.tokenSigner == nil {
+		return ""
+	}
+	signed, err := s.tokenSigner.VerifyAuditCheckpoint(cp.Signature)
+	if err != nil {
+		return "checkpoint signature is invalid"
+	}
+	if signed.OrgID != cp.OrgID || signed.ChainSeq != cp.ChainSeq || signed.Hash != cp.Hash {
+		return "checkpoint does not match its signature"
+	}
+	return ""
+}
+
+// CreateAuditCheckpoints signs the head of every chain that grew since its
+// last checkpoint, and returns how many checkpoints were made.
+func (s *Service) CreateAuditCheckpoints(ctx context.Context) (int, error) {
+	w := wool.Get(ctx).In("CreateAuditCheckpoints")
+
+	if s.tokenSigner == nil {
+		return 0, w.NewError("token signer not configured")
+	}
+	heads, err := s.store.ListUncheckpointedAuditChains(ctx)
+	if err != nil {
+		return 0, w.Wrapf(err, "cannot list audit chains")
+	}
+	for i, cp := range heads {
+		cp.ID = uuid.New().String()
+		cp.CreatedAt = time.Now()
+		cp.Signature, err = s.tokenSigner.SignAuditCheckpoint(*cp)
+		if err != nil {
+			return i, w.Wrapf(err, "cannot sign audit checkpoint")
+		}
+		if err := s.store.CreateAuditCheckpoint(ctx, cp); err != nil {
+			return i, w.Wrapf(err, "cannot store audit checkpoint")
+		}
+	}
+	return len(heads), nil
+}
+
+// RunAuditCheckpoints calls CreateAuditCheckpoints every interval until ctx
+// is done.
+func (s *Service) RunAuditCheckpoints(ctx context.Context, interval time.Duration) {
+	w := wool.Get(ctx).In("RunAuditCheckpoints")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CreateAuditCheckpoints(ctx); err != nil {
+				w.Warn("cannot create audit checkpoints", wool.ErrField(err))
+			}
+		}
+	}
+}
+
+// ListAuditCheckpoints returns the checkpoints of an org's chain covering the
+// events recorded between from and to.
+func (s *Service) ListAuditCheckpoints(ctx context.Context, orgID string, from, to *time.Time) ([]*AuditCheckpoint, error) {
+	w := wool.Get(ctx).In("ListAuditCheckpoints")
+
+	first, last, err := s.store.AuditChainRange(ctx, orgID, from, to)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot find audit events")
+	}
+	if first == 0 {
+		return nil, nil
+	}
+	if to == nil {
+		last = math.MaxInt64
+	}
+	return s.store.ListAuditCheckpoints(ctx, orgID, first, last)
+}
+
+func formatSeq(seq int64) string {
+	return "chain_seq " + strconv.FormatInt(seq, 10)
+}
@@ -0,0 +1,177 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/codefly-dev/core/wool"
+)
+
+const (
+	// auditRetentionFeature is the entitlement giving how many days an org's
+	// audit events are kept; unlimited keeps them for ever.
+	auditRetentionFeature = "audit_retention_days"
+	// defaultAuditRetention applies to events without an org, and to plans
+	// without the entitlement.
+	defaultAuditRetention = 365 * 24 * time.Hour
+
+	// auditPartitionsAhead is how many months of partitions are created
+	// ahead of the current one.
+	auditPartitionsAhead = 3
+)
+
+// AuditPartition is a month of the audit log.
+type AuditPartition struct {
+	Name     string
+	Month    time.Time // first day, UTC
+	Attached bool      // false once detached for archival
+}
+
+// End is when the partition's month ends.
+func (p AuditPartition) End() time.Time {
+	return p.Month.AddDate(0, 1, 0)
+}
+
+// AuditArchive stores the events of expired audit partitions.
+type AuditArchive interface {
+	// Store keeps what write writes as the archive of a partition, once
+	// write returns without error, replacing any previous archive.
+	Store(ctx context.Context, name string, write func(out io.Writer) error) error
+}
+
+// EnsureAuditPartitions creates the partitions of the current month and of
+// the next auditPartitionsAhead ones, when missing.
+func (s *Service) EnsureAuditPartitions(ctx context.Context) error {
+	w := wool.Get(ctx).In("EnsureAuditPartitions")
+
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= auditPartitionsAhead; i++ {
+		if _, err := s.store.CreateAuditPartition(ctx, month.AddDate(0, i, 0)); err != nil {
+			return w.Wrapf(err, "cannot create audit partition")
+		}
+	}
+	return nil
+}
+
+// ArchiveAuditPartitions archives the partitions whose events are all past
+// their org's retention: each is detached, written to the archive as NDJSON
+// (the ExportAuditLog format) and dropped. Events are never deleted one by
+// one, so retention is a minimum: see auditPartitionExpired. Partitions
+// detached by an interrupted run are archived too. Without an archive, events
+// are kept. It returns how many partitions were archived.
+func (s *Service) ArchiveAuditPartitions(ctx context.Context) (int, error) {
+	w := wool.Get(ctx).In("ArchiveAuditPartitions")
+
+	if s.auditArchive == nil {
+		return 0, nil
+	}
+	partitions, err := s.store.ListAuditPartitions(ctx)
+	if err != nil {
+		return 0, w.Wrapf(err, "cannot list audit partitions")
+	}
+
+	now := time.Now()
+	archived := 0
+	for _, p := range partitions {
+		if p.Attached {
+			if p.End().After(now) {
+				break
+			}
+			expired, err := s.auditPartitionExpired(ctx, p, now)
+			if err != nil {
+				return archived, err
+			}
+			if !expired {
+				continue
+			}
+			if err := s.store.DetachAuditPartition(ctx, p.Name); err != nil {
+				return archived, w.Wrapf(err, "cannot detach audit partition %s", p.Name)
+			}
+		}
+
+		err := s.auditArchive.Store(ctx, p.Name, func(out io.Writer) error {
+			encoder := json.NewEncoder(out)
+			return s.store.ScanAuditPartition(ctx, p.Name, func(e AuditEntry) error {
+				return encoder.Encode(newAuditExportRecord(e))
+			})
+		})
+		if err != nil {
+			return archived, w.Wrapf(err, "cannot archive audit partition %s", p.Name)
+		}
+		if err := s.store.DropAuditPartition(ctx, p.Name); err != nil {
+			return archived, w.Wrapf(err, "cannot drop audit partition %s", p.Name)
+		}
+		archived++
+	}
+	return archived, nil
+}
+
+// auditPartitionExpired tells whether every org with events in the partition
+// keeps them for less than the time since the partition's month ended.
+// Partitions are shared by all orgs: an org's events stay as long as those of
+// the org with the longest retention in the same month, for ever when it is
+// unlimited. Retention guarantees events are kept, not that they are gone.
+func (s *Service) auditPartitionExpired(ctx context.Context, p AuditPartition, now time.Time) (bool, error) {
+	w := wool.Get(ctx).In("auditPartitionExpired")
+
+	orgIDs, err := s.store.ListAuditPartitionOrgs(ctx, p.Name)
+	if err != nil {
+		return false, w.Wrapf(err, "cannot list orgs of audit partition %s", p.Name)
+	}
+	for _, orgID := range orgIDs {
+		retention, err := s.auditRetention(ctx, orgID)
+		if err != nil {
+			return false, err
+		}
+		if retention < 0 || p.End().Add(retention).After(now) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// auditRetention is how long an org's events are kept, negative for ever:
+// per plan, or per org with an entitlement override.
+func (s *Service) auditRetention(ctx context.Context, orgID string) (time.Duration, error) {
+	w := wool.Get(ctx).In("auditRetention")
+
+	if orgID == "" || s.entitlements == nil {
+		return defaultAuditRetention, nil
+	}
+	days, err := s.entitlements.GetLimit(ctx, orgID, auditRetentionFeature)
+	if err != nil {
+		return 0, w.Wrapf(err, "cannot get audit retention")
+	}
+	switch {
+	case days < 0:
+		return -1, nil
+	case days == 0:
+		return defaultAuditRetention, nil
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// RunAuditMaintenance creates audit partitions ahead and archives expired
+// ones, now and every interval, until ctx is done.
+func (s *Service) RunAuditMaintenance(ctx context.Context, interval time.Duration) {
+	w := wool.Get(ctx).In("RunAuditMaintenance")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.EnsureAuditPartitions(ctx); err != nil {
+			w.Warn("cannot create audit partitions", wool.ErrField(err))
+		}
+		if _, err := s.ArchiveAuditPartitions(ctx); err != nil {
+			w.Warn("cannot archive audit partitions", wool.ErrField(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package business
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codefly-dev/core/wool"
+
+	"backend/pkg/gen"
+)
+
+const (
+	// auditLogFeature is the entitlement to watch and export the audit log.
+	auditLogFeature = "audit_log"
+
+	// auditWatchInterval is how often WatchAuditLog looks for new events.
+	auditWatchInterval = time.Second
+	auditWatchPageSize = 500
+
+	auditExportPageSize = 1000
+	// auditExportChunkSize is the size of the chunks ExportAuditLog writes.
+	auditExportChunkSize = 64 * 1024
+)
+
+// AuditFilter selects audit events: the filters of QueryAuditLog.
+type AuditFilter struct {
+	OrgID      string
+	ActorID    string
+	Action     string
+	Resource   string
+	ResourceID string
+	From       *time.Time
+	To         *time.Time
+}
+
+func (f AuditFilter) matches(e AuditEntry) bool {
+	switch {
+	case f.ActorID != "" && e.ActorID != f.ActorID,
+		f.Action != "" && e.Action != f.Action,
+		f.Resource != "" && e.Resource != f.Resource,
+		f.ResourceID != "" && e.ResourceID != f.ResourceID,
+		f.From != nil && e.CreatedAt.Before(*f.From),
+		f.To != nil && e.CreatedAt.After(*f.To):
+		return false
+	}
+	return true
+}
+
+// requireAuditLog checks that the org's plan includes the audit log.
+func (s *Service) requireAuditLog(ctx context.Context, orgID string) error {
+	w := wool.Get(ctx).In("requireAuditLog")
+
+	if s.entitlements == nil {
+		return nil
+	}
+	ok, err := s.entitlements.HasFeature(ctx, orgID, auditLogFeature)
+	if err != nil {
+		return w.Wrapf(err, "cannot check audit log entitlement")
+	}
+	if !ok {
+		return w.NewError("the audit log is not included in your plan")
+	}
+	return nil
+}
+
+// WatchAuditLog sends the org's events matching the filter as they are
+// recorded, until ctx is done or send fails. Each event comes with a resume
+// token; given one, watching resumes after that event, otherwise from now.
+// Events are followed in chain order, so none is skipped on resume.
+func (s *Service) WatchAuditLog(ctx context.Context, filter AuditFilter, resumeToken string,
+	send func(e AuditEntry, resumeToken string) error) error {
+	w := wool.Get(ctx).In("WatchAuditLog")
+
+	if err := s.requireAuditLog(ctx, filter.OrgID); err != nil {
+		return err
+	}
+
+	var after int64
+	if resumeToken != "" {
+		orgID, seq, ok := parseAuditResumeToken(resumeToken)
+		if !ok || orgID != filter.OrgID {
+			return w.NewError("invalid resume token")
+		}
+		after = seq
+	} else {
+		seq, _, err := s.store.GetAuditChainHead(ctx, filter.OrgID)
+		if err != nil {
+			return w.Wrapf(err, "cannot get audit chain head")
+		}
+		after = seq
+	}
+
+	ticker := time.NewTicker(auditWatchInterval)
+	defer ticker.Stop()
+	for {
+		events, err := s.store.ListAuditChain(ctx, filter.OrgID, after, auditWatchPageSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return w.Wrapf(err, "cannot list audit events")
+		}
+		for _, e := range events {
+			after = e.ChainSeq
+			if !filter.matches(e) {
+				continue
+			}
+			if err := send(e, auditResumeToken(filter.OrgID, e.ChainSeq)); err != nil {
+				return err
+			}
+		}
+		if len(events) == auditWatchPageSize {
+			// Catching up
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// auditResumeToken is an opaque position in an org's chain.
+func auditResumeToken(orgID string, seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(orgID + ":" + strconv.FormatInt(seq, 10)))
+}
+
+func parseAuditResumeToken(token string) (string, int64, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", 0, false
+	}
+	orgID, encodedSeq, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseInt(encodedSeq, 10, 64)
+	if err != nil || seq < 0 {
+		return "", 0, false
+	}
+	return orgID, seq, true
+}
+
+// ExportAuditLog writes the org's events matching the filter to out, oldest
+// first, as NDJSON or CSV. Events are read a page at a time and written in
+// chunks, so exports of any size use bounded memory.
+func (s *Service) ExportAuditLog(ctx context.Context, filter AuditFilter, format gen.AuditExportFormat, out io.Writer) error {
+	w := wool.Get(ctx).In("ExportAuditLog")
+
+	if err := s.requireAuditLog(ctx, filter.OrgID); err != nil {
+		return err
+	}
+
+	buffered := bufio.NewWriterSize(out, auditExportChunkSize)
+	var write func(e AuditEntry) error
+	var csvWriter *csv.Writer
+	if format == gen.AuditExportFormat_AUDIT_EXPORT_FORMAT_CSV {
+		csvWriter = csv.NewWriter(buffered)
+		if err := csvWriter.Write(auditExportColumns); err != nil {
+			return w.Wrapf(err, "cannot write export")
+		}
+		write = func(e AuditEntry) error {
+			return csvWriter.Write(auditExportRow(e))
+		}
+	} else {
+		encoder := json.NewEncoder(buffered)
+		write = func(e AuditEntry) error {
+			return encoder.Encode(newAuditExportRecord(e))
+		}
+	}
+
+	var after *AuditEntry
+	for {
+		events, err := s.store.ListAuditEventsAfter(ctx, filter, after, auditExportPageSize)
+		if err != nil {
+			return w.Wrapf(err, "cannot list audit events")
+		}
+		for _, e := range events {
+			if err := write(e); err != nil {
+				return w.Wrapf(err, "cannot write export")
+			}
+		}
+		if len(events) < auditExportPageSize {
+			break
+		}
+		after = &events[len(events)-1]
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return w.Wrapf(err, "cannot write export")
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return w.Wrapf(err, "cannot write export")
+	}
+	return nil
+}
+
+// auditExportRecord is an exported event, with its chain fields so exports
+// can be checked against signed checkpoints offline.
+type auditExportRecord struct {
+	ID         string            `json:"id"`
+	CreatedAt  string            `json:"created_at"`
+	OrgID      string            `json:"org_id"`
+	ActorID    string            `json:"actor_id"`
+	ActorType  string            `json:"actor_type"`
+	Action     string            `json:"action"`
+	Resource   string            `json:"resource"`
+	ResourceID string            `json:"resource_id"`
+	Metadata   map[string]string `json:"metadata"`
+	IPAddress  string            `json:"ip_address"`
+	ChainSeq   int64             `json:"chain_seq"`
+	PrevHash   string            `json:"prev_hash"`
+	Hash       string            `json:"hash"`
+}
+
+// auditExportColumns are the CSV columns, in the order of the JSON fields.
+var auditExportColumns = []string{
+	"id", "created_at", "org_id", "actor_id", "actor_type", "action", "resource", "resource_id",
+	"metadata", "ip_address", "chain_seq", "prev_hash", "hash",
+}
+
+func newAuditExportRecord(e AuditEntry) auditExportRecord {
+	metadata := e.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return auditExportRecord{
+		ID:         e.ID,
+		CreatedAt:  e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		OrgID:      e.OrgID,
+		ActorID:    e.ActorID,
+		ActorType:  e.ActorType,
+		Action:     e.Action,
+		Resource:   e.Resource,
+		ResourceID: e.ResourceID,
+		Metadata:   metadata,
+		IPAddress:  e.IPAddress,
+		ChainSeq:   e.ChainSeq,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+	}
+}
+
+// auditExportRow is a CSV row; metadata is a JSON object.
+func auditExportRow(e AuditEntry) []string {
+	r := newAuditExportRecord(e)
+	metadata, _ := json.Marshal(r.Metadata)
+	return []string{
+		r.ID, r.CreatedAt, r.OrgID, r.ActorID, r.ActorType, r.Action, r.Resource, r.ResourceID,
+		string(metadata), r.IPAddress, strconv.FormatInt(r.ChainSeq, 10), r.PrevHash, r.Hash,
+	}
+}
@@ -15,8 +15,17 @@ import (
 // TokenSigner abstracts JWT signing and refresh token generation.
 type TokenSigner interface {
 	SignAccessToken(userID, orgID string, roles []string) (string, error)
+	AccessTokenID(token string) (id string, expiresAt time.Time, err error)
 	GenerateRefreshToken() (plaintext string, hash string, err error)
 	JWKS() (string, error)
+	SignAuditCheckpoint(cp AuditCheckpoint) (string, error)
+	VerifyAuditCheckpoint(signature string) (*AuditCheckpoint, error)
+}
+
+// TokenRevoker denylists access tokens before they expire.
+type TokenRevoker interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userID string, before time.Time) error
 }
 
 const refreshTokenTTL = 30 * 24 * time.Hour
@@ -90,12 +99,16 @@ func (s *Service) Authenticate(ctx context.Context, req *gen.AuthenticateRequest
 		IPAddress:        "",
 		ExpiresAt:        time.Now().Add(refreshTokenTTL),
 	}
-	if err := s.store.CreateSession(ctx, session); err != nil {
-		return nil, w.Wrapf(err, "cannot create session")
+	err = s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.CreateSession(ctx, session); err != nil {
+			return w.Wrapf(err, "cannot create session")
+		}
+		return s.emit(ctx, userID, "user", "auth.login", "session", session.ID, orgID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	s.emit(ctx, userID, "user", "auth.login", "session", session.ID, orgID)
-
 	return &gen.AuthenticateResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshPlaintext,
@@ -129,6 +142,10 @@ func (s *Service) RefreshToken(ctx context.Context, req *gen.RefreshTokenRequest
 	if session.RevokedAt != nil {
 		// Revoke the entire family to protect against token theft
 		_ = s.store.RevokeSessionFamily(ctx, session.FamilyID, "reuse_detected")
+		// Access tokens minted from the stolen family are still live — cut them off too
+		if err := s.RevokeUserTokens(ctx, session.UserID); err != nil {
+			w.Warn("cannot revoke access tokens", wool.ErrField(err))
+		}
 		return nil, w.NewError("refresh token reuse detected")
 	}
 
@@ -195,9 +212,19 @@ func (s *Service) RefreshToken(ctx context.Context, req *gen.RefreshTokenRequest
 }
 
 // Logout revokes the session associated with the given refresh token.
+// If the access token is supplied, it is denylisted until it expires.
 func (s *Service) Logout(ctx context.Context, req *gen.LogoutRequest) error {
 	w := wool.Get(ctx).In("Logout")
 
+	if req.AccessToken != "" && s.tokenSigner != nil && s.revoker != nil {
+		// An invalid or expired access token has nothing left to revoke
+		if id, expiresAt, err := s.tokenSigner.AccessTokenID(req.AccessToken); err == nil {
+			if err := s.revoker.RevokeToken(ctx, id, expiresAt); err != nil {
+				return w.Wrapf(err, "cannot revoke access token")
+			}
+		}
+	}
+
 	h := sha256.Sum256([]byte(req.RefreshToken))
 	hash := hex.EncodeToString(h[:])
 
@@ -212,6 +239,15 @@ func (s *Service) Logout(ctx context.Context, req *gen.LogoutRequest) error {
 	return s.store.RevokeSession(ctx, session.ID, "logout")
 }
 
+// RevokeUserTokens invalidates every access token issued to the user so far.
+// A no-op when no revoker is configured.
+func (s *Service) RevokeUserTokens(ctx context.Context, userID string) error {
+	if s.revoker == nil {
+		return nil
+	}
+	return s.revoker.RevokeUserTokens(ctx, userID, time.Now())
+}
+
 // GetJWKS returns the JSON Web Key Set.
 func (s *Service) GetJWKS(ctx context.Context) (string, error) {
 	if s.tokenSigner == nil {
@@ -123,7 +123,7 @@ func (c *DefaultEntitlementChecker) CheckQuota(ctx context.Context, orgID string
 		}
 		used = int64(len(members)) + int64(pending)
 	case "api_keys":
-		keys, _, err := c.store.ListAPIKeys(ctx, orgID, 1000, "")
+		keys, _, err := c.store.ListAPIKeys(ctx, orgID, nil, 1000, "")
 		if err != nil {
 			return false, err
 		}
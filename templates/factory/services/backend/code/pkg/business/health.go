@@ -0,0 +1,90 @@
+package business
+
+import (
+	"context"
+	"time"
+
+	"github.com/codefly-dev/core/wool"
+
+	"backend/pkg/gen"
+)
+
+// Health statuses.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+)
+
+// Component statuses reported in a degraded Health.
+const (
+	HealthNotConfigured = "not configured"
+	HealthUnavailable   = "unavailable"
+)
+
+// healthCheckTimeout bounds each dependency check.
+const healthCheckTimeout = 2 * time.Second
+
+// HealthChecker reports why a dependency can't be used, or nil when it can.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
+// HealthCounter reports counters shown by Health, e.g. events dropped.
+type HealthCounter interface {
+	Counters() map[string]int64
+}
+
+type namedHealthCheck struct {
+	name  string
+	check HealthChecker
+}
+
+// AddHealthCheck registers a dependency reported by Health.
+func (s *Service) AddHealthCheck(name string, check HealthChecker) {
+	s.healthChecks = append(s.healthChecks, namedHealthCheck{name: name, check: check})
+}
+
+// AddHealthCounters registers counters reported by Health.
+func (s *Service) AddHealthCounters(counters HealthCounter) {
+	s.healthCounters = append(s.healthCounters, counters)
+}
+
+// Health reports the service degraded when a registered dependency fails its
+// check or a Vault-backed component could not be set up at startup: API keys
+// can't be hashed without the hasher, nor tokens issued without the signer.
+// It includes the registered counters. Health is unauthenticated: failed
+// components only report their status, the reason is logged.
+func (s *Service) Health(ctx context.Context) *gen.HealthResponse {
+	w := wool.Get(ctx).In("Health")
+	degraded := map[string]string{}
+	if s.hasher == nil {
+		degraded["key_hasher"] = HealthNotConfigured
+	}
+	if s.tokenSigner == nil {
+		degraded["token_signer"] = HealthNotConfigured
+	}
+	for _, c := range s.healthChecks {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := c.check.Health(checkCtx)
+		cancel()
+		if err != nil {
+			w.Warn("health check failed", wool.Field("component", c.name), wool.ErrField(err))
+			degraded[c.name] = HealthUnavailable
+		}
+	}
+
+	var counters map[string]int64
+	for _, c := range s.healthCounters {
+		for name, value := range c.Counters() {
+			if counters == nil {
+				counters = map[string]int64{}
+			}
+			counters[name] = value
+		}
+	}
+
+	if len(degraded) > 0 {
+		return &gen.HealthResponse{Status: HealthDegraded, Degraded: degraded, Counters: counters}
+	}
+	return &gen.HealthResponse{Status: HealthOK, Counters: counters}
+}
@@ -72,12 +72,16 @@ func (s *Service) CreateInvitation(ctx context.Context, inviterID string, req *g
 		ExpiresAt: time.Now().Add(invitationTTL),
 	}
 
-	if err := s.store.CreateInvitation(ctx, inv); err != nil {
-		return nil, w.Wrapf(err, "cannot create invitation")
+	err := s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.CreateInvitation(ctx, inv); err != nil {
+			return w.Wrapf(err, "cannot create invitation")
+		}
+		return s.emit(ctx, inviterID, "user", "invitation.created", "invitation", inv.ID, req.OrgId)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	s.emit(ctx, inviterID, "user", "invitation.created", "invitation", inv.ID, req.OrgId)
-
 	return &gen.CreateInvitationResponse{
 		Invitation:  invitationToProto(inv),
 		InviteToken: plaintext,
@@ -106,14 +110,21 @@ func (s *Service) AcceptInvitation(ctx context.Context, userID string, req *gen.
 		return nil, w.NewError("invitation has expired")
 	}
 
-	// Add user to org
-	if err := s.store.AddOrgMember(ctx, inv.OrgID, userID, inv.Role); err != nil {
-		return nil, w.Wrapf(err, "cannot add member to org")
-	}
+	err = s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		// Add user to org
+		if err := s.store.AddOrgMember(ctx, inv.OrgID, userID, inv.Role); err != nil {
+			return w.Wrapf(err, "cannot add member to org")
+		}
 
-	// Mark accepted
-	if err := s.store.UpdateInvitationStatus(ctx, inv.ID, "accepted", userID); err != nil {
-		return nil, w.Wrapf(err, "cannot update invitation status")
+		// Mark accepted
+		if err := s.store.UpdateInvitationStatus(ctx, inv.ID, "accepted", userID); err != nil {
+			return w.Wrapf(err, "cannot update invitation status")
+		}
+
+		return s.emit(ctx, userID, "user", "invitation.accepted", "invitation", inv.ID, inv.OrgID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	org, err := s.store.GetOrganization(ctx, inv.OrgID)
@@ -121,8 +132,6 @@ func (s *Service) AcceptInvitation(ctx context.Context, userID string, req *gen.
 		return nil, w.Wrapf(err, "cannot get organization")
 	}
 
-	s.emit(ctx, userID, "user", "invitation.accepted", "invitation", inv.ID, inv.OrgID)
-
 	return &gen.AcceptInvitationResponse{Organization: org}, nil
 }
 
@@ -147,8 +156,12 @@ func (s *Service) ListInvitations(ctx context.Context, req *gen.ListInvitationsR
 
 // RevokeInvitation marks an invitation as revoked.
 func (s *Service) RevokeInvitation(ctx context.Context, inviterID string, req *gen.RevokeInvitationRequest) error {
-	s.emit(ctx, inviterID, "user", "invitation.revoked", "invitation", req.Id, "")
-	return s.store.UpdateInvitationStatus(ctx, req.Id, "revoked", "")
+	return s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.UpdateInvitationStatus(ctx, req.Id, "revoked", ""); err != nil {
+			return err
+		}
+		return s.emit(ctx, inviterID, "user", "invitation.revoked", "invitation", req.Id, "")
+	})
 }
 
 func invitationToProto(inv *Invitation) *gen.Invitation {
@@ -0,0 +1,70 @@
+package business
+
+import (
+	"context"
+	"time"
+
+	"github.com/codefly-dev/core/wool"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"backend/pkg/gen"
+)
+
+// Metered features enforced by the auth sidecar.
+const (
+	FeatureAPICallsMonthly         = "api_calls_monthly"
+	FeatureAPIKeyRequestsPerMinute = "api_key_requests_per_minute"
+)
+
+// GetRateLimits returns the API limits for an org and its usage so far this period.
+func (s *Service) GetRateLimits(ctx context.Context, orgID string) (*gen.GetRateLimitsResponse, error) {
+	w := wool.Get(ctx).In("GetRateLimits")
+
+	if s.entitlements == nil {
+		return nil, w.NewError("entitlement checker not configured")
+	}
+
+	monthly, err := s.entitlements.GetLimit(ctx, orgID, FeatureAPICallsMonthly)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot get monthly API call limit")
+	}
+	perMinute, err := s.entitlements.GetLimit(ctx, orgID, FeatureAPIKeyRequestsPerMinute)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot get per-key rate limit")
+	}
+	used, err := s.store.GetUsageForPeriod(ctx, orgID, FeatureAPICallsMonthly, currentPeriod())
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot get API call usage")
+	}
+
+	return &gen.GetRateLimitsResponse{
+		ApiCallsMonthly:         monthly,
+		ApiCallsUsed:            used,
+		ApiKeyRequestsPerMinute: perMinute,
+		PeriodEnd:               timestamppb.New(currentPeriodEnd()),
+	}, nil
+}
+
+// RecordUsageBatch records metered usage for several orgs at once.
+// The batch is all-or-nothing so callers can safely retry it.
+func (s *Service) RecordUsageBatch(ctx context.Context, records []*gen.UsageRecord) error {
+	w := wool.Get(ctx).In("RecordUsageBatch")
+
+	if s.entitlements == nil {
+		return w.NewError("entitlement checker not configured")
+	}
+	return s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		for _, r := range records {
+			if err := s.entitlements.RecordUsage(ctx, r.OrgId, r.Feature, r.Quantity); err != nil {
+				return w.Wrapf(err, "cannot record %s usage for org %s", r.Feature, r.OrgId)
+			}
+		}
+		return nil
+	})
+}
+
+// currentPeriodEnd is the start of the next usage period (see currentPeriod).
+func currentPeriodEnd() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location())
+}
@@ -0,0 +1,43 @@
+package business
+
+import (
+	"context"
+
+	"backend/pkg/gen"
+)
+
+// Notification is a message for some users, delivered out of band (email, chat).
+type Notification struct {
+	Kind    string // "api_key.leaked", ...
+	OrgID   string
+	UserIDs []string
+	Subject string
+	Body    string
+	Data    map[string]string
+}
+
+// Notifier hands notifications to the delivery channel.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// notifyOrgAdmins sends n to the org's admins and owners. It does nothing
+// without a notifier or admins.
+func (s *Service) notifyOrgAdmins(ctx context.Context, n Notification) error {
+	if s.notifier == nil {
+		return nil
+	}
+	members, err := s.store.ListOrgMembers(ctx, n.OrgID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if m.Role == gen.OrgRole_ORG_ROLE_ADMIN || m.Role == gen.OrgRole_ORG_ROLE_OWNER {
+			n.UserIDs = append(n.UserIDs, m.UserId)
+		}
+	}
+	if len(n.UserIDs) == 0 {
+		return nil
+	}
+	return s.notifier.Notify(ctx, n)
+}
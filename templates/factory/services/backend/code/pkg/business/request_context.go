@@ -0,0 +1,176 @@
+package business
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Actor types of audit events.
+const (
+	ActorUser           = "user"
+	ActorAPIKey         = "api_key"
+	ActorServiceAccount = "service_account"
+	// ActorImpersonator is an admin acting as a user
+	ActorImpersonator = "impersonator"
+	ActorSystem       = "system"
+)
+
+// RequestContext is who made a request and from where, as extracted by the
+// gRPC interceptor. emit copies it into audit events.
+type RequestContext struct {
+	ActorID   string
+	ActorType string
+	// UserID is the user the actor acts for: the owner of an API key, the
+	// impersonated user. Same as ActorID for users.
+	UserID    string
+	ClientIP  string
+	UserAgent string
+	TraceID   string
+	RequestID string
+}
+
+type requestContextKey struct{}
+
+// WithRequestContext returns ctx carrying rc.
+func WithRequestContext(ctx context.Context, rc RequestContext) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, rc)
+}
+
+// RequestContextFrom returns the request context of ctx, if any.
+func RequestContextFrom(ctx context.Context) (RequestContext, bool) {
+	rc, ok := ctx.Value(requestContextKey{}).(RequestContext)
+	return rc, ok
+}
+
+// applyTo records the request context in an event. The actor replaces the
+// one given by the call site, unless the event is the system's.
+func (rc RequestContext) applyTo(e *AuditEntry) {
+	if rc.ActorID != "" && e.ActorType != ActorSystem {
+		e.ActorID, e.ActorType = rc.ActorID, rc.ActorType
+		if rc.UserID != "" && rc.UserID != rc.ActorID {
+			setAuditMetadata(e, "on_behalf_of", rc.UserID)
+		}
+	}
+	if rc.ClientIP != "" {
+		e.IPAddress = rc.ClientIP
+	}
+	setAuditMetadata(e, "user_agent", rc.UserAgent)
+	setAuditMetadata(e, "trace_id", rc.TraceID)
+	setAuditMetadata(e, "request_id", rc.RequestID)
+}
+
+func setAuditMetadata(e *AuditEntry, key, value string) {
+	if value == "" {
+		return
+	}
+	if e.Metadata == nil {
+		e.Metadata = map[string]string{}
+	}
+	e.Metadata[key] = value
+}
+
+// AuditOption adds details to an event recorded by emit.
+type AuditOption func(e *AuditEntry)
+
+// WithAuditMetadata sets a metadata entry.
+func WithAuditMetadata(key, value string) AuditOption {
+	return func(e *AuditEntry) {
+		setAuditMetadata(e, key, value)
+	}
+}
+
+// WithAuditDiff records what a change did to a resource: the fields that
+// differ between before and after (protos or JSON-encodable values), as a
+// JSON object of {"field": {"before": ..., "after": ...}} in the "diff"
+// metadata entry. Nothing is recorded when no field changed.
+func WithAuditDiff(before, after any) AuditOption {
+	return func(e *AuditEntry) {
+		diff, err := auditDiff(before, after)
+		if err != nil || len(diff) == 0 {
+			return
+		}
+		encoded, err := json.Marshal(diff)
+		if err != nil {
+			return
+		}
+		setAuditMetadata(e, "diff", string(encoded))
+	}
+}
+
+type auditChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+func auditDiff(before, after any) (map[string]auditChange, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	diff := map[string]auditChange{}
+	for _, name := range names {
+		b, a := beforeFields[name], afterFields[name]
+		if string(b) == string(a) {
+			continue
+		}
+		change := auditChange{Before: json.RawMessage("null"), After: json.RawMessage("null")}
+		if b != nil {
+			change.Before = b
+		}
+		if a != nil {
+			change.After = a
+		}
+		diff[name] = change
+	}
+	return diff, nil
+}
+
+// auditFields are the top-level fields of v, each compacted so equal values
+// compare equal.
+func auditFields(v any) (map[string]json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var encoded []byte
+	var err error
+	if m, ok := v.(proto.Message); ok {
+		encoded, err = protojson.Marshal(m)
+	} else {
+		encoded, err = json.Marshal(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, value); err != nil {
+			return nil, err
+		}
+		fields[name] = compact.Bytes()
+	}
+	return fields, nil
+}
@@ -9,12 +9,19 @@ import (
 )
 
 type Service struct {
-	store        Store
-	hasher       KeyHasher
-	tokenSigner  TokenSigner
-	audit        AuditEmitter
-	entitlements EntitlementChecker
-	features     FeatureChecker
+	store             Store
+	hasher            KeyHasher
+	apiKeyInvalidator APIKeyInvalidator
+	apiKeyUsage       APIKeyUsageRecorder
+	tokenSigner       TokenSigner
+	revoker           TokenRevoker
+	audit             AuditEmitter
+	auditArchive      AuditArchive
+	entitlements      EntitlementChecker
+	features          FeatureChecker
+	notifier          Notifier
+	healthChecks      []namedHealthCheck
+	healthCounters    []HealthCounter
 }
 
 func NewService(store Store) (*Service, error) {
@@ -25,14 +32,30 @@ func (s *Service) SetHasher(h KeyHasher) {
 	s.hasher = h
 }
 
+func (s *Service) SetAPIKeyInvalidator(i APIKeyInvalidator) {
+	s.apiKeyInvalidator = i
+}
+
+func (s *Service) SetAPIKeyUsageRecorder(r APIKeyUsageRecorder) {
+	s.apiKeyUsage = r
+}
+
 func (s *Service) SetTokenSigner(t TokenSigner) {
 	s.tokenSigner = t
 }
 
+func (s *Service) SetTokenRevoker(r TokenRevoker) {
+	s.revoker = r
+}
+
 func (s *Service) SetAuditEmitter(a AuditEmitter) {
 	s.audit = a
 }
 
+func (s *Service) SetAuditArchive(a AuditArchive) {
+	s.auditArchive = a
+}
+
 func (s *Service) SetEntitlementChecker(e EntitlementChecker) {
 	s.entitlements = e
 }
@@ -41,6 +64,10 @@ func (s *Service) SetFeatureChecker(f FeatureChecker) {
 	s.features = f
 }
 
+func (s *Service) SetNotifier(n Notifier) {
+	s.notifier = n
+}
+
 func (s *Service) SetStore(store Store) {
 	s.store = store
 }
@@ -77,45 +104,52 @@ func (s *Service) RegisterUser(ctx context.Context, input *gen.RegisterUserReque
 		identity.ProviderEmail = input.PrimaryEmail
 	}
 
-	// RegisterUser already uses its own transaction for user+identity
-	if err := s.store.RegisterUser(ctx, user, identity); err != nil {
-		return nil, w.Wrapf(err, "cannot register user")
-	}
-
-	// Create a default personal organization
+	// The user, their personal organization, admin role and audit event are
+	// created together, serializable so concurrent registrations of the same
+	// identity can't both succeed
 	orgID := uuid.New().String()
-	org := &gen.Organization{
-		Id:      orgID,
-		Name:    "Personal",
-		Slug:    "personal-" + userID[:8],
-		OwnerId: userID,
-	}
-	if err := s.store.CreateOrganization(ctx, org); err != nil {
-		return nil, w.Wrapf(err, "cannot create default organization")
-	}
+	err := s.store.RunInSerializableTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.RegisterUser(ctx, user, identity); err != nil {
+			return w.Wrapf(err, "cannot register user")
+		}
 
-	// Assign admin role to user in their org
-	roles, err := s.store.ListRoles(ctx, "")
-	if err != nil {
-		return nil, w.Wrapf(err, "cannot list roles")
-	}
-	for _, role := range roles {
-		if role.Name == "admin" && role.BuiltIn {
-			assignment := &gen.RoleAssignment{
-				Id:          uuid.New().String(),
-				SubjectId:   userID,
-				SubjectKind: gen.SubjectKind_SUBJECT_KIND_USER,
-				RoleId:      role.Id,
-				OrgId:       orgID,
-			}
-			if err := s.store.AssignRole(ctx, assignment); err != nil {
-				return nil, w.Wrapf(err, "cannot assign admin role")
+		// Create a default personal organization
+		org := &gen.Organization{
+			Id:      orgID,
+			Name:    "Personal",
+			Slug:    "personal-" + userID[:8],
+			OwnerId: userID,
+		}
+		if err := s.store.CreateOrganization(ctx, org); err != nil {
+			return w.Wrapf(err, "cannot create default organization")
+		}
+
+		// Assign admin role to user in their org
+		roles, err := s.store.ListRoles(ctx, "")
+		if err != nil {
+			return w.Wrapf(err, "cannot list roles")
+		}
+		for _, role := range roles {
+			if role.Name == "admin" && role.BuiltIn {
+				assignment := &gen.RoleAssignment{
+					Id:          uuid.New().String(),
+					SubjectId:   userID,
+					SubjectKind: gen.SubjectKind_SUBJECT_KIND_USER,
+					RoleId:      role.Id,
+					OrgId:       orgID,
+				}
+				if err := s.store.AssignRole(ctx, assignment); err != nil {
+					return w.Wrapf(err, "cannot assign admin role")
+				}
+				break
 			}
-			break
 		}
-	}
 
-	s.emit(ctx, userID, "user", "user.registered", "user", userID, orgID)
+		return s.emit(ctx, userID, "user", "user.registered", "user", userID, orgID)
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &gen.RegisterUserResponse{User: user, Identity: identity}, nil
 }
@@ -154,10 +188,15 @@ func (s *Service) CreateOrganization(ctx context.Context, ownerID string, req *g
 		Slug:    req.Slug,
 		OwnerId: ownerID,
 	}
-	if err := s.store.CreateOrganization(ctx, org); err != nil {
+	err := s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.CreateOrganization(ctx, org); err != nil {
+			return err
+		}
+		return s.emit(ctx, ownerID, "user", "org.created", "organization", org.Id, org.Id)
+	})
+	if err != nil {
 		return nil, err
 	}
-	s.emit(ctx, ownerID, "user", "org.created", "organization", org.Id, org.Id)
 	return &gen.CreateOrganizationResponse{Organization: org}, nil
 }
 
@@ -201,9 +240,14 @@ func (s *Service) AssignRole(ctx context.Context, req *gen.AssignRoleRequest) (*
 		OrgId:       req.OrgId,
 		Scope:       req.Scope,
 	}
-	if err := s.store.AssignRole(ctx, assignment); err != nil {
+	err := s.store.RunInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.AssignRole(ctx, assignment); err != nil {
+			return err
+		}
+		return s.emit(ctx, req.SubjectId, "user", "role.assigned", "role", req.RoleId, req.OrgId)
+	})
+	if err != nil {
 		return nil, err
 	}
-	s.emit(ctx, req.SubjectId, "user", "role.assigned", "role", req.RoleId, req.OrgId)
 	return &gen.AssignRoleResponse{Assignment: assignment}, nil
 }
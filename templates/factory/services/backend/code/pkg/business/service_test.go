@@ -4,23 +4,52 @@ import (
 	"backend/pkg/business"
 	"backend/pkg/gen"
 	"backend/pkg/infra"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	codefly "github.com/codefly-dev/sdk-go"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/codefly-dev/core/sdk"
 	"github.com/codefly-dev/core/wool"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Shared test fixtures — initialized once in TestMain.
 var (
 	testStore   *infra.PostgresStore
 	testService *business.Service
+	testHasher  business.KeyHasher
 	testCtx     context.Context
 	testCleanup func()
 )
@@ -58,18 +87,35 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
-	// Wire optional components
-	vaultClient, err := infra.NewVaultClient(ctx)
-	if err == nil {
-		service.SetHasher(vaultClient)
+	// Key material from the configured provider: local dev keys in the
+	// local configuration, so tests don't need Vault
+	secrets, err := infra.NewSecretsProvider(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "NewSecretsProvider failed: %v\n", err)
+		os.Exit(1)
+	}
+	hasher, err := secrets.KeyHasher(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "KeyHasher failed: %v\n", err)
+		os.Exit(1)
+	}
+	service.SetHasher(hasher)
+	service.AddHealthCheck("secrets", secrets)
+
+	piiCipher, err := secrets.PIICipher(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "PIICipher failed: %v\n", err)
+		os.Exit(1)
 	}
+	store.SetPIICipher(piiCipher)
 
-	tokenService, err := infra.NewTokenService(ctx)
+	// Wire optional components
+	tokenService, err := secrets.TokenService(ctx)
 	if err == nil {
 		service.SetTokenSigner(tokenService)
 	}
 
-	auditEmitter := business.NewAsyncAuditEmitter(store, 1024)
+	auditEmitter := business.NewOutboxAuditEmitter(store)
 	service.SetAuditEmitter(auditEmitter)
 
 	entitlementChecker := business.NewDefaultEntitlementChecker(store)
@@ -77,9 +123,10 @@ func TestMain(m *testing.M) {
 
 	testStore = store
 	testService = service
+	testHasher = hasher
 	testCtx = ctx
 	testCleanup = func() {
-		auditEmitter.Close()
+		_ = secrets.Close()
 		store.Close()
 		deps.Destroy(ctx)
 	}
@@ -141,6 +188,13 @@ func TestRegisterUser_DuplicateIdentity(t *testing.T) {
 		},
 	})
 	require.Error(t, err)
+
+	// Registration can't run at a weaker isolation than serializable
+	err = testStore.RunInTransaction(testCtx, func(ctx context.Context) error {
+		return testStore.RegisterUser(ctx, &gen.User{Uuid: uuid.New().String(), PrimaryEmail: "third@test.com"},
+			&gen.UserIdentity{Uuid: uuid.New().String(), Provider: "google", ProviderId: "google-third"})
+	})
+	require.Error(t, err)
 }
 
 func TestRegisterUser_CreatesDefaultOrg(t *testing.T) {
@@ -390,9 +444,1451 @@ func TestLogout(t *testing.T) {
 	require.Error(t, err, "refresh should fail after logout")
 }
 
+func TestLogout_RevokesAccessToken(t *testing.T) {
+	clearData(t)
+
+	mr := miniredis.RunT(t)
+	testService.SetTokenRevoker(infra.NewRevocationListFromClient(redis.NewClient(&redis.Options{Addr: mr.Addr()})))
+	defer testService.SetTokenRevoker(nil)
+
+	authResp, err := testService.Authenticate(testCtx, &gen.AuthenticateRequest{
+		Provider: "google", ProviderId: "google-revoke", ProviderEmail: "revoke@test.com",
+	})
+	require.NoError(t, err)
+
+	err = testService.Logout(testCtx, &gen.LogoutRequest{
+		RefreshToken: authResp.RefreshToken,
+		AccessToken:  authResp.AccessToken,
+	})
+	require.NoError(t, err)
+
+	claims := &infra.AccessClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(authResp.AccessToken, claims)
+	require.NoError(t, err)
+
+	key := infra.RevokedTokenPrefix + claims.ID
+	require.True(t, mr.Exists(key), "jti should be denylisted")
+	require.LessOrEqual(t, mr.TTL(key), infra.AccessTokenTTL)
+	require.Greater(t, mr.TTL(key), time.Duration(0))
+}
+
+func TestRevokeUserTokens(t *testing.T) {
+	mr := miniredis.RunT(t)
+	testService.SetTokenRevoker(infra.NewRevocationListFromClient(redis.NewClient(&redis.Options{Addr: mr.Addr()})))
+	defer testService.SetTokenRevoker(nil)
+
+	err := testService.RevokeUserTokens(testCtx, "user-1")
+	require.NoError(t, err)
+
+	key := infra.RevokedUserPrefix + "user-1"
+	require.True(t, mr.Exists(key))
+	require.LessOrEqual(t, mr.TTL(key), infra.AccessTokenTTL)
+}
+
 func TestGetJWKS(t *testing.T) {
 	jwks, err := testService.GetJWKS(testCtx)
 	require.NoError(t, err)
 	require.Contains(t, jwks, "Ed25519")
 	require.Contains(t, jwks, "EdDSA")
 }
+
+func TestRateLimitsAndUsage(t *testing.T) {
+	clearData(t)
+
+	_, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "metered@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-metered", ProviderEmail: "metered@test.com",
+		},
+	})
+	require.NoError(t, err)
+	resolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "email", ProviderId: "email-metered",
+	})
+	require.NoError(t, err)
+	orgID := resolved.OrgId
+
+	// Free plan defaults
+	limits, err := testService.GetRateLimits(testCtx, orgID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10000), limits.ApiCallsMonthly)
+	require.Equal(t, int64(60), limits.ApiKeyRequestsPerMinute)
+	require.Equal(t, int64(0), limits.ApiCallsUsed)
+	require.True(t, limits.PeriodEnd.AsTime().After(time.Now()))
+
+	err = testService.RecordUsageBatch(testCtx, []*gen.UsageRecord{
+		{OrgId: orgID, Feature: business.FeatureAPICallsMonthly, Quantity: 40},
+		{OrgId: orgID, Feature: business.FeatureAPICallsMonthly, Quantity: 2},
+	})
+	require.NoError(t, err)
+
+	limits, err = testService.GetRateLimits(testCtx, orgID)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), limits.ApiCallsUsed)
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	clearData(t)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "rotate@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-rotate", ProviderEmail: "rotate@test.com",
+		},
+	})
+	require.NoError(t, err)
+	resolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "email", ProviderId: "email-rotate",
+	})
+	require.NoError(t, err)
+
+	created, err := testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{
+		OrganizationId: resolved.OrgId,
+		Name:           "ci",
+		Scopes:         []*gen.Permission{{Resource: "users", Action: "read"}},
+		Environment:    gen.APIKeyEnvironment_API_KEY_ENVIRONMENT_TEST,
+	})
+	require.NoError(t, err)
+
+	rotated, err := testService.RotateAPIKey(testCtx, resp.User.Uuid, &gen.RotateAPIKeyRequest{
+		Id:                 created.Key.Id,
+		GracePeriodSeconds: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ci", rotated.Key.Name)
+	require.Equal(t, gen.APIKeyEnvironment_API_KEY_ENVIRONMENT_TEST, rotated.Key.Environment)
+	require.Equal(t, created.Key.Id, rotated.Key.RotatedFromId)
+	require.NotEqual(t, created.PlaintextKey, rotated.PlaintextKey)
+	require.NotNil(t, rotated.PreviousKey.RevokeAt)
+
+	// Old key: still valid, but deprecated
+	old, err := testService.ValidateAPIKey(testCtx, created.PlaintextKey, "", "")
+	require.NoError(t, err)
+	require.True(t, old.Valid)
+	require.True(t, old.Deprecated)
+	require.NotEmpty(t, old.Warning)
+
+	successor, err := testService.ValidateAPIKey(testCtx, rotated.PlaintextKey, "", "")
+	require.NoError(t, err)
+	require.True(t, successor.Valid)
+	require.False(t, successor.Deprecated)
+	require.Equal(t, []string{"users:read"}, successor.Scopes)
+
+	// A key is rotated once
+	_, err = testService.RotateAPIKey(testCtx, resp.User.Uuid, &gen.RotateAPIKeyRequest{Id: created.Key.Id})
+	require.Error(t, err)
+
+	// Someone outside the org can't take the key over
+	outsider, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "outsider@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-outsider", ProviderEmail: "outsider@test.com",
+		},
+	})
+	require.NoError(t, err)
+	_, err = testService.RotateAPIKey(testCtx, outsider.User.Uuid, &gen.RotateAPIKeyRequest{Id: rotated.Key.Id})
+	require.Error(t, err)
+	stored, err := testStore.GetAPIKey(testCtx, rotated.Key.Id)
+	require.NoError(t, err)
+	require.Nil(t, stored.RevokeAt, "a refused rotation leaves the key alone")
+
+	// After the grace period the old key is invalid, then revoked by the sweep
+	time.Sleep(2100 * time.Millisecond)
+	old, err = testService.ValidateAPIKey(testCtx, created.PlaintextKey, "", "")
+	require.NoError(t, err)
+	require.False(t, old.Valid)
+
+	n, err := testService.RevokeDueAPIKeys(testCtx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	stored, err = testStore.GetAPIKey(testCtx, created.Key.Id)
+	require.NoError(t, err)
+	require.NotNil(t, stored.RevokedAt)
+}
+
+func TestAPIKeyScopeContainment(t *testing.T) {
+	clearData(t)
+
+	_, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "alice@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-alice-scopes", ProviderEmail: "alice@test.com",
+		},
+	})
+	require.NoError(t, err)
+	viewer, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "viewer@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-viewer-scopes", ProviderEmail: "viewer@test.com",
+		},
+	})
+	require.NoError(t, err)
+	aliceResolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "email", ProviderId: "email-alice-scopes",
+	})
+	require.NoError(t, err)
+	orgID := aliceResolved.OrgId
+
+	err = testService.Store().AddOrgMember(testCtx, orgID, viewer.User.Uuid, "member")
+	require.NoError(t, err)
+	readerRole, err := testService.CreateRole(testCtx, &gen.CreateRoleRequest{
+		Name: "reader", Description: "Can read users", OrgId: orgID,
+		Permissions: []*gen.Permission{{Resource: "users", Action: "read"}},
+	})
+	require.NoError(t, err)
+	_, err = testService.AssignRole(testCtx, &gen.AssignRoleRequest{
+		SubjectId: viewer.User.Uuid, SubjectKind: gen.SubjectKind_SUBJECT_KIND_USER,
+		RoleId: readerRole.Role.Id, OrgId: orgID,
+	})
+	require.NoError(t, err)
+
+	// Scopes beyond the creator's permissions are rejected
+	for _, scope := range []*gen.Permission{{Resource: "*", Action: "*"}, {Resource: "users", Action: "*"}, {Resource: "users", Action: "write"}} {
+		_, err = testService.CreateAPIKey(testCtx, viewer.User.Uuid, &gen.CreateAPIKeyRequest{
+			OrganizationId: orgID, Name: "too-broad", Scopes: []*gen.Permission{scope},
+		})
+		require.Error(t, err, "%s:%s", scope.Resource, scope.Action)
+	}
+
+	created, err := testService.CreateAPIKey(testCtx, viewer.User.Uuid, &gen.CreateAPIKeyRequest{
+		OrganizationId: orgID, Name: "reader",
+		Scopes: []*gen.Permission{{Resource: "users", Action: "read"}},
+	})
+	require.NoError(t, err)
+
+	// Losing the permission disables the key, regaining it re-enables it
+	err = testService.Store().RevokeRole(testCtx, viewer.User.Uuid, readerRole.Role.Id, orgID, "")
+	require.NoError(t, err)
+	// Only a key manager of the org may trigger it
+	_, err = testService.ReevaluateAPIKeys(testCtx, viewer.User.Uuid, orgID)
+	require.Error(t, err)
+
+	reevaluated, err := testService.ReevaluateAPIKeys(testCtx, aliceResolved.UserId, orgID)
+	require.NoError(t, err)
+	require.Empty(t, reevaluated.FailedKeyIds)
+	require.Len(t, reevaluated.Disabled, 1)
+	require.Equal(t, created.Key.Id, reevaluated.Disabled[0].Id)
+
+	validated, err := testService.ValidateAPIKey(testCtx, created.PlaintextKey, "", "")
+	require.NoError(t, err)
+	require.False(t, validated.Valid)
+
+	_, err = testService.AssignRole(testCtx, &gen.AssignRoleRequest{
+		SubjectId: viewer.User.Uuid, SubjectKind: gen.SubjectKind_SUBJECT_KIND_USER,
+		RoleId: readerRole.Role.Id, OrgId: orgID,
+	})
+	require.NoError(t, err)
+	reevaluated, err = testService.ReevaluateAPIKeys(testCtx, aliceResolved.UserId, orgID)
+	require.NoError(t, err)
+	require.Len(t, reevaluated.Enabled, 1)
+
+	validated, err = testService.ValidateAPIKey(testCtx, created.PlaintextKey, "", "")
+	require.NoError(t, err)
+	require.True(t, validated.Valid)
+}
+
+func TestAPIKeyUsage(t *testing.T) {
+	clearData(t)
+
+	usage := business.NewBatchedAPIKeyUsage(testStore, 100)
+	testService.SetAPIKeyUsageRecorder(usage)
+	defer testService.SetAPIKeyUsageRecorder(nil)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "usage@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-usage", ProviderEmail: "usage@test.com",
+		},
+	})
+	require.NoError(t, err)
+	resolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "email", ProviderId: "email-usage",
+	})
+	require.NoError(t, err)
+	orgID := resolved.OrgId
+
+	used, err := testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{OrganizationId: orgID, Name: "used"})
+	require.NoError(t, err)
+	stale, err := testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{OrganizationId: orgID, Name: "stale"})
+	require.NoError(t, err)
+	cutoff := timestamppb.Now()
+
+	for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+		validated, err := testService.ValidateAPIKey(testCtx, used.PlaintextKey, ip, "")
+		require.NoError(t, err)
+		require.True(t, validated.Valid)
+	}
+	require.NoError(t, usage.Flush(testCtx))
+
+	list, err := testService.ListAPIKeys(testCtx, &gen.ListAPIKeysRequest{OrganizationId: orgID, PageSize: 10, UsageDays: 7})
+	require.NoError(t, err)
+	require.Len(t, list.Keys, 2)
+	for _, key := range list.Keys {
+		if key.Id != used.Key.Id {
+			require.Nil(t, key.LastUsedAt)
+			require.Empty(t, key.DailyUsage)
+			continue
+		}
+		require.NotNil(t, key.LastUsedAt)
+		require.Equal(t, "203.0.113.2", key.LastUsedIp)
+		require.Len(t, key.DailyUsage, 1)
+		require.Equal(t, int64(2), key.DailyUsage[0].Requests)
+	}
+
+	// Stale keys: not used since the cutoff, then revoked in bulk
+	list, err = testService.ListAPIKeys(testCtx, &gen.ListAPIKeysRequest{OrganizationId: orgID, PageSize: 10, UnusedSince: cutoff})
+	require.NoError(t, err)
+	require.Len(t, list.Keys, 1)
+	require.Equal(t, stale.Key.Id, list.Keys[0].Id)
+
+	// Only a key manager of the org may revoke its keys
+	outsider, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "outsider@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-outsider", ProviderEmail: "outsider@test.com",
+		},
+	})
+	require.NoError(t, err)
+	_, err = testService.RevokeAPIKeys(testCtx, outsider.User.Uuid, &gen.RevokeAPIKeysRequest{
+		OrganizationId: orgID, Ids: []string{stale.Key.Id},
+	})
+	require.Error(t, err)
+	list, err = testService.ListAPIKeys(testCtx, &gen.ListAPIKeysRequest{OrganizationId: orgID, PageSize: 10, UnusedSince: cutoff})
+	require.NoError(t, err)
+	require.Len(t, list.Keys, 1)
+
+	revoked, err := testService.RevokeAPIKeys(testCtx, resp.User.Uuid, &gen.RevokeAPIKeysRequest{
+		OrganizationId: orgID, Ids: []string{stale.Key.Id},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{stale.Key.Id}, revoked.RevokedIds)
+}
+
+func TestAPIKeyRestrictions(t *testing.T) {
+	clearData(t)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "restricted@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-restricted", ProviderEmail: "restricted@test.com",
+		},
+	})
+	require.NoError(t, err)
+	resolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "email", ProviderId: "email-restricted",
+	})
+	require.NoError(t, err)
+
+	_, err = testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{
+		OrganizationId: resolved.OrgId, Name: "bad", AllowedCidrs: []string{"not-a-cidr"},
+	})
+	require.Error(t, err)
+
+	created, err := testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{
+		OrganizationId: resolved.OrgId,
+		Name:           "office",
+		AllowedCidrs:   []string{"203.0.113.7/24", "2001:db8::1"},
+		AllowedOrigins: []string{"https://*.example.com"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"203.0.113.0/24", "2001:db8::1/128"}, created.Key.AllowedCidrs)
+
+	cases := []struct {
+		ip, origin string
+		reason     gen.APIKeyInvalidReason
+	}{
+		{"203.0.113.9", "https://app.example.com", gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_UNSPECIFIED},
+		{"2001:db8::1", "https://app.example.com/page", gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_UNSPECIFIED},
+		{"198.51.100.1", "https://app.example.com", gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_IP_NOT_ALLOWED},
+		{"", "https://app.example.com", gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_IP_NOT_ALLOWED},
+		{"203.0.113.9", "https://evil.com", gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_ORIGIN_NOT_ALLOWED},
+		{"203.0.113.9", "", gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_ORIGIN_NOT_ALLOWED},
+	}
+	for _, tc := range cases {
+		validated, err := testService.ValidateAPIKey(testCtx, created.PlaintextKey, tc.ip, tc.origin)
+		require.NoError(t, err)
+		require.Equal(t, tc.reason == gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_UNSPECIFIED, validated.Valid, "%s %s", tc.ip, tc.origin)
+		require.Equal(t, tc.reason, validated.Reason, "%s %s", tc.ip, tc.origin)
+	}
+
+	// Someone outside the org can't lift the restrictions
+	outsider, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "outsider@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-outsider", ProviderEmail: "outsider@test.com",
+		},
+	})
+	require.NoError(t, err)
+	_, err = testService.UpdateAPIKey(testCtx, outsider.User.Uuid, &gen.UpdateAPIKeyRequest{
+		Id:         created.Key.Id,
+		Key:        &gen.APIKey{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"allowed_cidrs", "allowed_origins"}},
+	})
+	require.Error(t, err)
+	stored, err := testStore.GetAPIKey(testCtx, created.Key.Id)
+	require.NoError(t, err)
+	require.Equal(t, created.Key.AllowedCidrs, stored.AllowedCidrs)
+	require.Equal(t, created.Key.AllowedOrigins, stored.AllowedOrigins)
+
+	// Lifting the origin restriction
+	updated, err := testService.UpdateAPIKey(testCtx, resp.User.Uuid, &gen.UpdateAPIKeyRequest{
+		Id:         created.Key.Id,
+		Key:        &gen.APIKey{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"allowed_origins"}},
+	})
+	require.NoError(t, err)
+	require.Empty(t, updated.AllowedOrigins)
+	require.Equal(t, "office", updated.Name)
+
+	validated, err := testService.ValidateAPIKey(testCtx, created.PlaintextKey, "203.0.113.9", "")
+	require.NoError(t, err)
+	require.True(t, validated.Valid)
+	require.Equal(t, created.Key.AllowedCidrs, validated.AllowedCidrs)
+}
+
+func TestAPIKeyFormat(t *testing.T) {
+	clearData(t)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "format@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-format", ProviderEmail: "format@test.com",
+		},
+	})
+	require.NoError(t, err)
+	resolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "email", ProviderId: "email-format",
+	})
+	require.NoError(t, err)
+
+	created, err := testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{
+		OrganizationId: resolved.OrgId, Name: "format", Environment: gen.APIKeyEnvironment_API_KEY_ENVIRONMENT_TEST,
+	})
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`^cfly_k1_test_[0-9A-Za-z]{49}$`), created.PlaintextKey)
+	require.Equal(t, created.PlaintextKey[:17], created.Key.Prefix)
+
+	validated, err := testService.ValidateAPIKey(testCtx, created.PlaintextKey, "", "")
+	require.NoError(t, err)
+	require.True(t, validated.Valid)
+
+	// One changed character breaks the checksum
+	typo := []byte(created.PlaintextKey)
+	typo[20] = '0'
+	if created.PlaintextKey[20] == '0' {
+		typo[20] = '1'
+	}
+	cases := map[string]gen.APIKeyInvalidReason{
+		string(typo):           gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_MALFORMED,
+		"cfly_k1_live_garbage": gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_MALFORMED,
+		"not-a-key":            gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_MALFORMED,
+		// Legacy format: well formed, so looked up
+		"cfly_sk_live_0123456789abcdefghijABCDEFGHIJ01": gen.APIKeyInvalidReason_API_KEY_INVALID_REASON_NOT_FOUND,
+	}
+	for key, reason := range cases {
+		validated, err := testService.ValidateAPIKey(testCtx, key, "", "")
+		require.NoError(t, err)
+		require.False(t, validated.Valid, key)
+		require.Equal(t, reason, validated.Reason, key)
+	}
+}
+
+// countingHasher counts the lookups that needed the older hash schemes.
+type countingHasher struct {
+	business.KeyHasher
+	previous atomic.Int32
+}
+
+func (h *countingHasher) PreviousHashKeys(ctx context.Context, plaintext string) ([]string, error) {
+	h.previous.Add(1)
+	return h.KeyHasher.PreviousHashKeys(ctx, plaintext)
+}
+
+func TestAPIKeyHashSchemes(t *testing.T) {
+	clearData(t)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "rehash@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-rehash", ProviderEmail: "rehash@test.com",
+		},
+	})
+	require.NoError(t, err)
+	resolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "email", ProviderId: "email-rehash",
+	})
+	require.NoError(t, err)
+
+	created, err := testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{
+		OrganizationId: resolved.OrgId, Name: "rehash",
+	})
+	require.NoError(t, err)
+
+	current, err := testHasher.HashKey(testCtx, created.PlaintextKey)
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`^(vault|local)-hmac:v[0-9]+:`), current)
+
+	// A key under the current scheme is found without hashing the older ones
+	counting := &countingHasher{KeyHasher: testHasher}
+	testService.SetHasher(counting)
+	validated, err := testService.ValidateAPIKey(testCtx, created.PlaintextKey, "", "")
+	testService.SetHasher(testHasher)
+	require.NoError(t, err)
+	require.True(t, validated.Valid)
+	require.Zero(t, counting.previous.Load())
+
+	// Pretend the key was stored by the old SHA-256 fallback
+	digest := sha256.Sum256([]byte(created.PlaintextKey))
+	legacy := "sha256:" + hex.EncodeToString(digest[:])
+	require.NoError(t, testStore.UpdateAPIKeyHash(testCtx, created.Key.Id, current, legacy))
+
+	validated, err = testService.ValidateAPIKey(testCtx, created.PlaintextKey, "", "")
+	require.NoError(t, err)
+	require.True(t, validated.Valid)
+	require.Equal(t, created.Key.Id, validated.KeyId)
+
+	// The successful validation moved the key to the current scheme
+	key, err := testStore.GetAPIKeyByHash(testCtx, current)
+	require.NoError(t, err)
+	require.NotNil(t, key)
+	require.Equal(t, created.Key.Id, key.Id)
+	key, err = testStore.GetAPIKeyByHash(testCtx, legacy)
+	require.NoError(t, err)
+	require.Nil(t, key)
+}
+
+// recordingNotifier keeps the notifications it is sent.
+type recordingNotifier struct {
+	sent []business.Notification
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification business.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestReportLeakedAPIKeys(t *testing.T) {
+	clearData(t)
+
+	notifier := &recordingNotifier{}
+	testService.SetNotifier(notifier)
+	defer testService.SetNotifier(nil)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "leaky@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-leaky", ProviderEmail: "leaky@test.com",
+		},
+	})
+	require.NoError(t, err)
+	resolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "email", ProviderId: "email-leaky",
+	})
+	require.NoError(t, err)
+
+	leaked, err := testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{OrganizationId: resolved.OrgId, Name: "ci"})
+	require.NoError(t, err)
+	revoked, err := testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{OrganizationId: resolved.OrgId, Name: "other"})
+	require.NoError(t, err)
+	require.NoError(t, testService.RevokeAPIKey(testCtx, &gen.RevokeAPIKeyRequest{Id: revoked.Key.Id}))
+
+	report := &gen.ReportLeakedAPIKeysRequest{Reports: []*gen.LeakedAPIKeyReport{
+		{Token: leaked.PlaintextKey, Type: "codefly_api_key", Url: "https://github.com/acme/app/blob/main/.env", Source: "content"},
+		{Token: "cfly_k1_live_notarealkey"},
+		{Token: "cfly_sk_live_0123456789abcdefghijABCDEFGHIJ01"},
+	}}
+	results, err := testService.ReportLeakedAPIKeys(testCtx, report)
+	require.NoError(t, err)
+	require.Len(t, results.Results, 3)
+	require.True(t, results.Results[0].TruePositive)
+	require.Equal(t, leaked.PlaintextKey, results.Results[0].Token)
+	require.False(t, results.Results[1].TruePositive)
+	require.False(t, results.Results[2].TruePositive)
+
+	validated, err := testService.ValidateAPIKey(testCtx, leaked.PlaintextKey, "", "")
+	require.NoError(t, err)
+	require.False(t, validated.Valid)
+
+	require.Len(t, notifier.sent, 1)
+	require.Equal(t, "api_key.leaked", notifier.sent[0].Kind)
+	require.Equal(t, resolved.OrgId, notifier.sent[0].OrgID)
+	require.Equal(t, []string{resp.User.Uuid}, notifier.sent[0].UserIDs)
+
+	// Reported again: still a true positive, nothing more to revoke or notify
+	results, err = testService.ReportLeakedAPIKeys(testCtx, &gen.ReportLeakedAPIKeysRequest{
+		Reports: []*gen.LeakedAPIKeyReport{{Token: leaked.PlaintextKey}, {Token: revoked.PlaintextKey}},
+	})
+	require.NoError(t, err)
+	require.True(t, results.Results[0].TruePositive)
+	require.True(t, results.Results[1].TruePositive)
+	require.Len(t, notifier.sent, 1)
+}
+
+func TestTokenService_SigningAlgorithms(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	cases := []struct {
+		algorithm string
+		key       crypto.Signer
+		kty       string
+	}{
+		{infra.AlgEdDSA, edKey, "OKP"},
+		{infra.AlgES256, ecKey, "EC"},
+		{infra.AlgRS256, rsaKey, "RSA"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.algorithm, func(t *testing.T) {
+			tokens, err := infra.NewTokenServiceFromKey(tc.algorithm, tc.key)
+			require.NoError(t, err)
+
+			token, err := tokens.SignAccessToken("user-1", "org-1", []string{"admin"})
+			require.NoError(t, err)
+
+			claims, err := tokens.VerifyAccessToken(token)
+			require.NoError(t, err)
+			require.Equal(t, "user-1", claims.Subject)
+			require.Equal(t, "org-1", claims.OrgID)
+
+			var jwks struct {
+				Keys []map[string]string `json:"keys"`
+			}
+			keysJSON, err := tokens.JWKS()
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal([]byte(keysJSON), &jwks))
+			require.Len(t, jwks.Keys, 1)
+			require.Equal(t, tc.kty, jwks.Keys[0]["kty"])
+			require.Equal(t, tc.algorithm, jwks.Keys[0]["alg"])
+			require.NotEmpty(t, jwks.Keys[0]["kid"])
+		})
+	}
+
+	// A token signed with one algorithm must not verify under another
+	es, err := infra.NewTokenServiceFromKey(infra.AlgES256, ecKey)
+	require.NoError(t, err)
+	rs, err := infra.NewTokenServiceFromKey(infra.AlgRS256, rsaKey)
+	require.NoError(t, err)
+	token, err := es.SignAccessToken("user-1", "org-1", nil)
+	require.NoError(t, err)
+	_, err = rs.VerifyAccessToken(token)
+	require.Error(t, err)
+
+	// Key type must match the algorithm
+	_, err = infra.NewTokenServiceFromKey(infra.AlgES256, rsaKey)
+	require.Error(t, err)
+}
+
+func TestAuditCheckpointKeyRotation(t *testing.T) {
+	_, oldKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	before, err := infra.NewTokenServiceFromKey(infra.AlgEdDSA, oldKey)
+	require.NoError(t, err)
+	cp := business.AuditCheckpoint{OrgID: "org-1", ChainSeq: 42, Hash: "abc", CreatedAt: time.Now().Truncate(time.Second)}
+	signature, err := before.SignAuditCheckpoint(cp)
+	require.NoError(t, err)
+	token, err := before.SignAccessToken("user-1", "org-1", nil)
+	require.NoError(t, err)
+
+	// Without the old key, the checkpoint cannot be verified
+	after, err := infra.NewTokenServiceFromKey(infra.AlgES256, newKey)
+	require.NoError(t, err)
+	_, err = after.VerifyAuditCheckpoint(signature)
+	require.Error(t, err)
+
+	// Retaining the old key verifies it, picked by kid
+	der, err := x509.MarshalPKIXPublicKey(oldKey.Public())
+	require.NoError(t, err)
+	require.NoError(t, after.RetainPublicKeys(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))))
+
+	verified, err := after.VerifyAuditCheckpoint(signature)
+	require.NoError(t, err)
+	require.Equal(t, cp.OrgID, verified.OrgID)
+	require.Equal(t, cp.ChainSeq, verified.ChainSeq)
+	require.Equal(t, cp.Hash, verified.Hash)
+
+	claims, err := after.VerifyAccessToken(token)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", claims.Subject)
+
+	// Both keys are published, with their own algorithms
+	var jwks struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	keysJSON, err := after.JWKS()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(keysJSON), &jwks))
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, infra.AlgES256, jwks.Keys[0]["alg"])
+	require.Equal(t, infra.AlgEdDSA, jwks.Keys[1]["alg"])
+	require.ElementsMatch(t, []string{infra.AlgES256, infra.AlgEdDSA}, after.Algorithms())
+
+	// A kid naming the old key does not let a token signed by another key through
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"org_id": "org-1", "chain_seq": 42, "hash": "abc"})
+	forged.Header["kid"] = jwks.Keys[1]["kid"]
+	forgedSignature, err := forged.SignedString(otherKey)
+	require.NoError(t, err)
+	_, err = after.VerifyAuditCheckpoint(forgedSignature)
+	require.Error(t, err)
+}
+
+func TestVaultClientResilience(t *testing.T) {
+	var hmacCalls, failing atomic.Int32
+	failing.Store(2)
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			var creds map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&creds)
+			if creds["role_id"] != "role" || creds["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"auth": {"client_token": "approle-token", "lease_duration": 3600, "renewable": true}}`))
+		case "/v1/transit/hmac/api-keys":
+			hmacCalls.Add(1)
+			if r.Header.Get("X-Vault-Token") != "approle-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			if failing.Load() != 0 {
+				failing.Add(-1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": {"hmac": "vault:v1:abc"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer fake.Close()
+
+	config := infra.VaultConfig{
+		Address: fake.URL, AuthMethod: infra.VaultAuthAppRole, RoleID: "role", SecretID: "secret",
+		TransitKey: "api-keys", MaxRetries: 2, BreakerThreshold: 2, BreakerCooldown: time.Hour,
+	}
+	vault, err := infra.NewVaultClientFromConfig(testCtx, config)
+	require.NoError(t, err)
+	defer vault.Close()
+
+	// Transient failures are retried
+	hash, err := vault.HashKey(testCtx, "cfly_k1_test_key")
+	require.NoError(t, err)
+	require.Equal(t, "vault-hmac:v1:abc", hash)
+	require.Equal(t, int32(3), hmacCalls.Load())
+	require.NoError(t, vault.Health(testCtx))
+
+	// An outage opens the circuit: later calls fail without reaching Vault
+	failing.Store(-1)
+	for range 2 {
+		_, err = vault.HashKey(testCtx, "cfly_k1_test_key")
+		require.Error(t, err)
+	}
+	require.ErrorContains(t, vault.Health(testCtx), "circuit open")
+	calls := hmacCalls.Load()
+	_, err = vault.HashKey(testCtx, "cfly_k1_test_key")
+	require.ErrorContains(t, err, "circuit open")
+	require.Equal(t, calls, hmacCalls.Load())
+
+	// Vault down at startup: fail fast, or start degraded
+	config.Address = "http://127.0.0.1:1"
+	_, err = infra.NewVaultClientFromConfig(testCtx, config)
+	require.Error(t, err)
+
+	config.Degraded = true
+	degraded, err := infra.NewVaultClientFromConfig(testCtx, config)
+	require.NoError(t, err)
+	defer degraded.Close()
+	require.ErrorContains(t, degraded.Health(testCtx), "not logged in")
+	_, err = degraded.HashKey(testCtx, "cfly_k1_test_key")
+	require.ErrorContains(t, err, "vault unavailable")
+
+	service, err := business.NewService(testStore)
+	require.NoError(t, err)
+	service.SetHasher(degraded)
+	service.AddHealthCheck("vault", degraded)
+	health := service.Health(testCtx)
+	require.Equal(t, business.HealthDegraded, health.Status)
+	require.Equal(t, business.HealthUnavailable, health.Degraded["vault"])
+	require.Equal(t, business.HealthNotConfigured, health.Degraded["token_signer"])
+}
+
+func TestVaultClientRelogin(t *testing.T) {
+	var logins atomic.Int32
+	var loginFails atomic.Bool
+	var valid atomic.Value
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			if loginFails.Load() {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			token := fmt.Sprintf("token-%d", logins.Add(1))
+			valid.Store(token)
+			_, _ = fmt.Fprintf(w, `{"auth": {"client_token": %q, "lease_duration": 3600, "renewable": true}}`, token)
+		case "/v1/transit/hmac/api-keys":
+			if r.Header.Get("X-Vault-Token") != valid.Load() {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": {"hmac": "vault:v1:abc"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer fake.Close()
+
+	config := infra.VaultConfig{
+		Address: fake.URL, AuthMethod: infra.VaultAuthAppRole, RoleID: "role", SecretID: "secret",
+		TransitKey: "api-keys", MaxRetries: 1, BreakerThreshold: 5, BreakerCooldown: time.Hour,
+	}
+	vault, err := infra.NewVaultClientFromConfig(testCtx, config)
+	require.NoError(t, err)
+	defer vault.Close()
+	require.Equal(t, int32(1), logins.Load())
+
+	// A revoked token is replaced by a single login, however many requests it failed
+	valid.Store("revoked")
+	errs := make(chan error, 10)
+	for range 10 {
+		go func() {
+			_, err := vault.HashKey(testCtx, "cfly_k1_test_key")
+			errs <- err
+		}()
+	}
+	for range 10 {
+		require.NoError(t, <-errs)
+	}
+	require.Equal(t, int32(2), logins.Load())
+
+	// A failed re-login fails the request, but keeps the client logged in
+	valid.Store("revoked")
+	loginFails.Store(true)
+	_, err = vault.HashKey(testCtx, "cfly_k1_test_key")
+	require.Error(t, err)
+	require.NoError(t, vault.Health(testCtx))
+
+	loginFails.Store(false)
+	_, err = vault.HashKey(testCtx, "cfly_k1_test_key")
+	require.NoError(t, err)
+	require.Equal(t, int32(3), logins.Load())
+}
+
+func TestVaultTransitVersionsCache(t *testing.T) {
+	var keyReads atomic.Int32
+	var latest atomic.Int32
+	latest.Store(2)
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			_, _ = w.Write([]byte(`{"data": {"ttl": 0}}`))
+		case "/v1/transit/keys/api-keys":
+			keyReads.Add(1)
+			_, _ = fmt.Fprintf(w, `{"data": {"latest_version": %d, "min_decryption_version": 1}}`, latest.Load())
+		case "/v1/transit/hmac/api-keys":
+			var payload struct {
+				KeyVersion int `json:"key_version"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			if payload.KeyVersion == 0 {
+				payload.KeyVersion = int(latest.Load())
+			}
+			_, _ = fmt.Fprintf(w, `{"data": {"hmac": "vault:v%d:abc"}}`, payload.KeyVersion)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer fake.Close()
+
+	vault, err := infra.NewVaultClientFromConfig(testCtx, infra.VaultConfig{
+		Address: fake.URL, AuthMethod: infra.VaultAuthToken, Token: "token", TransitKey: "api-keys",
+	})
+	require.NoError(t, err)
+	defer vault.Close()
+
+	// Versions are read once, not on every lookup
+	for range 3 {
+		previous, err := vault.PreviousHashKeys(testCtx, "cfly_k1_test_key")
+		require.NoError(t, err)
+		require.Len(t, previous, 2)
+		require.Equal(t, "vault-hmac:v1:abc", previous[0])
+	}
+	require.Equal(t, int32(1), keyReads.Load())
+
+	// Hashing with a newer version shows the key was rotated
+	latest.Store(3)
+	current, err := vault.HashKey(testCtx, "cfly_k1_test_key")
+	require.NoError(t, err)
+	require.Equal(t, "vault-hmac:v3:abc", current)
+	previous, err := vault.PreviousHashKeys(testCtx, "cfly_k1_test_key")
+	require.NoError(t, err)
+	require.Equal(t, []string{"vault-hmac:v2:abc", "vault-hmac:v1:abc"}, previous[:2])
+	require.Equal(t, int32(2), keyReads.Load())
+}
+
+func TestLocalSecrets(t *testing.T) {
+	dir := t.TempDir()
+	config := infra.LocalSecretsConfig{
+		Algorithm:         infra.AlgES256,
+		JWTSigningKeyFile: dir + "/jwt-signing-key",
+		HMACKeyFile:       dir + "/api-key-hmac-key",
+		PIIKeyFile:        dir + "/pii-key",
+		PIIIndexKeyFile:   dir + "/pii-index-key",
+	}
+
+	// Outside dev mode, missing keys are an error
+	_, err := infra.NewLocalSecretsFromConfig(testCtx, config)
+	require.Error(t, err)
+
+	// Dev mode generates and persists them
+	config.Dev = true
+	secrets, err := infra.NewLocalSecretsFromConfig(testCtx, config)
+	require.NoError(t, err)
+	hasher, err := secrets.KeyHasher(testCtx)
+	require.NoError(t, err)
+	hash, err := hasher.HashKey(testCtx, "cfly_k1_test_key")
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`^local-hmac:v1:`), hash)
+	info, err := os.Stat(config.HMACKeyFile)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	tokens, err := secrets.TokenService(testCtx)
+	require.NoError(t, err)
+	token, err := tokens.SignAccessToken("user", "org", nil)
+	require.NoError(t, err)
+
+	// The next run reads the same keys
+	config.Dev = false
+	reloaded, err := infra.NewLocalSecretsFromConfig(testCtx, config)
+	require.NoError(t, err)
+	hasher, err = reloaded.KeyHasher(testCtx)
+	require.NoError(t, err)
+	again, err := hasher.HashKey(testCtx, "cfly_k1_test_key")
+	require.NoError(t, err)
+	require.Equal(t, hash, again)
+	tokens, err = reloaded.TokenService(testCtx)
+	require.NoError(t, err)
+	_, err = tokens.VerifyAccessToken(token)
+	require.NoError(t, err)
+
+	// A key given directly (e.g. from the environment) wins over the file
+	config.HMACKey = base64.StdEncoding.EncodeToString(make([]byte, 32))
+	fromEnv, err := infra.NewLocalSecretsFromConfig(testCtx, config)
+	require.NoError(t, err)
+	hasher, err = fromEnv.KeyHasher(testCtx)
+	require.NoError(t, err)
+	other, err := hasher.HashKey(testCtx, "cfly_k1_test_key")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+
+	config.HMACKey = base64.StdEncoding.EncodeToString([]byte("short"))
+	_, err = infra.NewLocalSecretsFromConfig(testCtx, config)
+	require.ErrorContains(t, err, "at least 32 bytes")
+}
+
+func TestPIIEncryption(t *testing.T) {
+	clearData(t)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "Carol@Test.com",
+		Profile:      map[string]string{"name": "Carol"},
+		Identity: &gen.UserIdentity{
+			Provider: "google", ProviderId: "google-pii", ProviderEmail: "carol@gmail.com",
+		},
+	})
+	require.NoError(t, err)
+
+	// Reads decrypt transparently
+	user, err := testStore.GetUserByIdentity(testCtx, &gen.UserIdentity{Provider: "google", ProviderId: "google-pii"})
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	require.Equal(t, "Carol@Test.com", user.PrimaryEmail)
+	require.Equal(t, "Carol", user.Profile["name"])
+
+	// Emails stay unique regardless of case, through the blind index
+	_, err = testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "carol@test.COM",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-pii", ProviderEmail: "carol@test.com",
+		},
+	})
+	require.Error(t, err)
+
+	// One pending invitation per email and org
+	resolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "google", ProviderId: "google-pii",
+	})
+	require.NoError(t, err)
+	invite := func(id, email string) error {
+		return testStore.CreateInvitation(testCtx, &business.Invitation{
+			ID: id, OrgID: resolved.OrgId, InviterID: resp.User.Uuid, Email: email, Role: "member",
+			TokenHash: id, Status: "pending", ExpiresAt: time.Now().Add(time.Hour),
+		})
+	}
+	require.NoError(t, invite("00000000-0000-0000-0000-0000000000a1", "Dave@Test.com"))
+	require.Error(t, invite("00000000-0000-0000-0000-0000000000a2", "dave@test.com"))
+
+	invitations, err := testStore.ListInvitations(testCtx, resolved.OrgId, "pending")
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	require.Equal(t, "Dave@Test.com", invitations[0].Email)
+
+	// Rows written before encryption are still unique, in plaintext
+	_, err = testStore.Pool().Exec(testCtx, `
+		INSERT INTO invitations (id, org_id, inviter_id, email, role, token_hash, status, expires_at)
+		VALUES ('00000000-0000-0000-0000-0000000000b1', $1, $2, 'Erin@Test.com', 'member', 'legacy', 'pending', $3)`,
+		resolved.OrgId, resp.User.Uuid, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Error(t, invite("00000000-0000-0000-0000-0000000000b2", "erin@test.com"))
+	_, err = testStore.Pool().Exec(testCtx, `
+		INSERT INTO invitations (id, org_id, inviter_id, email, role, token_hash, status, expires_at)
+		VALUES ('00000000-0000-0000-0000-0000000000b3', $1, $2, 'ERIN@test.com', 'member', 'legacy-2', 'pending', $3)`,
+		resolved.OrgId, resp.User.Uuid, time.Now().Add(time.Hour))
+	require.Error(t, err, "legacy rows keep their LOWER() uniqueness")
+
+	// The backfill encrypts the legacy invitation, after which everything is encrypted
+	counts, err := testStore.EncryptPII(testCtx, 100)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, counts["invitations.email"])
+	counts, err = testStore.EncryptPII(testCtx, 100)
+	require.NoError(t, err)
+	for column, n := range counts {
+		require.Zero(t, n, column)
+	}
+}
+
+func TestAuditOutbox(t *testing.T) {
+	clearData(t)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "auditor@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-auditor", ProviderEmail: "auditor@test.com",
+		},
+	})
+	require.NoError(t, err)
+	orgResp, err := testService.CreateOrganization(testCtx, resp.User.Uuid, &gen.CreateOrganizationRequest{
+		Name: "Audited", Slug: "audited",
+	})
+	require.NoError(t, err)
+	orgID := orgResp.Organization.Id
+
+	// Events of rolled back changes are never recorded
+	err = testStore.RunInTransaction(testCtx, func(ctx context.Context) error {
+		require.NoError(t, testStore.EnqueueAuditEvent(ctx, business.AuditEntry{
+			ActorType: "system", Action: "org.deleted", Resource: "organization", ResourceID: orgID, OrgID: orgID,
+		}))
+		return errors.New("rolled back")
+	})
+	require.Error(t, err)
+
+	// An event the audit log rejects doesn't hold back the others
+	require.NoError(t, testStore.EnqueueAuditEvent(testCtx, business.AuditEntry{
+		ActorType: "robot", Action: "org.probed", Resource: "organization", ResourceID: orgID, OrgID: orgID,
+	}))
+	require.NoError(t, testStore.EnqueueAuditEvent(testCtx, business.AuditEntry{
+		ActorType: "system", Action: "org.checked", Resource: "organization", ResourceID: orgID, OrgID: orgID,
+	}))
+
+	pending, _, err := testStore.CountAuditOutbox(testCtx)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, pending)
+
+	relay := business.NewAuditRelay(testStore, 2)
+	moved, err := relay.Relay(testCtx)
+	require.NoError(t, err)
+	require.Equal(t, 3, moved)
+
+	counters := relay.Counters()
+	require.EqualValues(t, 3, counters["audit_events_relayed"])
+	require.EqualValues(t, 1, counters["audit_relay_failures"])
+	require.EqualValues(t, 1, counters["audit_outbox_pending"])
+	require.EqualValues(t, 1, counters["audit_outbox_failing"])
+	require.Error(t, relay.Health(testCtx))
+
+	events, _, _, err := testService.QueryAuditLog(testCtx, orgID, "", "", "", "", nil, nil, 50, "")
+	require.NoError(t, err)
+	var actions []string
+	for _, e := range events {
+		actions = append(actions, e.Action)
+	}
+	require.ElementsMatch(t, []string{"org.created", "org.checked"}, actions)
+
+	// The failed event waits for its backoff
+	moved, err = relay.Relay(testCtx)
+	require.NoError(t, err)
+	require.Zero(t, moved)
+}
+
+func TestAuditHashChain(t *testing.T) {
+	clearData(t)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "chain@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-chain", ProviderEmail: "chain@test.com",
+		},
+	})
+	require.NoError(t, err)
+	orgResp, err := testService.CreateOrganization(testCtx, resp.User.Uuid, &gen.CreateOrganizationRequest{
+		Name: "Chained", Slug: "chained",
+	})
+	require.NoError(t, err)
+	orgID := orgResp.Organization.Id
+	for _, action := range []string{"org.checked", "org.exported"} {
+		require.NoError(t, testStore.EnqueueAuditEvent(testCtx, business.AuditEntry{
+			ActorType: "system", Action: action, Resource: "organization", ResourceID: orgID, OrgID: orgID,
+			Metadata: map[string]string{"reason": "test"},
+		}))
+	}
+
+	_, err = business.NewAuditRelay(testStore, 100).Relay(testCtx)
+	require.NoError(t, err)
+
+	// Events link to the previous one of their org, from the first
+	events, err := testStore.ListAuditChain(testCtx, orgID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	prev := ""
+	for i, e := range events {
+		require.EqualValues(t, i+1, e.ChainSeq)
+		require.Equal(t, prev, e.PrevHash)
+		require.Equal(t, business.AuditEventHash(e), e.Hash)
+		prev = e.Hash
+	}
+	altered := events[1]
+	altered.Metadata = map[string]string{"reason": "other"}
+	require.NotEqual(t, events[1].Hash, business.AuditEventHash(altered))
+
+	// A sequence number is used once per chain, whatever the partition
+	_, err = testStore.Pool().Exec(testCtx, `
+		INSERT INTO audit_events (actor_type, action, resource, org_id, chain_seq, prev_hash, hash)
+		VALUES ('system', 'forged', 'audit', $1, 2, '', '')`, orgID)
+	require.ErrorContains(t, err, "audit_chain_seqs_pkey")
+
+	verified, err := testService.VerifyAuditChain(testCtx, orgID, nil, nil)
+	require.NoError(t, err)
+	require.True(t, verified.Valid)
+	require.EqualValues(t, 3, verified.Checked)
+
+	// Checkpoints sign the chain head, once
+	n, err := testService.CreateAuditCheckpoints(testCtx)
+	require.NoError(t, err)
+	require.Positive(t, n)
+	n, err = testService.CreateAuditCheckpoints(testCtx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	checkpoints, err := testService.ListAuditCheckpoints(testCtx, orgID, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 1)
+	require.EqualValues(t, 3, checkpoints[0].ChainSeq)
+	require.Equal(t, prev, checkpoints[0].Hash)
+
+	verified, err = testService.VerifyAuditChain(testCtx, orgID, nil, nil)
+	require.NoError(t, err)
+	require.True(t, verified.Valid)
+	require.EqualValues(t, 1, verified.CheckpointsChecked)
+}
+
+func TestAuditLogWatchAndExport(t *testing.T) {
+	clearData(t)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "export@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-export", ProviderEmail: "export@test.com",
+		},
+	})
+	require.NoError(t, err)
+	orgResp, err := testService.CreateOrganization(testCtx, resp.User.Uuid, &gen.CreateOrganizationRequest{
+		Name: "Exported", Slug: "exported",
+	})
+	require.NoError(t, err)
+	orgID := orgResp.Organization.Id
+	filter := business.AuditFilter{OrgID: orgID}
+
+	// Free plans don't include the audit log
+	var out bytes.Buffer
+	err = testService.ExportAuditLog(testCtx, filter, gen.AuditExportFormat_AUDIT_EXPORT_FORMAT_NDJSON, &out)
+	require.Error(t, err)
+	require.NoError(t, testStore.CreateEntitlementOverride(testCtx, &business.EntitlementOverride{
+		ID: uuid.NewString(), OrgID: orgID, Feature: "audit_log", Reason: "test",
+	}))
+
+	for _, action := range []string{"org.checked", "org.exported"} {
+		require.NoError(t, testStore.EnqueueAuditEvent(testCtx, business.AuditEntry{
+			ActorType: "system", Action: action, Resource: "organization", ResourceID: orgID, OrgID: orgID,
+			Metadata: map[string]string{"reason": "test"},
+		}))
+	}
+	_, err = business.NewAuditRelay(testStore, 100).Relay(testCtx)
+	require.NoError(t, err)
+
+	// Exports are oldest first, with the chain fields
+	out.Reset()
+	require.NoError(t, testService.ExportAuditLog(testCtx, filter, gen.AuditExportFormat_AUDIT_EXPORT_FORMAT_NDJSON, &out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	var last map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &last))
+	require.Equal(t, "org.exported", last["action"])
+	require.EqualValues(t, 3, last["chain_seq"])
+
+	out.Reset()
+	filter.Action = "org.checked"
+	require.NoError(t, testService.ExportAuditLog(testCtx, filter, gen.AuditExportFormat_AUDIT_EXPORT_FORMAT_CSV, &out))
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "id", records[0][0])
+	require.Equal(t, `{"reason":"test"}`, records[1][8])
+
+	// Watching starts from now, and resumes after the event of a token
+	relay := func(action string) {
+		_ = testStore.EnqueueAuditEvent(testCtx, business.AuditEntry{
+			ActorType: "system", Action: action, Resource: "organization", ResourceID: orgID, OrgID: orgID,
+		})
+		_, _ = business.NewAuditRelay(testStore, 100).Relay(testCtx)
+	}
+	errStop := errors.New("stop")
+	ctx, cancel := context.WithTimeout(testCtx, 10*time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		relay("org.watched")
+	}()
+	var watched []string
+	var token string
+	err = testService.WatchAuditLog(ctx, filter, "", func(e business.AuditEntry, resumeToken string) error {
+		watched = append(watched, e.Action)
+		token = resumeToken
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []string{"org.watched"}, watched)
+
+	relay("org.resumed")
+	watched = nil
+	err = testService.WatchAuditLog(ctx, filter, token, func(e business.AuditEntry, resumeToken string) error {
+		watched = append(watched, e.Action)
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []string{"org.resumed"}, watched)
+
+	err = testService.WatchAuditLog(ctx, filter, "not-a-token", nil)
+	require.Error(t, err)
+}
+
+func TestAuditRequestContext(t *testing.T) {
+	clearData(t)
+
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "context@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-context", ProviderEmail: "context@test.com",
+		},
+	})
+	require.NoError(t, err)
+	resolved, err := testService.ResolveIdentity(testCtx, &gen.ResolveIdentityRequest{
+		Provider: "email", ProviderId: "email-context",
+	})
+	require.NoError(t, err)
+	created, err := testService.CreateAPIKey(testCtx, resp.User.Uuid, &gen.CreateAPIKeyRequest{
+		OrganizationId: resolved.OrgId, Name: "ci",
+	})
+	require.NoError(t, err)
+
+	// Updated with another key of the user: the key is the actor
+	actorKeyID := uuid.NewString()
+	ctx := business.WithRequestContext(testCtx, business.RequestContext{
+		ActorID: actorKeyID, ActorType: business.ActorAPIKey, UserID: resp.User.Uuid,
+		ClientIP: "203.0.113.9", UserAgent: "curl/8.5.0",
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", RequestID: "req-1",
+	})
+	_, err = testService.UpdateAPIKey(ctx, resp.User.Uuid, &gen.UpdateAPIKeyRequest{
+		Id:         created.Key.Id,
+		Key:        &gen.APIKey{Name: "deploy"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+	})
+	require.NoError(t, err)
+	_, err = business.NewAuditRelay(testStore, 100).Relay(testCtx)
+	require.NoError(t, err)
+
+	events, _, _, err := testService.QueryAuditLog(testCtx, resolved.OrgId, "", "api_key.updated", "", "", nil, nil, 10, "")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	e := events[0]
+	require.Equal(t, actorKeyID, e.ActorID)
+	require.Equal(t, business.ActorAPIKey, e.ActorType)
+	require.Equal(t, "203.0.113.9", e.IPAddress)
+	require.Equal(t, resp.User.Uuid, e.Metadata["on_behalf_of"])
+	require.Equal(t, "curl/8.5.0", e.Metadata["user_agent"])
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", e.Metadata["trace_id"])
+	require.Equal(t, "req-1", e.Metadata["request_id"])
+	require.JSONEq(t, `{"name": {"before": "ci", "after": "deploy"}}`, e.Metadata["diff"])
+}
+
+func TestAuditPartitions(t *testing.T) {
+	clearData(t)
+
+	// Partitions are ready for this month and the next ones
+	require.NoError(t, testService.EnsureAuditPartitions(testCtx))
+	partitions, err := testStore.ListAuditPartitions(testCtx)
+	require.NoError(t, err)
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	attached := map[string]bool{}
+	for _, p := range partitions {
+		attached[p.Name] = p.Attached
+	}
+	for i := 0; i <= 3; i++ {
+		require.True(t, attached["audit_events_"+month.AddDate(0, i, 0).Format("2006_01")])
+	}
+
+	// A free org (90 days) has events in January and February 2001, an
+	// unlimited org in February only
+	resp, err := testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "retention@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-retention", ProviderEmail: "retention@test.com",
+		},
+	})
+	require.NoError(t, err)
+	orgIDs := map[string]string{}
+	for _, slug := range []string{"free", "unlimited"} {
+		orgResp, err := testService.CreateOrganization(testCtx, resp.User.Uuid, &gen.CreateOrganizationRequest{
+			Name: slug, Slug: "retention-" + slug,
+		})
+		require.NoError(t, err)
+		orgIDs[slug] = orgResp.Organization.Id
+	}
+	require.NoError(t, testStore.CreateEntitlementOverride(testCtx, &business.EntitlementOverride{
+		ID: uuid.NewString(), OrgID: orgIDs["free"], Feature: "audit_log", Reason: "test",
+	}))
+	require.NoError(t, testStore.CreateEntitlementOverride(testCtx, &business.EntitlementOverride{
+		ID: uuid.NewString(), OrgID: orgIDs["unlimited"], Feature: "audit_retention_days", Reason: "test",
+	}))
+
+	january, err := testStore.CreateAuditPartition(testCtx, time.Date(2001, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, "audit_events_2001_01", january)
+	february, err := testStore.CreateAuditPartition(testCtx, time.Date(2001, 2, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	defer func() {
+		_ = testStore.DetachAuditPartition(testCtx, february)
+		_ = testStore.DropAuditPartition(testCtx, february)
+	}()
+
+	for _, e := range []struct {
+		org, action string
+		at          time.Time
+	}{
+		{"free", "org.checked", time.Date(2001, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{"free", "org.exported", time.Date(2001, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{"free", "org.checked", time.Date(2001, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{"unlimited", "org.checked", time.Date(2001, 2, 11, 0, 0, 0, 0, time.UTC)},
+	} {
+		require.NoError(t, testStore.EnqueueAuditEvent(testCtx, business.AuditEntry{
+			ActorType: "system", Action: e.action, Resource: "organization", ResourceID: orgIDs[e.org], OrgID: orgIDs[e.org],
+		}))
+		_, err = testStore.Pool().Exec(testCtx, `
+			UPDATE audit_outbox SET created_at = $1
+			WHERE seq = (SELECT max(seq) FROM audit_outbox)`, e.at)
+		require.NoError(t, err)
+	}
+	_, err = business.NewAuditRelay(testStore, 100).Relay(testCtx)
+	require.NoError(t, err)
+
+	// What the archive must hold: the free org's January, as exported
+	from, to := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2001, 2, 1, 0, 0, 0, 0, time.UTC)
+	var exported bytes.Buffer
+	require.NoError(t, testService.ExportAuditLog(testCtx, business.AuditFilter{OrgID: orgIDs["free"], From: &from, To: &to},
+		gen.AuditExportFormat_AUDIT_EXPORT_FORMAT_NDJSON, &exported))
+	exportedLines := strings.Split(strings.TrimSpace(exported.String()), "\n")
+	require.Len(t, exportedLines, 2)
+
+	// January is past the free org's retention: archived, then dropped.
+	// February is kept for the unlimited org, so the free org's event too.
+	archive, err := infra.NewLocalAuditArchiveIn(t.TempDir())
+	require.NoError(t, err)
+	testService.SetAuditArchive(archive)
+	defer testService.SetAuditArchive(nil)
+	_, err = testService.ArchiveAuditPartitions(testCtx)
+	require.NoError(t, err)
+
+	partitions, err = testStore.ListAuditPartitions(testCtx)
+	require.NoError(t, err)
+	attached = map[string]bool{}
+	for _, p := range partitions {
+		attached[p.Name] = p.Attached
+	}
+	require.NotContains(t, attached, january)
+	require.True(t, attached[february])
+	_, err = os.Stat(archive.Path(february))
+	require.True(t, os.IsNotExist(err))
+
+	f, err := os.Open(archive.Path(january))
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	archived, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, exportedLines, strings.Split(strings.TrimSpace(string(archived)), "\n"))
+
+	var kept bytes.Buffer
+	require.NoError(t, testService.ExportAuditLog(testCtx, business.AuditFilter{OrgID: orgIDs["free"]},
+		gen.AuditExportFormat_AUDIT_EXPORT_FORMAT_NDJSON, &kept))
+	require.Contains(t, kept.String(), "2001-02-10")
+	require.NotContains(t, kept.String(), "2001-01-")
+
+	// Querying pages through the log
+	resp, err = testService.RegisterUser(testCtx, &gen.RegisterUserRequest{
+		PrimaryEmail: "pages@test.com",
+		Identity: &gen.UserIdentity{
+			Provider: "email", ProviderId: "email-pages", ProviderEmail: "pages@test.com",
+		},
+	})
+	require.NoError(t, err)
+	orgResp, err := testService.CreateOrganization(testCtx, resp.User.Uuid, &gen.CreateOrganizationRequest{
+		Name: "Paged", Slug: "paged",
+	})
+	require.NoError(t, err)
+	orgID := orgResp.Organization.Id
+	for _, action := range []string{"org.checked", "org.exported"} {
+		require.NoError(t, testStore.EnqueueAuditEvent(testCtx, business.AuditEntry{
+			ActorType: "system", Action: action, Resource: "organization", ResourceID: orgID, OrgID: orgID,
+		}))
+	}
+	_, err = business.NewAuditRelay(testStore, 100).Relay(testCtx)
+	require.NoError(t, err)
+
+	seen := map[string]bool{}
+	token := ""
+	for {
+		events, next, _, err := testService.QueryAuditLog(testCtx, orgID, "", "", "", "", nil, nil, 2, token)
+		require.NoError(t, err)
+		for _, e := range events {
+			require.False(t, seen[e.ID], "each event is on one page")
+			seen[e.ID] = true
+		}
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	require.Len(t, seen, 3)
+}
@@ -10,6 +10,9 @@ import (
 type Store interface {
 	// Transactions
 	RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// RunInSerializableTransaction is RunInTransaction at serializable
+	// isolation, which RegisterUser needs; it can't join a weaker transaction.
+	RunInSerializableTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 
 	// Users
 	RegisterUser(ctx context.Context, user *gen.User, identity *gen.UserIdentity) error
@@ -47,15 +50,40 @@ type Store interface {
 
 	// API Keys
 	CreateAPIKey(ctx context.Context, key *gen.APIKey, keyHash string) error
+	GetAPIKey(ctx context.Context, keyID string) (*gen.APIKey, error)
 	GetAPIKeyByHash(ctx context.Context, keyHash string) (*gen.APIKey, error)
-	ListAPIKeys(ctx context.Context, orgID string, pageSize int32, pageToken string) ([]*gen.APIKey, string, error)
+	GetAPIKeyByHashes(ctx context.Context, keyHashes []string) (key *gen.APIKey, matched string, err error)
+	ListAPIKeys(ctx context.Context, orgID string, unusedSince *time.Time, pageSize int32, pageToken string) ([]*gen.APIKey, string, error)
+	UpdateAPIKey(ctx context.Context, key *gen.APIKey) error
+	UpdateAPIKeyHash(ctx context.Context, keyID, oldHash, newHash string) error
 	RevokeAPIKey(ctx context.Context, keyID string) error
-	TouchAPIKeyUsage(ctx context.Context, keyID string, ip string) error
+	ScheduleAPIKeyRevocation(ctx context.Context, keyID string, at time.Time) (bool, error)
+	RevokeDueAPIKeys(ctx context.Context) ([]*gen.APIKey, error)
+	ListLiveAPIKeys(ctx context.Context, orgID string) ([]*gen.APIKey, error)
+	SetAPIKeyDisabled(ctx context.Context, keyID string, reason string) error
+	RevokeAPIKeys(ctx context.Context, orgID string, keyIDs []string) ([]string, error)
+	RecordAPIKeyUsage(ctx context.Context, usage []APIKeyUsage) error
+	GetAPIKeyDailyUsage(ctx context.Context, keyIDs []string, since time.Time) (map[string][]*gen.APIKeyDailyUsage, error)
 
 	// Audit
-	InsertAuditEvent(ctx context.Context, entry AuditEntry) error
+	EnqueueAuditEvent(ctx context.Context, entry AuditEntry) error
+	RelayAuditEvents(ctx context.Context, limit int) (moved int, failed int, err error)
+	CountAuditOutbox(ctx context.Context) (pending int64, failing int64, err error)
+	AuditChainRange(ctx context.Context, orgID string, from, to *time.Time) (first int64, last int64, err error)
+	ListAuditChain(ctx context.Context, orgID string, afterSeq int64, limit int) ([]AuditEntry, error)
+	GetAuditChainHead(ctx context.Context, orgID string) (seq int64, hash string, err error)
+	ListUncheckpointedAuditChains(ctx context.Context) ([]*AuditCheckpoint, error)
+	CreateAuditCheckpoint(ctx context.Context, cp *AuditCheckpoint) error
+	ListAuditCheckpoints(ctx context.Context, orgID string, fromSeq, toSeq int64) ([]*AuditCheckpoint, error)
 	QueryAuditLog(ctx context.Context, orgID, actorID, action, resource, resourceID string,
 		from, to *time.Time, pageSize int32, pageToken string) ([]AuditEntry, string, int32, error)
+	ListAuditEventsAfter(ctx context.Context, filter AuditFilter, after *AuditEntry, limit int) ([]AuditEntry, error)
+	CreateAuditPartition(ctx context.Context, month time.Time) (string, error)
+	ListAuditPartitions(ctx context.Context) ([]AuditPartition, error)
+	ListAuditPartitionOrgs(ctx context.Context, name string) ([]string, error)
+	DetachAuditPartition(ctx context.Context, name string) error
+	ScanAuditPartition(ctx context.Context, name string, fn func(e AuditEntry) error) error
+	DropAuditPartition(ctx context.Context, name string) error
 
 	// Invitations
 	CreateInvitation(ctx context.Context, inv *Invitation) error
@@ -127,3 +155,12 @@ type Session struct {
 	RevokedAt        *time.Time
 	RevokedReason    string
 }
+
+// APIKeyUsage is the aggregated use of one API key on one day (UTC).
+type APIKeyUsage struct {
+	KeyID      string
+	Day        time.Time // midnight UTC
+	Requests   int64
+	LastUsedAt time.Time
+	LastUsedIP string
+}
@@ -9,6 +9,7 @@ package gen
 import (
 	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
@@ -283,6 +284,120 @@ func (APIKeyEnvironment) EnumDescriptor() ([]byte, []int) {
 	return file_user_proto_rawDescGZIP(), []int{4}
 }
 
+type APIKeyInvalidReason int32
+
+const (
+	APIKeyInvalidReason_API_KEY_INVALID_REASON_UNSPECIFIED        APIKeyInvalidReason = 0
+	APIKeyInvalidReason_API_KEY_INVALID_REASON_NOT_FOUND          APIKeyInvalidReason = 1
+	APIKeyInvalidReason_API_KEY_INVALID_REASON_REVOKED            APIKeyInvalidReason = 2
+	APIKeyInvalidReason_API_KEY_INVALID_REASON_EXPIRED            APIKeyInvalidReason = 3
+	APIKeyInvalidReason_API_KEY_INVALID_REASON_DISABLED           APIKeyInvalidReason = 4
+	APIKeyInvalidReason_API_KEY_INVALID_REASON_IP_NOT_ALLOWED     APIKeyInvalidReason = 5
+	APIKeyInvalidReason_API_KEY_INVALID_REASON_ORIGIN_NOT_ALLOWED APIKeyInvalidReason = 6
+	APIKeyInvalidReason_API_KEY_INVALID_REASON_MALFORMED          APIKeyInvalidReason = 7 // not a key format (or bad checksum); no lookup done
+)
+
+// Enum value maps for APIKeyInvalidReason.
+var (
+	APIKeyInvalidReason_name = map[int32]string{
+		0: "API_KEY_INVALID_REASON_UNSPECIFIED",
+		1: "API_KEY_INVALID_REASON_NOT_FOUND",
+		2: "API_KEY_INVALID_REASON_REVOKED",
+		3: "API_KEY_INVALID_REASON_EXPIRED",
+		4: "API_KEY_INVALID_REASON_DISABLED",
+		5: "API_KEY_INVALID_REASON_IP_NOT_ALLOWED",
+		6: "API_KEY_INVALID_REASON_ORIGIN_NOT_ALLOWED",
+		7: "API_KEY_INVALID_REASON_MALFORMED",
+	}
+	APIKeyInvalidReason_value = map[string]int32{
+		"API_KEY_INVALID_REASON_UNSPECIFIED":        0,
+		"API_KEY_INVALID_REASON_NOT_FOUND":          1,
+		"API_KEY_INVALID_REASON_REVOKED":            2,
+		"API_KEY_INVALID_REASON_EXPIRED":            3,
+		"API_KEY_INVALID_REASON_DISABLED":           4,
+		"API_KEY_INVALID_REASON_IP_NOT_ALLOWED":     5,
+		"API_KEY_INVALID_REASON_ORIGIN_NOT_ALLOWED": 6,
+		"API_KEY_INVALID_REASON_MALFORMED":          7,
+	}
+)
+
+func (x APIKeyInvalidReason) Enum() *APIKeyInvalidReason {
+	p := new(APIKeyInvalidReason)
+	*p = x
+	return p
+}
+
+func (x APIKeyInvalidReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (APIKeyInvalidReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[5].Descriptor()
+}
+
+func (APIKeyInvalidReason) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[5]
+}
+
+func (x APIKeyInvalidReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use APIKeyInvalidReason.Descriptor instead.
+func (APIKeyInvalidReason) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{5}
+}
+
+type AuditExportFormat int32
+
+const (
+	AuditExportFormat_AUDIT_EXPORT_FORMAT_UNSPECIFIED AuditExportFormat = 0
+	// One JSON object per line
+	AuditExportFormat_AUDIT_EXPORT_FORMAT_NDJSON AuditExportFormat = 1
+	AuditExportFormat_AUDIT_EXPORT_FORMAT_CSV    AuditExportFormat = 2
+)
+
+// Enum value maps for AuditExportFormat.
+var (
+	AuditExportFormat_name = map[int32]string{
+		0: "AUDIT_EXPORT_FORMAT_UNSPECIFIED",
+		1: "AUDIT_EXPORT_FORMAT_NDJSON",
+		2: "AUDIT_EXPORT_FORMAT_CSV",
+	}
+	AuditExportFormat_value = map[string]int32{
+		"AUDIT_EXPORT_FORMAT_UNSPECIFIED": 0,
+		"AUDIT_EXPORT_FORMAT_NDJSON":      1,
+		"AUDIT_EXPORT_FORMAT_CSV":         2,
+	}
+)
+
+func (x AuditExportFormat) Enum() *AuditExportFormat {
+	p := new(AuditExportFormat)
+	*p = x
+	return p
+}
+
+func (x AuditExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuditExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[6].Descriptor()
+}
+
+func (AuditExportFormat) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[6]
+}
+
+func (x AuditExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuditExportFormat.Descriptor instead.
+func (AuditExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{6}
+}
+
 type InvitationStatus int32
 
 const (
@@ -322,11 +437,11 @@ func (x InvitationStatus) String() string {
 }
 
 func (InvitationStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_user_proto_enumTypes[5].Descriptor()
+	return file_user_proto_enumTypes[7].Descriptor()
 }
 
 func (InvitationStatus) Type() protoreflect.EnumType {
-	return &file_user_proto_enumTypes[5]
+	return &file_user_proto_enumTypes[7]
 }
 
 func (x InvitationStatus) Number() protoreflect.EnumNumber {
@@ -335,7 +450,7 @@ func (x InvitationStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use InvitationStatus.Descriptor instead.
 func (InvitationStatus) EnumDescriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{5}
+	return file_user_proto_rawDescGZIP(), []int{7}
 }
 
 type VersionRequest struct {
@@ -418,6 +533,106 @@ func (x *VersionResponse) GetVersion() string {
 	return ""
 }
 
+type HealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{2}
+}
+
+// HealthResponse reports "ok", or "degraded" while dependencies are down
+// (e.g. Vault unreachable at startup); the service keeps serving what it can.
+type HealthResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// Components that are down, with their status; the reason is only logged
+	Degraded map[string]string `protobuf:"bytes,2,rep,name=degraded,proto3" json:"degraded,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Counters since startup, e.g. audit_events_dropped
+	Counters      map[string]int64 `protobuf:"bytes,3,rep,name=counters,proto3" json:"counters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HealthResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthResponse) GetDegraded() map[string]string {
+	if x != nil {
+		return x.Degraded
+	}
+	return nil
+}
+
+func (x *HealthResponse) GetCounters() map[string]int64 {
+	if x != nil {
+		return x.Counters
+	}
+	return nil
+}
+
 // User represents a user in the system
 type User struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -435,7 +650,7 @@ type User struct {
 
 func (x *User) Reset() {
 	*x = User{}
-	mi := &file_user_proto_msgTypes[2]
+	mi := &file_user_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -447,7 +662,7 @@ func (x *User) String() string {
 func (*User) ProtoMessage() {}
 
 func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[2]
+	mi := &file_user_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -460,7 +675,7 @@ func (x *User) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use User.ProtoReflect.Descriptor instead.
 func (*User) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{2}
+	return file_user_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *User) GetUuid() string {
@@ -537,7 +752,7 @@ type UserIdentity struct {
 
 func (x *UserIdentity) Reset() {
 	*x = UserIdentity{}
-	mi := &file_user_proto_msgTypes[3]
+	mi := &file_user_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -549,7 +764,7 @@ func (x *UserIdentity) String() string {
 func (*UserIdentity) ProtoMessage() {}
 
 func (x *UserIdentity) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[3]
+	mi := &file_user_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -562,7 +777,7 @@ func (x *UserIdentity) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserIdentity.ProtoReflect.Descriptor instead.
 func (*UserIdentity) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{3}
+	return file_user_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UserIdentity) GetUuid() string {
@@ -641,7 +856,7 @@ type Organization struct {
 
 func (x *Organization) Reset() {
 	*x = Organization{}
-	mi := &file_user_proto_msgTypes[4]
+	mi := &file_user_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -653,7 +868,7 @@ func (x *Organization) String() string {
 func (*Organization) ProtoMessage() {}
 
 func (x *Organization) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[4]
+	mi := &file_user_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -666,7 +881,7 @@ func (x *Organization) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Organization.ProtoReflect.Descriptor instead.
 func (*Organization) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{4}
+	return file_user_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Organization) GetId() string {
@@ -716,7 +931,7 @@ type OrgMembership struct {
 
 func (x *OrgMembership) Reset() {
 	*x = OrgMembership{}
-	mi := &file_user_proto_msgTypes[5]
+	mi := &file_user_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -728,7 +943,7 @@ func (x *OrgMembership) String() string {
 func (*OrgMembership) ProtoMessage() {}
 
 func (x *OrgMembership) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[5]
+	mi := &file_user_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -741,7 +956,7 @@ func (x *OrgMembership) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrgMembership.ProtoReflect.Descriptor instead.
 func (*OrgMembership) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{5}
+	return file_user_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *OrgMembership) GetOrgId() string {
@@ -785,7 +1000,7 @@ type Team struct {
 
 func (x *Team) Reset() {
 	*x = Team{}
-	mi := &file_user_proto_msgTypes[6]
+	mi := &file_user_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -797,7 +1012,7 @@ func (x *Team) String() string {
 func (*Team) ProtoMessage() {}
 
 func (x *Team) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[6]
+	mi := &file_user_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -810,7 +1025,7 @@ func (x *Team) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Team.ProtoReflect.Descriptor instead.
 func (*Team) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{6}
+	return file_user_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Team) GetId() string {
@@ -860,7 +1075,7 @@ type TeamMembership struct {
 
 func (x *TeamMembership) Reset() {
 	*x = TeamMembership{}
-	mi := &file_user_proto_msgTypes[7]
+	mi := &file_user_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -872,7 +1087,7 @@ func (x *TeamMembership) String() string {
 func (*TeamMembership) ProtoMessage() {}
 
 func (x *TeamMembership) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[7]
+	mi := &file_user_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -885,7 +1100,7 @@ func (x *TeamMembership) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TeamMembership.ProtoReflect.Descriptor instead.
 func (*TeamMembership) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{7}
+	return file_user_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *TeamMembership) GetTeamId() string {
@@ -926,7 +1141,7 @@ type Permission struct {
 
 func (x *Permission) Reset() {
 	*x = Permission{}
-	mi := &file_user_proto_msgTypes[8]
+	mi := &file_user_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -938,7 +1153,7 @@ func (x *Permission) String() string {
 func (*Permission) ProtoMessage() {}
 
 func (x *Permission) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[8]
+	mi := &file_user_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -951,7 +1166,7 @@ func (x *Permission) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Permission.ProtoReflect.Descriptor instead.
 func (*Permission) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{8}
+	return file_user_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Permission) GetResource() string {
@@ -982,7 +1197,7 @@ type Role struct {
 
 func (x *Role) Reset() {
 	*x = Role{}
-	mi := &file_user_proto_msgTypes[9]
+	mi := &file_user_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -994,7 +1209,7 @@ func (x *Role) String() string {
 func (*Role) ProtoMessage() {}
 
 func (x *Role) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[9]
+	mi := &file_user_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1007,7 +1222,7 @@ func (x *Role) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Role.ProtoReflect.Descriptor instead.
 func (*Role) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{9}
+	return file_user_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Role) GetId() string {
@@ -1067,7 +1282,7 @@ type RoleAssignment struct {
 
 func (x *RoleAssignment) Reset() {
 	*x = RoleAssignment{}
-	mi := &file_user_proto_msgTypes[10]
+	mi := &file_user_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1079,7 +1294,7 @@ func (x *RoleAssignment) String() string {
 func (*RoleAssignment) ProtoMessage() {}
 
 func (x *RoleAssignment) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[10]
+	mi := &file_user_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1092,7 +1307,7 @@ func (x *RoleAssignment) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoleAssignment.ProtoReflect.Descriptor instead.
 func (*RoleAssignment) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{10}
+	return file_user_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *RoleAssignment) GetId() string {
@@ -1155,7 +1370,7 @@ type RegisterUserRequest struct {
 
 func (x *RegisterUserRequest) Reset() {
 	*x = RegisterUserRequest{}
-	mi := &file_user_proto_msgTypes[11]
+	mi := &file_user_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1167,7 +1382,7 @@ func (x *RegisterUserRequest) String() string {
 func (*RegisterUserRequest) ProtoMessage() {}
 
 func (x *RegisterUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[11]
+	mi := &file_user_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1180,7 +1395,7 @@ func (x *RegisterUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterUserRequest.ProtoReflect.Descriptor instead.
 func (*RegisterUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{11}
+	return file_user_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *RegisterUserRequest) GetPrimaryEmail() string {
@@ -1214,7 +1429,7 @@ type RegisterUserResponse struct {
 
 func (x *RegisterUserResponse) Reset() {
 	*x = RegisterUserResponse{}
-	mi := &file_user_proto_msgTypes[12]
+	mi := &file_user_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1226,7 +1441,7 @@ func (x *RegisterUserResponse) String() string {
 func (*RegisterUserResponse) ProtoMessage() {}
 
 func (x *RegisterUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[12]
+	mi := &file_user_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1239,7 +1454,7 @@ func (x *RegisterUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterUserResponse.ProtoReflect.Descriptor instead.
 func (*RegisterUserResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{12}
+	return file_user_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *RegisterUserResponse) GetUser() *User {
@@ -1269,7 +1484,7 @@ type GetUserRequest struct {
 
 func (x *GetUserRequest) Reset() {
 	*x = GetUserRequest{}
-	mi := &file_user_proto_msgTypes[13]
+	mi := &file_user_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1281,7 +1496,7 @@ func (x *GetUserRequest) String() string {
 func (*GetUserRequest) ProtoMessage() {}
 
 func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[13]
+	mi := &file_user_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1294,7 +1509,7 @@ func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
 func (*GetUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{13}
+	return file_user_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetUserRequest) GetIdentifier() isGetUserRequest_Identifier {
@@ -1346,7 +1561,7 @@ type GetSelfRequest struct {
 
 func (x *GetSelfRequest) Reset() {
 	*x = GetSelfRequest{}
-	mi := &file_user_proto_msgTypes[14]
+	mi := &file_user_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1358,7 +1573,7 @@ func (x *GetSelfRequest) String() string {
 func (*GetSelfRequest) ProtoMessage() {}
 
 func (x *GetSelfRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[14]
+	mi := &file_user_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1371,7 +1586,7 @@ func (x *GetSelfRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSelfRequest.ProtoReflect.Descriptor instead.
 func (*GetSelfRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{14}
+	return file_user_proto_rawDescGZIP(), []int{16}
 }
 
 type GetSelfResponse struct {
@@ -1386,7 +1601,7 @@ type GetSelfResponse struct {
 
 func (x *GetSelfResponse) Reset() {
 	*x = GetSelfResponse{}
-	mi := &file_user_proto_msgTypes[15]
+	mi := &file_user_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1398,7 +1613,7 @@ func (x *GetSelfResponse) String() string {
 func (*GetSelfResponse) ProtoMessage() {}
 
 func (x *GetSelfResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[15]
+	mi := &file_user_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1411,7 +1626,7 @@ func (x *GetSelfResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSelfResponse.ProtoReflect.Descriptor instead.
 func (*GetSelfResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{15}
+	return file_user_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetSelfResponse) GetUser() *User {
@@ -1453,7 +1668,7 @@ type ListUsersRequest struct {
 
 func (x *ListUsersRequest) Reset() {
 	*x = ListUsersRequest{}
-	mi := &file_user_proto_msgTypes[16]
+	mi := &file_user_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1465,7 +1680,7 @@ func (x *ListUsersRequest) String() string {
 func (*ListUsersRequest) ProtoMessage() {}
 
 func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[16]
+	mi := &file_user_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1478,7 +1693,7 @@ func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
 func (*ListUsersRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{16}
+	return file_user_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ListUsersRequest) GetPageSize() int32 {
@@ -1512,7 +1727,7 @@ type ListUsersResponse struct {
 
 func (x *ListUsersResponse) Reset() {
 	*x = ListUsersResponse{}
-	mi := &file_user_proto_msgTypes[17]
+	mi := &file_user_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1524,7 +1739,7 @@ func (x *ListUsersResponse) String() string {
 func (*ListUsersResponse) ProtoMessage() {}
 
 func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[17]
+	mi := &file_user_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1537,7 +1752,7 @@ func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
 func (*ListUsersResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{17}
+	return file_user_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ListUsersResponse) GetUsers() []*User {
@@ -1565,7 +1780,7 @@ type UpdateUserRequest struct {
 
 func (x *UpdateUserRequest) Reset() {
 	*x = UpdateUserRequest{}
-	mi := &file_user_proto_msgTypes[18]
+	mi := &file_user_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1577,7 +1792,7 @@ func (x *UpdateUserRequest) String() string {
 func (*UpdateUserRequest) ProtoMessage() {}
 
 func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[18]
+	mi := &file_user_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1590,7 +1805,7 @@ func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
 func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{18}
+	return file_user_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *UpdateUserRequest) GetUuid() string {
@@ -1624,7 +1839,7 @@ type AddIdentityRequest struct {
 
 func (x *AddIdentityRequest) Reset() {
 	*x = AddIdentityRequest{}
-	mi := &file_user_proto_msgTypes[19]
+	mi := &file_user_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1636,7 +1851,7 @@ func (x *AddIdentityRequest) String() string {
 func (*AddIdentityRequest) ProtoMessage() {}
 
 func (x *AddIdentityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[19]
+	mi := &file_user_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1649,7 +1864,7 @@ func (x *AddIdentityRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddIdentityRequest.ProtoReflect.Descriptor instead.
 func (*AddIdentityRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{19}
+	return file_user_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *AddIdentityRequest) GetUserUuid() string {
@@ -1676,7 +1891,7 @@ type FindUserByIdentityRequest struct {
 
 func (x *FindUserByIdentityRequest) Reset() {
 	*x = FindUserByIdentityRequest{}
-	mi := &file_user_proto_msgTypes[20]
+	mi := &file_user_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1688,7 +1903,7 @@ func (x *FindUserByIdentityRequest) String() string {
 func (*FindUserByIdentityRequest) ProtoMessage() {}
 
 func (x *FindUserByIdentityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[20]
+	mi := &file_user_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1701,7 +1916,7 @@ func (x *FindUserByIdentityRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FindUserByIdentityRequest.ProtoReflect.Descriptor instead.
 func (*FindUserByIdentityRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{20}
+	return file_user_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *FindUserByIdentityRequest) GetProvider() string {
@@ -1727,7 +1942,7 @@ type ListUserIdentitiesRequest struct {
 
 func (x *ListUserIdentitiesRequest) Reset() {
 	*x = ListUserIdentitiesRequest{}
-	mi := &file_user_proto_msgTypes[21]
+	mi := &file_user_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1739,7 +1954,7 @@ func (x *ListUserIdentitiesRequest) String() string {
 func (*ListUserIdentitiesRequest) ProtoMessage() {}
 
 func (x *ListUserIdentitiesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[21]
+	mi := &file_user_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1752,7 +1967,7 @@ func (x *ListUserIdentitiesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUserIdentitiesRequest.ProtoReflect.Descriptor instead.
 func (*ListUserIdentitiesRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{21}
+	return file_user_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *ListUserIdentitiesRequest) GetUserUuid() string {
@@ -1771,7 +1986,7 @@ type ListUserIdentitiesResponse struct {
 
 func (x *ListUserIdentitiesResponse) Reset() {
 	*x = ListUserIdentitiesResponse{}
-	mi := &file_user_proto_msgTypes[22]
+	mi := &file_user_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1783,7 +1998,7 @@ func (x *ListUserIdentitiesResponse) String() string {
 func (*ListUserIdentitiesResponse) ProtoMessage() {}
 
 func (x *ListUserIdentitiesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[22]
+	mi := &file_user_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1796,7 +2011,7 @@ func (x *ListUserIdentitiesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUserIdentitiesResponse.ProtoReflect.Descriptor instead.
 func (*ListUserIdentitiesResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{22}
+	return file_user_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *ListUserIdentitiesResponse) GetIdentities() []*UserIdentity {
@@ -1816,7 +2031,7 @@ type CreateOrganizationRequest struct {
 
 func (x *CreateOrganizationRequest) Reset() {
 	*x = CreateOrganizationRequest{}
-	mi := &file_user_proto_msgTypes[23]
+	mi := &file_user_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1828,7 +2043,7 @@ func (x *CreateOrganizationRequest) String() string {
 func (*CreateOrganizationRequest) ProtoMessage() {}
 
 func (x *CreateOrganizationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[23]
+	mi := &file_user_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1841,7 +2056,7 @@ func (x *CreateOrganizationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrganizationRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrganizationRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{23}
+	return file_user_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *CreateOrganizationRequest) GetName() string {
@@ -1867,7 +2082,7 @@ type CreateOrganizationResponse struct {
 
 func (x *CreateOrganizationResponse) Reset() {
 	*x = CreateOrganizationResponse{}
-	mi := &file_user_proto_msgTypes[24]
+	mi := &file_user_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1879,7 +2094,7 @@ func (x *CreateOrganizationResponse) String() string {
 func (*CreateOrganizationResponse) ProtoMessage() {}
 
 func (x *CreateOrganizationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[24]
+	mi := &file_user_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1892,7 +2107,7 @@ func (x *CreateOrganizationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrganizationResponse.ProtoReflect.Descriptor instead.
 func (*CreateOrganizationResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{24}
+	return file_user_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *CreateOrganizationResponse) GetOrganization() *Organization {
@@ -1911,7 +2126,7 @@ type GetOrganizationRequest struct {
 
 func (x *GetOrganizationRequest) Reset() {
 	*x = GetOrganizationRequest{}
-	mi := &file_user_proto_msgTypes[25]
+	mi := &file_user_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1923,7 +2138,7 @@ func (x *GetOrganizationRequest) String() string {
 func (*GetOrganizationRequest) ProtoMessage() {}
 
 func (x *GetOrganizationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[25]
+	mi := &file_user_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1936,7 +2151,7 @@ func (x *GetOrganizationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrganizationRequest.ProtoReflect.Descriptor instead.
 func (*GetOrganizationRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{25}
+	return file_user_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *GetOrganizationRequest) GetId() string {
@@ -1954,7 +2169,7 @@ type ListOrganizationsRequest struct {
 
 func (x *ListOrganizationsRequest) Reset() {
 	*x = ListOrganizationsRequest{}
-	mi := &file_user_proto_msgTypes[26]
+	mi := &file_user_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1966,7 +2181,7 @@ func (x *ListOrganizationsRequest) String() string {
 func (*ListOrganizationsRequest) ProtoMessage() {}
 
 func (x *ListOrganizationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[26]
+	mi := &file_user_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1979,7 +2194,7 @@ func (x *ListOrganizationsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrganizationsRequest.ProtoReflect.Descriptor instead.
 func (*ListOrganizationsRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{26}
+	return file_user_proto_rawDescGZIP(), []int{28}
 }
 
 type ListOrganizationsResponse struct {
@@ -1991,7 +2206,7 @@ type ListOrganizationsResponse struct {
 
 func (x *ListOrganizationsResponse) Reset() {
 	*x = ListOrganizationsResponse{}
-	mi := &file_user_proto_msgTypes[27]
+	mi := &file_user_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2003,7 +2218,7 @@ func (x *ListOrganizationsResponse) String() string {
 func (*ListOrganizationsResponse) ProtoMessage() {}
 
 func (x *ListOrganizationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[27]
+	mi := &file_user_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2016,7 +2231,7 @@ func (x *ListOrganizationsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrganizationsResponse.ProtoReflect.Descriptor instead.
 func (*ListOrganizationsResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{27}
+	return file_user_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ListOrganizationsResponse) GetOrganizations() []*Organization {
@@ -2037,7 +2252,7 @@ type AddOrgMemberRequest struct {
 
 func (x *AddOrgMemberRequest) Reset() {
 	*x = AddOrgMemberRequest{}
-	mi := &file_user_proto_msgTypes[28]
+	mi := &file_user_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2049,7 +2264,7 @@ func (x *AddOrgMemberRequest) String() string {
 func (*AddOrgMemberRequest) ProtoMessage() {}
 
 func (x *AddOrgMemberRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[28]
+	mi := &file_user_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2062,7 +2277,7 @@ func (x *AddOrgMemberRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddOrgMemberRequest.ProtoReflect.Descriptor instead.
 func (*AddOrgMemberRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{28}
+	return file_user_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *AddOrgMemberRequest) GetOrgId() string {
@@ -2096,7 +2311,7 @@ type RemoveOrgMemberRequest struct {
 
 func (x *RemoveOrgMemberRequest) Reset() {
 	*x = RemoveOrgMemberRequest{}
-	mi := &file_user_proto_msgTypes[29]
+	mi := &file_user_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2108,7 +2323,7 @@ func (x *RemoveOrgMemberRequest) String() string {
 func (*RemoveOrgMemberRequest) ProtoMessage() {}
 
 func (x *RemoveOrgMemberRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[29]
+	mi := &file_user_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2121,7 +2336,7 @@ func (x *RemoveOrgMemberRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveOrgMemberRequest.ProtoReflect.Descriptor instead.
 func (*RemoveOrgMemberRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{29}
+	return file_user_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *RemoveOrgMemberRequest) GetOrgId() string {
@@ -2147,7 +2362,7 @@ type ListOrgMembersRequest struct {
 
 func (x *ListOrgMembersRequest) Reset() {
 	*x = ListOrgMembersRequest{}
-	mi := &file_user_proto_msgTypes[30]
+	mi := &file_user_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2159,7 +2374,7 @@ func (x *ListOrgMembersRequest) String() string {
 func (*ListOrgMembersRequest) ProtoMessage() {}
 
 func (x *ListOrgMembersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[30]
+	mi := &file_user_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2172,7 +2387,7 @@ func (x *ListOrgMembersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrgMembersRequest.ProtoReflect.Descriptor instead.
 func (*ListOrgMembersRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{30}
+	return file_user_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *ListOrgMembersRequest) GetOrgId() string {
@@ -2191,7 +2406,7 @@ type ListOrgMembersResponse struct {
 
 func (x *ListOrgMembersResponse) Reset() {
 	*x = ListOrgMembersResponse{}
-	mi := &file_user_proto_msgTypes[31]
+	mi := &file_user_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2203,7 +2418,7 @@ func (x *ListOrgMembersResponse) String() string {
 func (*ListOrgMembersResponse) ProtoMessage() {}
 
 func (x *ListOrgMembersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[31]
+	mi := &file_user_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2216,7 +2431,7 @@ func (x *ListOrgMembersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrgMembersResponse.ProtoReflect.Descriptor instead.
 func (*ListOrgMembersResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{31}
+	return file_user_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *ListOrgMembersResponse) GetMembers() []*OrgMembership {
@@ -2237,7 +2452,7 @@ type CreateTeamRequest struct {
 
 func (x *CreateTeamRequest) Reset() {
 	*x = CreateTeamRequest{}
-	mi := &file_user_proto_msgTypes[32]
+	mi := &file_user_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2249,7 +2464,7 @@ func (x *CreateTeamRequest) String() string {
 func (*CreateTeamRequest) ProtoMessage() {}
 
 func (x *CreateTeamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[32]
+	mi := &file_user_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2262,7 +2477,7 @@ func (x *CreateTeamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTeamRequest.ProtoReflect.Descriptor instead.
 func (*CreateTeamRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{32}
+	return file_user_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *CreateTeamRequest) GetOrgId() string {
@@ -2295,7 +2510,7 @@ type CreateTeamResponse struct {
 
 func (x *CreateTeamResponse) Reset() {
 	*x = CreateTeamResponse{}
-	mi := &file_user_proto_msgTypes[33]
+	mi := &file_user_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2307,7 +2522,7 @@ func (x *CreateTeamResponse) String() string {
 func (*CreateTeamResponse) ProtoMessage() {}
 
 func (x *CreateTeamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[33]
+	mi := &file_user_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2320,7 +2535,7 @@ func (x *CreateTeamResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTeamResponse.ProtoReflect.Descriptor instead.
 func (*CreateTeamResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{33}
+	return file_user_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *CreateTeamResponse) GetTeam() *Team {
@@ -2339,7 +2554,7 @@ type ListTeamsRequest struct {
 
 func (x *ListTeamsRequest) Reset() {
 	*x = ListTeamsRequest{}
-	mi := &file_user_proto_msgTypes[34]
+	mi := &file_user_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2351,7 +2566,7 @@ func (x *ListTeamsRequest) String() string {
 func (*ListTeamsRequest) ProtoMessage() {}
 
 func (x *ListTeamsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[34]
+	mi := &file_user_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2364,7 +2579,7 @@ func (x *ListTeamsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTeamsRequest.ProtoReflect.Descriptor instead.
 func (*ListTeamsRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{34}
+	return file_user_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *ListTeamsRequest) GetOrgId() string {
@@ -2383,7 +2598,7 @@ type ListTeamsResponse struct {
 
 func (x *ListTeamsResponse) Reset() {
 	*x = ListTeamsResponse{}
-	mi := &file_user_proto_msgTypes[35]
+	mi := &file_user_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2395,7 +2610,7 @@ func (x *ListTeamsResponse) String() string {
 func (*ListTeamsResponse) ProtoMessage() {}
 
 func (x *ListTeamsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[35]
+	mi := &file_user_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2408,7 +2623,7 @@ func (x *ListTeamsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTeamsResponse.ProtoReflect.Descriptor instead.
 func (*ListTeamsResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{35}
+	return file_user_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *ListTeamsResponse) GetTeams() []*Team {
@@ -2429,7 +2644,7 @@ type AddTeamMemberRequest struct {
 
 func (x *AddTeamMemberRequest) Reset() {
 	*x = AddTeamMemberRequest{}
-	mi := &file_user_proto_msgTypes[36]
+	mi := &file_user_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2441,7 +2656,7 @@ func (x *AddTeamMemberRequest) String() string {
 func (*AddTeamMemberRequest) ProtoMessage() {}
 
 func (x *AddTeamMemberRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[36]
+	mi := &file_user_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2454,7 +2669,7 @@ func (x *AddTeamMemberRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddTeamMemberRequest.ProtoReflect.Descriptor instead.
 func (*AddTeamMemberRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{36}
+	return file_user_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *AddTeamMemberRequest) GetTeamId() string {
@@ -2488,7 +2703,7 @@ type RemoveTeamMemberRequest struct {
 
 func (x *RemoveTeamMemberRequest) Reset() {
 	*x = RemoveTeamMemberRequest{}
-	mi := &file_user_proto_msgTypes[37]
+	mi := &file_user_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2500,7 +2715,7 @@ func (x *RemoveTeamMemberRequest) String() string {
 func (*RemoveTeamMemberRequest) ProtoMessage() {}
 
 func (x *RemoveTeamMemberRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[37]
+	mi := &file_user_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2513,7 +2728,7 @@ func (x *RemoveTeamMemberRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveTeamMemberRequest.ProtoReflect.Descriptor instead.
 func (*RemoveTeamMemberRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{37}
+	return file_user_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *RemoveTeamMemberRequest) GetTeamId() string {
@@ -2539,7 +2754,7 @@ type ListTeamMembersRequest struct {
 
 func (x *ListTeamMembersRequest) Reset() {
 	*x = ListTeamMembersRequest{}
-	mi := &file_user_proto_msgTypes[38]
+	mi := &file_user_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2551,7 +2766,7 @@ func (x *ListTeamMembersRequest) String() string {
 func (*ListTeamMembersRequest) ProtoMessage() {}
 
 func (x *ListTeamMembersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[38]
+	mi := &file_user_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2564,7 +2779,7 @@ func (x *ListTeamMembersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTeamMembersRequest.ProtoReflect.Descriptor instead.
 func (*ListTeamMembersRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{38}
+	return file_user_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *ListTeamMembersRequest) GetTeamId() string {
@@ -2583,7 +2798,7 @@ type ListTeamMembersResponse struct {
 
 func (x *ListTeamMembersResponse) Reset() {
 	*x = ListTeamMembersResponse{}
-	mi := &file_user_proto_msgTypes[39]
+	mi := &file_user_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2595,7 +2810,7 @@ func (x *ListTeamMembersResponse) String() string {
 func (*ListTeamMembersResponse) ProtoMessage() {}
 
 func (x *ListTeamMembersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[39]
+	mi := &file_user_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2608,7 +2823,7 @@ func (x *ListTeamMembersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTeamMembersResponse.ProtoReflect.Descriptor instead.
 func (*ListTeamMembersResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{39}
+	return file_user_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *ListTeamMembersResponse) GetMembers() []*TeamMembership {
@@ -2630,7 +2845,7 @@ type CreateRoleRequest struct {
 
 func (x *CreateRoleRequest) Reset() {
 	*x = CreateRoleRequest{}
-	mi := &file_user_proto_msgTypes[40]
+	mi := &file_user_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2642,7 +2857,7 @@ func (x *CreateRoleRequest) String() string {
 func (*CreateRoleRequest) ProtoMessage() {}
 
 func (x *CreateRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[40]
+	mi := &file_user_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2655,7 +2870,7 @@ func (x *CreateRoleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoleRequest.ProtoReflect.Descriptor instead.
 func (*CreateRoleRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{40}
+	return file_user_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *CreateRoleRequest) GetName() string {
@@ -2695,7 +2910,7 @@ type CreateRoleResponse struct {
 
 func (x *CreateRoleResponse) Reset() {
 	*x = CreateRoleResponse{}
-	mi := &file_user_proto_msgTypes[41]
+	mi := &file_user_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2707,7 +2922,7 @@ func (x *CreateRoleResponse) String() string {
 func (*CreateRoleResponse) ProtoMessage() {}
 
 func (x *CreateRoleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[41]
+	mi := &file_user_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2720,7 +2935,7 @@ func (x *CreateRoleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoleResponse.ProtoReflect.Descriptor instead.
 func (*CreateRoleResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{41}
+	return file_user_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *CreateRoleResponse) GetRole() *Role {
@@ -2739,7 +2954,7 @@ type ListRolesRequest struct {
 
 func (x *ListRolesRequest) Reset() {
 	*x = ListRolesRequest{}
-	mi := &file_user_proto_msgTypes[42]
+	mi := &file_user_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2751,7 +2966,7 @@ func (x *ListRolesRequest) String() string {
 func (*ListRolesRequest) ProtoMessage() {}
 
 func (x *ListRolesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[42]
+	mi := &file_user_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2764,7 +2979,7 @@ func (x *ListRolesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRolesRequest.ProtoReflect.Descriptor instead.
 func (*ListRolesRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{42}
+	return file_user_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *ListRolesRequest) GetOrgId() string {
@@ -2783,7 +2998,7 @@ type ListRolesResponse struct {
 
 func (x *ListRolesResponse) Reset() {
 	*x = ListRolesResponse{}
-	mi := &file_user_proto_msgTypes[43]
+	mi := &file_user_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2795,7 +3010,7 @@ func (x *ListRolesResponse) String() string {
 func (*ListRolesResponse) ProtoMessage() {}
 
 func (x *ListRolesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[43]
+	mi := &file_user_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2808,7 +3023,7 @@ func (x *ListRolesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRolesResponse.ProtoReflect.Descriptor instead.
 func (*ListRolesResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{43}
+	return file_user_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *ListRolesResponse) GetRoles() []*Role {
@@ -2827,7 +3042,7 @@ type DeleteRoleRequest struct {
 
 func (x *DeleteRoleRequest) Reset() {
 	*x = DeleteRoleRequest{}
-	mi := &file_user_proto_msgTypes[44]
+	mi := &file_user_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2839,7 +3054,7 @@ func (x *DeleteRoleRequest) String() string {
 func (*DeleteRoleRequest) ProtoMessage() {}
 
 func (x *DeleteRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[44]
+	mi := &file_user_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2852,7 +3067,7 @@ func (x *DeleteRoleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteRoleRequest.ProtoReflect.Descriptor instead.
 func (*DeleteRoleRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{44}
+	return file_user_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *DeleteRoleRequest) GetId() string {
@@ -2875,7 +3090,7 @@ type AssignRoleRequest struct {
 
 func (x *AssignRoleRequest) Reset() {
 	*x = AssignRoleRequest{}
-	mi := &file_user_proto_msgTypes[45]
+	mi := &file_user_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2887,7 +3102,7 @@ func (x *AssignRoleRequest) String() string {
 func (*AssignRoleRequest) ProtoMessage() {}
 
 func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[45]
+	mi := &file_user_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2900,7 +3115,7 @@ func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AssignRoleRequest.ProtoReflect.Descriptor instead.
 func (*AssignRoleRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{45}
+	return file_user_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *AssignRoleRequest) GetSubjectId() string {
@@ -2947,7 +3162,7 @@ type AssignRoleResponse struct {
 
 func (x *AssignRoleResponse) Reset() {
 	*x = AssignRoleResponse{}
-	mi := &file_user_proto_msgTypes[46]
+	mi := &file_user_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2959,7 +3174,7 @@ func (x *AssignRoleResponse) String() string {
 func (*AssignRoleResponse) ProtoMessage() {}
 
 func (x *AssignRoleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[46]
+	mi := &file_user_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2972,7 +3187,7 @@ func (x *AssignRoleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AssignRoleResponse.ProtoReflect.Descriptor instead.
 func (*AssignRoleResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{46}
+	return file_user_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *AssignRoleResponse) GetAssignment() *RoleAssignment {
@@ -2994,7 +3209,7 @@ type RevokeRoleRequest struct {
 
 func (x *RevokeRoleRequest) Reset() {
 	*x = RevokeRoleRequest{}
-	mi := &file_user_proto_msgTypes[47]
+	mi := &file_user_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3006,7 +3221,7 @@ func (x *RevokeRoleRequest) String() string {
 func (*RevokeRoleRequest) ProtoMessage() {}
 
 func (x *RevokeRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[47]
+	mi := &file_user_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3019,7 +3234,7 @@ func (x *RevokeRoleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RevokeRoleRequest.ProtoReflect.Descriptor instead.
 func (*RevokeRoleRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{47}
+	return file_user_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *RevokeRoleRequest) GetSubjectId() string {
@@ -3064,7 +3279,7 @@ type CheckPermissionRequest struct {
 
 func (x *CheckPermissionRequest) Reset() {
 	*x = CheckPermissionRequest{}
-	mi := &file_user_proto_msgTypes[48]
+	mi := &file_user_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3076,7 +3291,7 @@ func (x *CheckPermissionRequest) String() string {
 func (*CheckPermissionRequest) ProtoMessage() {}
 
 func (x *CheckPermissionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[48]
+	mi := &file_user_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3089,7 +3304,7 @@ func (x *CheckPermissionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckPermissionRequest.ProtoReflect.Descriptor instead.
 func (*CheckPermissionRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{48}
+	return file_user_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *CheckPermissionRequest) GetSubjectId() string {
@@ -3144,7 +3359,7 @@ type CheckPermissionResponse struct {
 
 func (x *CheckPermissionResponse) Reset() {
 	*x = CheckPermissionResponse{}
-	mi := &file_user_proto_msgTypes[49]
+	mi := &file_user_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3156,7 +3371,7 @@ func (x *CheckPermissionResponse) String() string {
 func (*CheckPermissionResponse) ProtoMessage() {}
 
 func (x *CheckPermissionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[49]
+	mi := &file_user_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3169,7 +3384,7 @@ func (x *CheckPermissionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckPermissionResponse.ProtoReflect.Descriptor instead.
 func (*CheckPermissionResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{49}
+	return file_user_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *CheckPermissionResponse) GetAllowed() bool {
@@ -3196,7 +3411,7 @@ type ResolveIdentityRequest struct {
 
 func (x *ResolveIdentityRequest) Reset() {
 	*x = ResolveIdentityRequest{}
-	mi := &file_user_proto_msgTypes[50]
+	mi := &file_user_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3208,7 +3423,7 @@ func (x *ResolveIdentityRequest) String() string {
 func (*ResolveIdentityRequest) ProtoMessage() {}
 
 func (x *ResolveIdentityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[50]
+	mi := &file_user_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3221,7 +3436,7 @@ func (x *ResolveIdentityRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResolveIdentityRequest.ProtoReflect.Descriptor instead.
 func (*ResolveIdentityRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{50}
+	return file_user_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *ResolveIdentityRequest) GetProvider() string {
@@ -3250,7 +3465,7 @@ type ResolveIdentityResponse struct {
 
 func (x *ResolveIdentityResponse) Reset() {
 	*x = ResolveIdentityResponse{}
-	mi := &file_user_proto_msgTypes[51]
+	mi := &file_user_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3262,7 +3477,7 @@ func (x *ResolveIdentityResponse) String() string {
 func (*ResolveIdentityResponse) ProtoMessage() {}
 
 func (x *ResolveIdentityResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[51]
+	mi := &file_user_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3275,7 +3490,7 @@ func (x *ResolveIdentityResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResolveIdentityResponse.ProtoReflect.Descriptor instead.
 func (*ResolveIdentityResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{51}
+	return file_user_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *ResolveIdentityResponse) GetUserId() string {
@@ -3319,13 +3534,26 @@ type APIKey struct {
 	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	LastUsedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
 	RevokedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	RotatedFromId  string                 `protobuf:"bytes,12,opt,name=rotated_from_id,json=rotatedFromId,proto3" json:"rotated_from_id,omitempty"` // predecessor, for keys issued by RotateAPIKey
+	RevokeAt       *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=revoke_at,json=revokeAt,proto3" json:"revoke_at,omitempty"`                  // end of the rotation grace period, when set
+	// Set while the owner lacks a permission behind one of the scopes; the key
+	// is rejected until re-evaluation finds the permissions back.
+	DisabledAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=disabled_at,json=disabledAt,proto3" json:"disabled_at,omitempty"`
+	DisabledReason string                 `protobuf:"bytes,15,opt,name=disabled_reason,json=disabledReason,proto3" json:"disabled_reason,omitempty"`
+	LastUsedIp     string                 `protobuf:"bytes,16,opt,name=last_used_ip,json=lastUsedIp,proto3" json:"last_used_ip,omitempty"`
+	DailyUsage     []*APIKeyDailyUsage    `protobuf:"bytes,17,rep,name=daily_usage,json=dailyUsage,proto3" json:"daily_usage,omitempty"` // filled by ListAPIKeys when usage_days is set
+	// Optional restrictions; empty = any. CIDRs such as 203.0.113.0/24 (a bare
+	// IP means that address only); origins such as https://app.example.com or
+	// https://*.example.com, matched against the request's Origin or Referer.
+	AllowedCidrs   []string `protobuf:"bytes,18,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	AllowedOrigins []string `protobuf:"bytes,19,rep,name=allowed_origins,json=allowedOrigins,proto3" json:"allowed_origins,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *APIKey) Reset() {
 	*x = APIKey{}
-	mi := &file_user_proto_msgTypes[52]
+	mi := &file_user_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3337,7 +3565,7 @@ func (x *APIKey) String() string {
 func (*APIKey) ProtoMessage() {}
 
 func (x *APIKey) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[52]
+	mi := &file_user_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3350,7 +3578,7 @@ func (x *APIKey) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
 func (*APIKey) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{52}
+	return file_user_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *APIKey) GetId() string {
@@ -3430,105 +3658,88 @@ func (x *APIKey) GetRevokedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-type CreateAPIKeyRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
-	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Scopes         []*Permission          `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
-	Environment    APIKeyEnvironment      `protobuf:"varint,4,opt,name=environment,proto3,enum=customers.APIKeyEnvironment" json:"environment,omitempty"`
-	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
-}
-
-func (x *CreateAPIKeyRequest) Reset() {
-	*x = CreateAPIKeyRequest{}
-	mi := &file_user_proto_msgTypes[53]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *CreateAPIKeyRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *APIKey) GetRotatedFromId() string {
+	if x != nil {
+		return x.RotatedFromId
+	}
+	return ""
 }
 
-func (*CreateAPIKeyRequest) ProtoMessage() {}
-
-func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[53]
+func (x *APIKey) GetRevokeAt() *timestamppb.Timestamp {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.RevokeAt
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
-func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{53}
+func (x *APIKey) GetDisabledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisabledAt
+	}
+	return nil
 }
 
-func (x *CreateAPIKeyRequest) GetOrganizationId() string {
+func (x *APIKey) GetDisabledReason() string {
 	if x != nil {
-		return x.OrganizationId
+		return x.DisabledReason
 	}
 	return ""
 }
 
-func (x *CreateAPIKeyRequest) GetName() string {
+func (x *APIKey) GetLastUsedIp() string {
 	if x != nil {
-		return x.Name
+		return x.LastUsedIp
 	}
 	return ""
 }
 
-func (x *CreateAPIKeyRequest) GetScopes() []*Permission {
+func (x *APIKey) GetDailyUsage() []*APIKeyDailyUsage {
 	if x != nil {
-		return x.Scopes
+		return x.DailyUsage
 	}
 	return nil
 }
 
-func (x *CreateAPIKeyRequest) GetEnvironment() APIKeyEnvironment {
+func (x *APIKey) GetAllowedCidrs() []string {
 	if x != nil {
-		return x.Environment
+		return x.AllowedCidrs
 	}
-	return APIKeyEnvironment_API_KEY_ENVIRONMENT_UNSPECIFIED
+	return nil
 }
 
-func (x *CreateAPIKeyRequest) GetExpiresAt() *timestamppb.Timestamp {
+func (x *APIKey) GetAllowedOrigins() []string {
 	if x != nil {
-		return x.ExpiresAt
+		return x.AllowedOrigins
 	}
 	return nil
 }
 
-type CreateAPIKeyResponse struct {
+// Requests validated with a key on one day (UTC). Validation results are
+// cached by the sidecar, so this counts backend validations: a lower bound of
+// the requests made with the key.
+type APIKeyDailyUsage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           *APIKey                `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	PlaintextKey  string                 `protobuf:"bytes,2,opt,name=plaintext_key,json=plaintextKey,proto3" json:"plaintext_key,omitempty"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD
+	Requests      int64                  `protobuf:"varint,2,opt,name=requests,proto3" json:"requests,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateAPIKeyResponse) Reset() {
-	*x = CreateAPIKeyResponse{}
-	mi := &file_user_proto_msgTypes[54]
+func (x *APIKeyDailyUsage) Reset() {
+	*x = APIKeyDailyUsage{}
+	mi := &file_user_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateAPIKeyResponse) String() string {
+func (x *APIKeyDailyUsage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateAPIKeyResponse) ProtoMessage() {}
+func (*APIKeyDailyUsage) ProtoMessage() {}
 
-func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[54]
+func (x *APIKeyDailyUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3539,37 +3750,185 @@ func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
-func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{54}
+// Deprecated: Use APIKeyDailyUsage.ProtoReflect.Descriptor instead.
+func (*APIKeyDailyUsage) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *CreateAPIKeyResponse) GetKey() *APIKey {
+func (x *APIKeyDailyUsage) GetDate() string {
 	if x != nil {
-		return x.Key
+		return x.Date
 	}
-	return nil
+	return ""
 }
 
-func (x *CreateAPIKeyResponse) GetPlaintextKey() string {
+func (x *APIKeyDailyUsage) GetRequests() int64 {
 	if x != nil {
-		return x.PlaintextKey
+		return x.Requests
 	}
-	return ""
+	return 0
 }
 
-type ListAPIKeysRequest struct {
+type CreateAPIKeyRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
-	PageSize       int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken      string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes         []*Permission          `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Environment    APIKeyEnvironment      `protobuf:"varint,4,opt,name=environment,proto3,enum=customers.APIKeyEnvironment" json:"environment,omitempty"`
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	AllowedCidrs   []string               `protobuf:"bytes,6,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	AllowedOrigins []string               `protobuf:"bytes,7,rep,name=allowed_origins,json=allowedOrigins,proto3" json:"allowed_origins,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_user_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CreateAPIKeyRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []*Permission {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetEnvironment() APIKeyEnvironment {
+	if x != nil {
+		return x.Environment
+	}
+	return APIKeyEnvironment_API_KEY_ENVIRONMENT_UNSPECIFIED
+}
+
+func (x *CreateAPIKeyRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetAllowedOrigins() []string {
+	if x != nil {
+		return x.AllowedOrigins
+	}
+	return nil
+}
+
+type CreateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *APIKey                `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	PlaintextKey  string                 `protobuf:"bytes,2,opt,name=plaintext_key,json=plaintextKey,proto3" json:"plaintext_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_user_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *CreateAPIKeyResponse) GetKey() *APIKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyResponse) GetPlaintextKey() string {
+	if x != nil {
+		return x.PlaintextKey
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	PageSize       int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken      string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Include daily usage for the last usage_days days; 0 = none.
+	UsageDays int32 `protobuf:"varint,4,opt,name=usage_days,json=usageDays,proto3" json:"usage_days,omitempty"`
+	// Only keys not used since then (never-used keys created before it), to find stale keys.
+	UnusedSince   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=unused_since,json=unusedSince,proto3" json:"unused_since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
 func (x *ListAPIKeysRequest) Reset() {
 	*x = ListAPIKeysRequest{}
-	mi := &file_user_proto_msgTypes[55]
+	mi := &file_user_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3581,7 +3940,7 @@ func (x *ListAPIKeysRequest) String() string {
 func (*ListAPIKeysRequest) ProtoMessage() {}
 
 func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[55]
+	mi := &file_user_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3594,7 +3953,7 @@ func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
 func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{55}
+	return file_user_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *ListAPIKeysRequest) GetOrganizationId() string {
@@ -3618,6 +3977,20 @@ func (x *ListAPIKeysRequest) GetPageToken() string {
 	return ""
 }
 
+func (x *ListAPIKeysRequest) GetUsageDays() int32 {
+	if x != nil {
+		return x.UsageDays
+	}
+	return 0
+}
+
+func (x *ListAPIKeysRequest) GetUnusedSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UnusedSince
+	}
+	return nil
+}
+
 type ListAPIKeysResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Keys          []*APIKey              `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
@@ -3628,7 +4001,7 @@ type ListAPIKeysResponse struct {
 
 func (x *ListAPIKeysResponse) Reset() {
 	*x = ListAPIKeysResponse{}
-	mi := &file_user_proto_msgTypes[56]
+	mi := &file_user_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3640,7 +4013,7 @@ func (x *ListAPIKeysResponse) String() string {
 func (*ListAPIKeysResponse) ProtoMessage() {}
 
 func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[56]
+	mi := &file_user_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3653,7 +4026,7 @@ func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
 func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{56}
+	return file_user_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *ListAPIKeysResponse) GetKeys() []*APIKey {
@@ -3670,6 +4043,67 @@ func (x *ListAPIKeysResponse) GetNextPageToken() string {
 	return ""
 }
 
+// Editable: name, allowed_cidrs, allowed_origins.
+type UpdateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key           *APIKey                `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAPIKeyRequest) Reset() {
+	*x = UpdateAPIKeyRequest{}
+	mi := &file_user_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAPIKeyRequest) ProtoMessage() {}
+
+func (x *UpdateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *UpdateAPIKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateAPIKeyRequest) GetKey() *APIKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *UpdateAPIKeyRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 type RevokeAPIKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -3679,7 +4113,7 @@ type RevokeAPIKeyRequest struct {
 
 func (x *RevokeAPIKeyRequest) Reset() {
 	*x = RevokeAPIKeyRequest{}
-	mi := &file_user_proto_msgTypes[57]
+	mi := &file_user_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3691,7 +4125,7 @@ func (x *RevokeAPIKeyRequest) String() string {
 func (*RevokeAPIKeyRequest) ProtoMessage() {}
 
 func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[57]
+	mi := &file_user_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3704,7 +4138,7 @@ func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
 func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{57}
+	return file_user_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *RevokeAPIKeyRequest) GetId() string {
@@ -3714,28 +4148,29 @@ func (x *RevokeAPIKeyRequest) GetId() string {
 	return ""
 }
 
-type ValidateAPIKeyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	KeyHash       string                 `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type RevokeAPIKeysRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Ids            []string               `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ValidateAPIKeyRequest) Reset() {
-	*x = ValidateAPIKeyRequest{}
-	mi := &file_user_proto_msgTypes[58]
+func (x *RevokeAPIKeysRequest) Reset() {
+	*x = RevokeAPIKeysRequest{}
+	mi := &file_user_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateAPIKeyRequest) String() string {
+func (x *RevokeAPIKeysRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateAPIKeyRequest) ProtoMessage() {}
+func (*RevokeAPIKeysRequest) ProtoMessage() {}
 
-func (x *ValidateAPIKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[58]
+func (x *RevokeAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3746,43 +4181,1535 @@ func (x *ValidateAPIKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateAPIKeyRequest.ProtoReflect.Descriptor instead.
-func (*ValidateAPIKeyRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{58}
+// Deprecated: Use RevokeAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *ValidateAPIKeyRequest) GetKeyHash() string {
+func (x *RevokeAPIKeysRequest) GetOrganizationId() string {
 	if x != nil {
-		return x.KeyHash
+		return x.OrganizationId
 	}
 	return ""
 }
 
-type ValidateAPIKeyResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Valid          bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	OrganizationId string                 `protobuf:"bytes,3,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
-	Scopes         []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *RevokeAPIKeysRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
 }
 
-func (x *ValidateAPIKeyResponse) Reset() {
-	*x = ValidateAPIKeyResponse{}
-	mi := &file_user_proto_msgTypes[59]
+type RevokeAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RevokedIds    []string               `protobuf:"bytes,1,rep,name=revoked_ids,json=revokedIds,proto3" json:"revoked_ids,omitempty"` // ids already revoked or not in the org are skipped
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeysResponse) Reset() {
+	*x = RevokeAPIKeysResponse{}
+	mi := &file_user_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeysResponse) ProtoMessage() {}
+
+func (x *RevokeAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *RevokeAPIKeysResponse) GetRevokedIds() []string {
+	if x != nil {
+		return x.RevokedIds
+	}
+	return nil
+}
+
+type RotateAPIKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// How long the old key keeps working; 0 means the default (24h). At most 30 days.
+	GracePeriodSeconds int64 `protobuf:"varint,2,opt,name=grace_period_seconds,json=gracePeriodSeconds,proto3" json:"grace_period_seconds,omitempty"`
+	// Expiry of the new key; defaults to the old key's.
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyRequest) Reset() {
+	*x = RotateAPIKeyRequest{}
+	mi := &file_user_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyRequest) ProtoMessage() {}
+
+func (x *RotateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *RotateAPIKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RotateAPIKeyRequest) GetGracePeriodSeconds() int64 {
+	if x != nil {
+		return x.GracePeriodSeconds
+	}
+	return 0
+}
+
+func (x *RotateAPIKeyRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type RotateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *APIKey                `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"` // the successor
+	PlaintextKey  string                 `protobuf:"bytes,2,opt,name=plaintext_key,json=plaintextKey,proto3" json:"plaintext_key,omitempty"`
+	PreviousKey   *APIKey                `protobuf:"bytes,3,opt,name=previous_key,json=previousKey,proto3" json:"previous_key,omitempty"` // with revoke_at set
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyResponse) Reset() {
+	*x = RotateAPIKeyResponse{}
+	mi := &file_user_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyResponse) ProtoMessage() {}
+
+func (x *RotateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *RotateAPIKeyResponse) GetKey() *APIKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *RotateAPIKeyResponse) GetPlaintextKey() string {
+	if x != nil {
+		return x.PlaintextKey
+	}
+	return ""
+}
+
+func (x *RotateAPIKeyResponse) GetPreviousKey() *APIKey {
+	if x != nil {
+		return x.PreviousKey
+	}
+	return nil
+}
+
+type ReevaluateAPIKeysRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReevaluateAPIKeysRequest) Reset() {
+	*x = ReevaluateAPIKeysRequest{}
+	mi := &file_user_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReevaluateAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReevaluateAPIKeysRequest) ProtoMessage() {}
+
+func (x *ReevaluateAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReevaluateAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ReevaluateAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ReevaluateAPIKeysRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+type ReevaluateAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Checked       int32                  `protobuf:"varint,1,opt,name=checked,proto3" json:"checked,omitempty"`
+	Disabled      []*APIKey              `protobuf:"bytes,2,rep,name=disabled,proto3" json:"disabled,omitempty"`                               // owner lost a permission behind the scopes
+	Enabled       []*APIKey              `protobuf:"bytes,3,rep,name=enabled,proto3" json:"enabled,omitempty"`                                 // owner has the permissions again
+	FailedKeyIds  []string               `protobuf:"bytes,4,rep,name=failed_key_ids,json=failedKeyIds,proto3" json:"failed_key_ids,omitempty"` // could not be re-evaluated; retried on the next run
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReevaluateAPIKeysResponse) Reset() {
+	*x = ReevaluateAPIKeysResponse{}
+	mi := &file_user_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReevaluateAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReevaluateAPIKeysResponse) ProtoMessage() {}
+
+func (x *ReevaluateAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReevaluateAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ReevaluateAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ReevaluateAPIKeysResponse) GetChecked() int32 {
+	if x != nil {
+		return x.Checked
+	}
+	return 0
+}
+
+func (x *ReevaluateAPIKeysResponse) GetDisabled() []*APIKey {
+	if x != nil {
+		return x.Disabled
+	}
+	return nil
+}
+
+func (x *ReevaluateAPIKeysResponse) GetEnabled() []*APIKey {
+	if x != nil {
+		return x.Enabled
+	}
+	return nil
+}
+
+func (x *ReevaluateAPIKeysResponse) GetFailedKeyIds() []string {
+	if x != nil {
+		return x.FailedKeyIds
+	}
+	return nil
+}
+
+type ValidateAPIKeyRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	KeyHash string                 `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"`
+	// The request's Origin (or Referer), for keys with allowed_origins. The
+	// caller IP is read from the x-forwarded-for metadata.
+	Origin        string `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateAPIKeyRequest) Reset() {
+	*x = ValidateAPIKeyRequest{}
+	mi := &file_user_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateAPIKeyRequest) ProtoMessage() {}
+
+func (x *ValidateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*ValidateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ValidateAPIKeyRequest) GetKeyHash() string {
+	if x != nil {
+		return x.KeyHash
+	}
+	return ""
+}
+
+func (x *ValidateAPIKeyRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+type ValidateAPIKeyResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Valid          bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrganizationId string                 `protobuf:"bytes,3,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Scopes         []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	KeyId          string                 `protobuf:"bytes,5,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"` // lets callers that cache results invalidate them on revoke
+	// Set while a rotated key is in its grace period: it stops working at revoke_at.
+	Deprecated bool                   `protobuf:"varint,6,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	RevokeAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=revoke_at,json=revokeAt,proto3" json:"revoke_at,omitempty"`
+	Warning    string                 `protobuf:"bytes,8,opt,name=warning,proto3" json:"warning,omitempty"`
+	Reason     APIKeyInvalidReason    `protobuf:"varint,9,opt,name=reason,proto3,enum=customers.APIKeyInvalidReason" json:"reason,omitempty"` // why the key is invalid
+	// Restrictions of a valid key, so that callers caching the result can
+	// check later requests themselves.
+	AllowedCidrs   []string `protobuf:"bytes,10,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	AllowedOrigins []string `protobuf:"bytes,11,rep,name=allowed_origins,json=allowedOrigins,proto3" json:"allowed_origins,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ValidateAPIKeyResponse) Reset() {
+	*x = ValidateAPIKeyResponse{}
+	mi := &file_user_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateAPIKeyResponse) ProtoMessage() {}
+
+func (x *ValidateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*ValidateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ValidateAPIKeyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateAPIKeyResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ValidateAPIKeyResponse) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *ValidateAPIKeyResponse) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ValidateAPIKeyResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *ValidateAPIKeyResponse) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+func (x *ValidateAPIKeyResponse) GetRevokeAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokeAt
+	}
+	return nil
+}
+
+func (x *ValidateAPIKeyResponse) GetWarning() string {
+	if x != nil {
+		return x.Warning
+	}
+	return ""
+}
+
+func (x *ValidateAPIKeyResponse) GetReason() APIKeyInvalidReason {
+	if x != nil {
+		return x.Reason
+	}
+	return APIKeyInvalidReason_API_KEY_INVALID_REASON_UNSPECIFIED
+}
+
+func (x *ValidateAPIKeyResponse) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+func (x *ValidateAPIKeyResponse) GetAllowedOrigins() []string {
+	if x != nil {
+		return x.AllowedOrigins
+	}
+	return nil
+}
+
+type LeakedAPIKeyReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`     // the partner's token type
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`       // where the token was found
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"` // e.g. content, commit, gist
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeakedAPIKeyReport) Reset() {
+	*x = LeakedAPIKeyReport{}
+	mi := &file_user_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeakedAPIKeyReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeakedAPIKeyReport) ProtoMessage() {}
+
+func (x *LeakedAPIKeyReport) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeakedAPIKeyReport.ProtoReflect.Descriptor instead.
+func (*LeakedAPIKeyReport) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *LeakedAPIKeyReport) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *LeakedAPIKeyReport) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LeakedAPIKeyReport) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *LeakedAPIKeyReport) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type ReportLeakedAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reports       []*LeakedAPIKeyReport  `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportLeakedAPIKeysRequest) Reset() {
+	*x = ReportLeakedAPIKeysRequest{}
+	mi := &file_user_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportLeakedAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportLeakedAPIKeysRequest) ProtoMessage() {}
+
+func (x *ReportLeakedAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportLeakedAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ReportLeakedAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ReportLeakedAPIKeysRequest) GetReports() []*LeakedAPIKeyReport {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+type LeakedAPIKeyResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	TruePositive  bool                   `protobuf:"varint,2,opt,name=true_positive,json=truePositive,proto3" json:"true_positive,omitempty"` // the token is one of our keys (revoked now, or already)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeakedAPIKeyResult) Reset() {
+	*x = LeakedAPIKeyResult{}
+	mi := &file_user_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeakedAPIKeyResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeakedAPIKeyResult) ProtoMessage() {}
+
+func (x *LeakedAPIKeyResult) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeakedAPIKeyResult.ProtoReflect.Descriptor instead.
+func (*LeakedAPIKeyResult) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *LeakedAPIKeyResult) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *LeakedAPIKeyResult) GetTruePositive() bool {
+	if x != nil {
+		return x.TruePositive
+	}
+	return false
+}
+
+type ReportLeakedAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*LeakedAPIKeyResult  `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // one per report, in order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportLeakedAPIKeysResponse) Reset() {
+	*x = ReportLeakedAPIKeysResponse{}
+	mi := &file_user_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportLeakedAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportLeakedAPIKeysResponse) ProtoMessage() {}
+
+func (x *ReportLeakedAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportLeakedAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ReportLeakedAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *ReportLeakedAPIKeysResponse) GetResults() []*LeakedAPIKeyResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetRateLimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateLimitsRequest) Reset() {
+	*x = GetRateLimitsRequest{}
+	mi := &file_user_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitsRequest) ProtoMessage() {}
+
+func (x *GetRateLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitsRequest.ProtoReflect.Descriptor instead.
+func (*GetRateLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *GetRateLimitsRequest) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+type GetRateLimitsResponse struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	ApiCallsMonthly         int64                  `protobuf:"varint,1,opt,name=api_calls_monthly,json=apiCallsMonthly,proto3" json:"api_calls_monthly,omitempty"`                             // -1 = unlimited, 0 = not in plan
+	ApiCallsUsed            int64                  `protobuf:"varint,2,opt,name=api_calls_used,json=apiCallsUsed,proto3" json:"api_calls_used,omitempty"`                                      // in the current period
+	ApiKeyRequestsPerMinute int64                  `protobuf:"varint,3,opt,name=api_key_requests_per_minute,json=apiKeyRequestsPerMinute,proto3" json:"api_key_requests_per_minute,omitempty"` // -1 = unlimited, 0 = not in plan
+	PeriodEnd               *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=period_end,json=periodEnd,proto3" json:"period_end,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *GetRateLimitsResponse) Reset() {
+	*x = GetRateLimitsResponse{}
+	mi := &file_user_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitsResponse) ProtoMessage() {}
+
+func (x *GetRateLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitsResponse.ProtoReflect.Descriptor instead.
+func (*GetRateLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *GetRateLimitsResponse) GetApiCallsMonthly() int64 {
+	if x != nil {
+		return x.ApiCallsMonthly
+	}
+	return 0
+}
+
+func (x *GetRateLimitsResponse) GetApiCallsUsed() int64 {
+	if x != nil {
+		return x.ApiCallsUsed
+	}
+	return 0
+}
+
+func (x *GetRateLimitsResponse) GetApiKeyRequestsPerMinute() int64 {
+	if x != nil {
+		return x.ApiKeyRequestsPerMinute
+	}
+	return 0
+}
+
+func (x *GetRateLimitsResponse) GetPeriodEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeriodEnd
+	}
+	return nil
+}
+
+type UsageRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	Feature       string                 `protobuf:"bytes,2,opt,name=feature,proto3" json:"feature,omitempty"`
+	Quantity      int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageRecord) Reset() {
+	*x = UsageRecord{}
+	mi := &file_user_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageRecord) ProtoMessage() {}
+
+func (x *UsageRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageRecord.ProtoReflect.Descriptor instead.
+func (*UsageRecord) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *UsageRecord) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *UsageRecord) GetFeature() string {
+	if x != nil {
+		return x.Feature
+	}
+	return ""
+}
+
+func (x *UsageRecord) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type RecordUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*UsageRecord         `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordUsageRequest) Reset() {
+	*x = RecordUsageRequest{}
+	mi := &file_user_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordUsageRequest) ProtoMessage() {}
+
+func (x *RecordUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordUsageRequest.ProtoReflect.Descriptor instead.
+func (*RecordUsageRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *RecordUsageRequest) GetRecords() []*UsageRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type AuthenticateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	ProviderId    string                 `protobuf:"bytes,2,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	ProviderEmail string                 `protobuf:"bytes,3,opt,name=provider_email,json=providerEmail,proto3" json:"provider_email,omitempty"`
+	EmailVerified bool                   `protobuf:"varint,4,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"`
+	Profile       map[string]string      `protobuf:"bytes,5,rep,name=profile,proto3" json:"profile,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DeviceInfo    string                 `protobuf:"bytes,6,opt,name=device_info,json=deviceInfo,proto3" json:"device_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateRequest) Reset() {
+	*x = AuthenticateRequest{}
+	mi := &file_user_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateRequest) ProtoMessage() {}
+
+func (x *AuthenticateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateRequest.ProtoReflect.Descriptor instead.
+func (*AuthenticateRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *AuthenticateRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *AuthenticateRequest) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *AuthenticateRequest) GetProviderEmail() string {
+	if x != nil {
+		return x.ProviderEmail
+	}
+	return ""
+}
+
+func (x *AuthenticateRequest) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *AuthenticateRequest) GetProfile() map[string]string {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *AuthenticateRequest) GetDeviceInfo() string {
+	if x != nil {
+		return x.DeviceInfo
+	}
+	return ""
+}
+
+type AuthenticateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	ExpiresIn     int64                  `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	User          *User                  `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateResponse) Reset() {
+	*x = AuthenticateResponse{}
+	mi := &file_user_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateResponse) ProtoMessage() {}
+
+func (x *AuthenticateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateResponse.ProtoReflect.Descriptor instead.
+func (*AuthenticateResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *AuthenticateResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *AuthenticateResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *AuthenticateResponse) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *AuthenticateResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
+	mi := &file_user_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenRequest) ProtoMessage() {}
+
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *RefreshTokenRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type RefreshTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	ExpiresIn     int64                  `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenResponse) Reset() {
+	*x = RefreshTokenResponse{}
+	mi := &file_user_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenResponse) ProtoMessage() {}
+
+func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenResponse.ProtoReflect.Descriptor instead.
+func (*RefreshTokenResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *RefreshTokenResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *RefreshTokenResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *RefreshTokenResponse) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+type LogoutRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// Optional: the current access token, denylisted until it expires.
+	AccessToken   string `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
+	mi := &file_user_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequest) ProtoMessage() {}
+
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *LogoutRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *LogoutRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+type JWKSResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeysJson      string                 `protobuf:"bytes,1,opt,name=keys_json,json=keysJson,proto3" json:"keys_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JWKSResponse) Reset() {
+	*x = JWKSResponse{}
+	mi := &file_user_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JWKSResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JWKSResponse) ProtoMessage() {}
+
+func (x *JWKSResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JWKSResponse.ProtoReflect.Descriptor instead.
+func (*JWKSResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *JWKSResponse) GetKeysJson() string {
+	if x != nil {
+		return x.KeysJson
+	}
+	return ""
+}
+
+type AuditEvent struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActorId    string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorType  string                 `protobuf:"bytes,3,opt,name=actor_type,json=actorType,proto3" json:"actor_type,omitempty"`
+	Action     string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Resource   string                 `protobuf:"bytes,5,opt,name=resource,proto3" json:"resource,omitempty"`
+	ResourceId string                 `protobuf:"bytes,6,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	OrgId      string                 `protobuf:"bytes,7,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	Metadata   map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	IpAddress  string                 `protobuf:"bytes,9,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Position in the org's hash chain; 0 for events recorded before chaining
+	ChainSeq int64  `protobuf:"varint,11,opt,name=chain_seq,json=chainSeq,proto3" json:"chain_seq,omitempty"`
+	PrevHash string `protobuf:"bytes,12,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+	// sha256 of prev_hash, a newline and the event's canonical JSON
+	Hash          string `protobuf:"bytes,13,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_user_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *AuditEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorType() string {
+	if x != nil {
+		return x.ActorType
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *AuditEvent) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AuditEvent) GetChainSeq() int64 {
+	if x != nil {
+		return x.ChainSeq
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetPrevHash() string {
+	if x != nil {
+		return x.PrevHash
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type QueryAuditLogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Resource      string                 `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`
+	ResourceId    string                 `protobuf:"bytes,5,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=to,proto3" json:"to,omitempty"`
+	PageSize      int32                  `protobuf:"varint,8,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditLogRequest) Reset() {
+	*x = QueryAuditLogRequest{}
+	mi := &file_user_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAuditLogRequest) ProtoMessage() {}
+
+func (x *QueryAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *QueryAuditLogRequest) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *QueryAuditLogRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *QueryAuditLogRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type QueryAuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditLogResponse) Reset() {
+	*x = QueryAuditLogResponse{}
+	mi := &file_user_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateAPIKeyResponse) String() string {
+func (x *QueryAuditLogResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateAPIKeyResponse) ProtoMessage() {}
+func (*QueryAuditLogResponse) ProtoMessage() {}
 
-func (x *ValidateAPIKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[59]
+func (x *QueryAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3793,66 +5720,58 @@ func (x *ValidateAPIKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateAPIKeyResponse.ProtoReflect.Descriptor instead.
-func (*ValidateAPIKeyResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{59}
-}
-
-func (x *ValidateAPIKeyResponse) GetValid() bool {
-	if x != nil {
-		return x.Valid
-	}
-	return false
+// Deprecated: Use QueryAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{86}
 }
 
-func (x *ValidateAPIKeyResponse) GetUserId() string {
+func (x *QueryAuditLogResponse) GetEvents() []*AuditEvent {
 	if x != nil {
-		return x.UserId
+		return x.Events
 	}
-	return ""
+	return nil
 }
 
-func (x *ValidateAPIKeyResponse) GetOrganizationId() string {
+func (x *QueryAuditLogResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.OrganizationId
+		return x.NextPageToken
 	}
 	return ""
 }
 
-func (x *ValidateAPIKeyResponse) GetScopes() []string {
+func (x *QueryAuditLogResponse) GetTotalCount() int32 {
 	if x != nil {
-		return x.Scopes
+		return x.TotalCount
 	}
-	return nil
+	return 0
 }
 
-type AuthenticateRequest struct {
+// VerifyAuditChainRequest selects the events of an org's chain (events
+// without an org when org_id is empty) recorded between from and to.
+type VerifyAuditChainRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
-	ProviderId    string                 `protobuf:"bytes,2,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
-	ProviderEmail string                 `protobuf:"bytes,3,opt,name=provider_email,json=providerEmail,proto3" json:"provider_email,omitempty"`
-	EmailVerified bool                   `protobuf:"varint,4,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"`
-	Profile       map[string]string      `protobuf:"bytes,5,rep,name=profile,proto3" json:"profile,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	DeviceInfo    string                 `protobuf:"bytes,6,opt,name=device_info,json=deviceInfo,proto3" json:"device_info,omitempty"`
+	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AuthenticateRequest) Reset() {
-	*x = AuthenticateRequest{}
-	mi := &file_user_proto_msgTypes[60]
+func (x *VerifyAuditChainRequest) Reset() {
+	*x = VerifyAuditChainRequest{}
+	mi := &file_user_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AuthenticateRequest) String() string {
+func (x *VerifyAuditChainRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthenticateRequest) ProtoMessage() {}
+func (*VerifyAuditChainRequest) ProtoMessage() {}
 
-func (x *AuthenticateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[60]
+func (x *VerifyAuditChainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3863,78 +5782,58 @@ func (x *AuthenticateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthenticateRequest.ProtoReflect.Descriptor instead.
-func (*AuthenticateRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{60}
-}
-
-func (x *AuthenticateRequest) GetProvider() string {
-	if x != nil {
-		return x.Provider
-	}
-	return ""
-}
-
-func (x *AuthenticateRequest) GetProviderId() string {
-	if x != nil {
-		return x.ProviderId
-	}
-	return ""
+// Deprecated: Use VerifyAuditChainRequest.ProtoReflect.Descriptor instead.
+func (*VerifyAuditChainRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *AuthenticateRequest) GetProviderEmail() string {
+func (x *VerifyAuditChainRequest) GetOrgId() string {
 	if x != nil {
-		return x.ProviderEmail
+		return x.OrgId
 	}
 	return ""
 }
 
-func (x *AuthenticateRequest) GetEmailVerified() bool {
-	if x != nil {
-		return x.EmailVerified
-	}
-	return false
-}
-
-func (x *AuthenticateRequest) GetProfile() map[string]string {
+func (x *VerifyAuditChainRequest) GetFrom() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Profile
+		return x.From
 	}
 	return nil
 }
 
-func (x *AuthenticateRequest) GetDeviceInfo() string {
+func (x *VerifyAuditChainRequest) GetTo() *timestamppb.Timestamp {
 	if x != nil {
-		return x.DeviceInfo
+		return x.To
 	}
-	return ""
+	return nil
 }
 
-type AuthenticateResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
-	ExpiresIn     int64                  `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
-	User          *User                  `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+// AuditChainBreak is the first event where the chain doesn't verify
+type AuditChainBreak struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ChainSeq int64                  `protobuf:"varint,1,opt,name=chain_seq,json=chainSeq,proto3" json:"chain_seq,omitempty"`
+	// Empty when the event is missing
+	EventId       string `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AuthenticateResponse) Reset() {
-	*x = AuthenticateResponse{}
-	mi := &file_user_proto_msgTypes[61]
+func (x *AuditChainBreak) Reset() {
+	*x = AuditChainBreak{}
+	mi := &file_user_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AuthenticateResponse) String() string {
+func (x *AuditChainBreak) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthenticateResponse) ProtoMessage() {}
+func (*AuditChainBreak) ProtoMessage() {}
 
-func (x *AuthenticateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[61]
+func (x *AuditChainBreak) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3945,61 +5844,57 @@ func (x *AuthenticateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthenticateResponse.ProtoReflect.Descriptor instead.
-func (*AuthenticateResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{61}
+// Deprecated: Use AuditChainBreak.ProtoReflect.Descriptor instead.
+func (*AuditChainBreak) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *AuthenticateResponse) GetAccessToken() string {
+func (x *AuditChainBreak) GetChainSeq() int64 {
 	if x != nil {
-		return x.AccessToken
+		return x.ChainSeq
 	}
-	return ""
+	return 0
 }
 
-func (x *AuthenticateResponse) GetRefreshToken() string {
+func (x *AuditChainBreak) GetEventId() string {
 	if x != nil {
-		return x.RefreshToken
+		return x.EventId
 	}
 	return ""
 }
 
-func (x *AuthenticateResponse) GetExpiresIn() int64 {
-	if x != nil {
-		return x.ExpiresIn
-	}
-	return 0
-}
-
-func (x *AuthenticateResponse) GetUser() *User {
+func (x *AuditChainBreak) GetReason() string {
 	if x != nil {
-		return x.User
+		return x.Reason
 	}
-	return nil
+	return ""
 }
 
-type RefreshTokenRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type VerifyAuditChainResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Valid              bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Checked            int64                  `protobuf:"varint,2,opt,name=checked,proto3" json:"checked,omitempty"`
+	CheckpointsChecked int32                  `protobuf:"varint,3,opt,name=checkpoints_checked,json=checkpointsChecked,proto3" json:"checkpoints_checked,omitempty"`
+	FirstBreak         *AuditChainBreak       `protobuf:"bytes,4,opt,name=first_break,json=firstBreak,proto3" json:"first_break,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
-func (x *RefreshTokenRequest) Reset() {
-	*x = RefreshTokenRequest{}
-	mi := &file_user_proto_msgTypes[62]
+func (x *VerifyAuditChainResponse) Reset() {
+	*x = VerifyAuditChainResponse{}
+	mi := &file_user_proto_msgTypes[89]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RefreshTokenRequest) String() string {
+func (x *VerifyAuditChainResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RefreshTokenRequest) ProtoMessage() {}
+func (*VerifyAuditChainResponse) ProtoMessage() {}
 
-func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[62]
+func (x *VerifyAuditChainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[89]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4010,42 +5905,69 @@ func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
-func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{62}
+// Deprecated: Use VerifyAuditChainResponse.ProtoReflect.Descriptor instead.
+func (*VerifyAuditChainResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{89}
 }
 
-func (x *RefreshTokenRequest) GetRefreshToken() string {
+func (x *VerifyAuditChainResponse) GetValid() bool {
 	if x != nil {
-		return x.RefreshToken
+		return x.Valid
 	}
-	return ""
+	return false
 }
 
-type RefreshTokenResponse struct {
+func (x *VerifyAuditChainResponse) GetChecked() int64 {
+	if x != nil {
+		return x.Checked
+	}
+	return 0
+}
+
+func (x *VerifyAuditChainResponse) GetCheckpointsChecked() int32 {
+	if x != nil {
+		return x.CheckpointsChecked
+	}
+	return 0
+}
+
+func (x *VerifyAuditChainResponse) GetFirstBreak() *AuditChainBreak {
+	if x != nil {
+		return x.FirstBreak
+	}
+	return nil
+}
+
+// AuditCheckpoint commits to an org's chain up to chain_seq. signature is a
+// JWT with org, seq and hash claims, verifiable with the JWKS
+// (/v1/auth/.well-known/jwks.json).
+type AuditCheckpoint struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
-	ExpiresIn     int64                  `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrgId         string                 `protobuf:"bytes,2,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	ChainSeq      int64                  `protobuf:"varint,3,opt,name=chain_seq,json=chainSeq,proto3" json:"chain_seq,omitempty"`
+	Hash          string                 `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	Signature     string                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RefreshTokenResponse) Reset() {
-	*x = RefreshTokenResponse{}
-	mi := &file_user_proto_msgTypes[63]
+func (x *AuditCheckpoint) Reset() {
+	*x = AuditCheckpoint{}
+	mi := &file_user_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RefreshTokenResponse) String() string {
+func (x *AuditCheckpoint) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RefreshTokenResponse) ProtoMessage() {}
+func (*AuditCheckpoint) ProtoMessage() {}
 
-func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[63]
+func (x *AuditCheckpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4056,54 +5978,77 @@ func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RefreshTokenResponse.ProtoReflect.Descriptor instead.
-func (*RefreshTokenResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{63}
+// Deprecated: Use AuditCheckpoint.ProtoReflect.Descriptor instead.
+func (*AuditCheckpoint) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{90}
 }
 
-func (x *RefreshTokenResponse) GetAccessToken() string {
+func (x *AuditCheckpoint) GetId() string {
 	if x != nil {
-		return x.AccessToken
+		return x.Id
 	}
 	return ""
 }
 
-func (x *RefreshTokenResponse) GetRefreshToken() string {
+func (x *AuditCheckpoint) GetOrgId() string {
 	if x != nil {
-		return x.RefreshToken
+		return x.OrgId
 	}
 	return ""
 }
 
-func (x *RefreshTokenResponse) GetExpiresIn() int64 {
+func (x *AuditCheckpoint) GetChainSeq() int64 {
 	if x != nil {
-		return x.ExpiresIn
+		return x.ChainSeq
 	}
 	return 0
 }
 
-type LogoutRequest struct {
+func (x *AuditCheckpoint) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *AuditCheckpoint) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *AuditCheckpoint) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListAuditCheckpointsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LogoutRequest) Reset() {
-	*x = LogoutRequest{}
-	mi := &file_user_proto_msgTypes[64]
+func (x *ListAuditCheckpointsRequest) Reset() {
+	*x = ListAuditCheckpointsRequest{}
+	mi := &file_user_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LogoutRequest) String() string {
+func (x *ListAuditCheckpointsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LogoutRequest) ProtoMessage() {}
+func (*ListAuditCheckpointsRequest) ProtoMessage() {}
 
-func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[64]
+func (x *ListAuditCheckpointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4114,40 +6059,54 @@ func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
-func (*LogoutRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{64}
+// Deprecated: Use ListAuditCheckpointsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditCheckpointsRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *LogoutRequest) GetRefreshToken() string {
+func (x *ListAuditCheckpointsRequest) GetOrgId() string {
 	if x != nil {
-		return x.RefreshToken
+		return x.OrgId
 	}
 	return ""
 }
 
-type JWKSResponse struct {
+func (x *ListAuditCheckpointsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListAuditCheckpointsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type ListAuditCheckpointsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	KeysJson      string                 `protobuf:"bytes,1,opt,name=keys_json,json=keysJson,proto3" json:"keys_json,omitempty"`
+	Checkpoints   []*AuditCheckpoint     `protobuf:"bytes,1,rep,name=checkpoints,proto3" json:"checkpoints,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JWKSResponse) Reset() {
-	*x = JWKSResponse{}
-	mi := &file_user_proto_msgTypes[65]
+func (x *ListAuditCheckpointsResponse) Reset() {
+	*x = ListAuditCheckpointsResponse{}
+	mi := &file_user_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JWKSResponse) String() string {
+func (x *ListAuditCheckpointsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JWKSResponse) ProtoMessage() {}
+func (*ListAuditCheckpointsResponse) ProtoMessage() {}
 
-func (x *JWKSResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[65]
+func (x *ListAuditCheckpointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4158,49 +6117,50 @@ func (x *JWKSResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JWKSResponse.ProtoReflect.Descriptor instead.
-func (*JWKSResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{65}
-}
-
-func (x *JWKSResponse) GetKeysJson() string {
+// Deprecated: Use ListAuditCheckpointsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditCheckpointsResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ListAuditCheckpointsResponse) GetCheckpoints() []*AuditCheckpoint {
 	if x != nil {
-		return x.KeysJson
+		return x.Checkpoints
 	}
-	return ""
+	return nil
 }
 
-type AuditEvent struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
-	ActorType     string                 `protobuf:"bytes,3,opt,name=actor_type,json=actorType,proto3" json:"actor_type,omitempty"`
-	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
-	Resource      string                 `protobuf:"bytes,5,opt,name=resource,proto3" json:"resource,omitempty"`
-	ResourceId    string                 `protobuf:"bytes,6,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
-	OrgId         string                 `protobuf:"bytes,7,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	IpAddress     string                 `protobuf:"bytes,9,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+// WatchAuditLogRequest tails an org's audit log with the QueryAuditLog
+// filters. Without resume_token, only events recorded from now on are sent.
+type WatchAuditLogRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	OrgId      string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	ActorId    string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Action     string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Resource   string                 `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`
+	ResourceId string                 `protobuf:"bytes,5,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	From       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=from,proto3" json:"from,omitempty"`
+	To         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=to,proto3" json:"to,omitempty"`
+	// From a previous WatchAuditLogResponse: resume after that event
+	ResumeToken   string `protobuf:"bytes,8,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AuditEvent) Reset() {
-	*x = AuditEvent{}
-	mi := &file_user_proto_msgTypes[66]
+func (x *WatchAuditLogRequest) Reset() {
+	*x = WatchAuditLogRequest{}
+	mi := &file_user_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AuditEvent) String() string {
+func (x *WatchAuditLogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuditEvent) ProtoMessage() {}
+func (*WatchAuditLogRequest) ProtoMessage() {}
 
-func (x *AuditEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[66]
+func (x *WatchAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4211,82 +6171,123 @@ func (x *AuditEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
-func (*AuditEvent) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{66}
+// Deprecated: Use WatchAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*WatchAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{93}
 }
 
-func (x *AuditEvent) GetId() string {
+func (x *WatchAuditLogRequest) GetOrgId() string {
 	if x != nil {
-		return x.Id
+		return x.OrgId
 	}
 	return ""
 }
 
-func (x *AuditEvent) GetActorId() string {
+func (x *WatchAuditLogRequest) GetActorId() string {
 	if x != nil {
 		return x.ActorId
 	}
 	return ""
 }
 
-func (x *AuditEvent) GetActorType() string {
-	if x != nil {
-		return x.ActorType
-	}
-	return ""
-}
-
-func (x *AuditEvent) GetAction() string {
+func (x *WatchAuditLogRequest) GetAction() string {
 	if x != nil {
 		return x.Action
 	}
 	return ""
 }
 
-func (x *AuditEvent) GetResource() string {
+func (x *WatchAuditLogRequest) GetResource() string {
 	if x != nil {
 		return x.Resource
 	}
 	return ""
 }
 
-func (x *AuditEvent) GetResourceId() string {
+func (x *WatchAuditLogRequest) GetResourceId() string {
 	if x != nil {
 		return x.ResourceId
 	}
 	return ""
 }
 
-func (x *AuditEvent) GetOrgId() string {
+func (x *WatchAuditLogRequest) GetFrom() *timestamppb.Timestamp {
 	if x != nil {
-		return x.OrgId
+		return x.From
 	}
-	return ""
+	return nil
 }
 
-func (x *AuditEvent) GetMetadata() map[string]string {
+func (x *WatchAuditLogRequest) GetTo() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Metadata
+		return x.To
 	}
 	return nil
 }
 
-func (x *AuditEvent) GetIpAddress() string {
+func (x *WatchAuditLogRequest) GetResumeToken() string {
 	if x != nil {
-		return x.IpAddress
+		return x.ResumeToken
 	}
 	return ""
 }
 
-func (x *AuditEvent) GetCreatedAt() *timestamppb.Timestamp {
+type WatchAuditLogResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Event *AuditEvent            `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	// Pass back in WatchAuditLogRequest to resume after this event
+	ResumeToken   string `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAuditLogResponse) Reset() {
+	*x = WatchAuditLogResponse{}
+	mi := &file_user_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAuditLogResponse) ProtoMessage() {}
+
+func (x *WatchAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[94]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*WatchAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *WatchAuditLogResponse) GetEvent() *AuditEvent {
+	if x != nil {
+		return x.Event
 	}
 	return nil
 }
 
-type QueryAuditLogRequest struct {
+func (x *WatchAuditLogResponse) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// ExportAuditLogRequest selects events as QueryAuditLog does, oldest first.
+// NDJSON is the default format.
+type ExportAuditLogRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
 	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
@@ -4295,27 +6296,26 @@ type QueryAuditLogRequest struct {
 	ResourceId    string                 `protobuf:"bytes,5,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
 	From          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=from,proto3" json:"from,omitempty"`
 	To            *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=to,proto3" json:"to,omitempty"`
-	PageSize      int32                  `protobuf:"varint,8,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken     string                 `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Format        AuditExportFormat      `protobuf:"varint,8,opt,name=format,proto3,enum=customers.AuditExportFormat" json:"format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *QueryAuditLogRequest) Reset() {
-	*x = QueryAuditLogRequest{}
-	mi := &file_user_proto_msgTypes[67]
+func (x *ExportAuditLogRequest) Reset() {
+	*x = ExportAuditLogRequest{}
+	mi := &file_user_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *QueryAuditLogRequest) String() string {
+func (x *ExportAuditLogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryAuditLogRequest) ProtoMessage() {}
+func (*ExportAuditLogRequest) ProtoMessage() {}
 
-func (x *QueryAuditLogRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[67]
+func (x *ExportAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4326,132 +6326,65 @@ func (x *QueryAuditLogRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryAuditLogRequest.ProtoReflect.Descriptor instead.
-func (*QueryAuditLogRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{67}
+// Deprecated: Use ExportAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*ExportAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{95}
 }
 
-func (x *QueryAuditLogRequest) GetOrgId() string {
+func (x *ExportAuditLogRequest) GetOrgId() string {
 	if x != nil {
 		return x.OrgId
 	}
 	return ""
 }
 
-func (x *QueryAuditLogRequest) GetActorId() string {
+func (x *ExportAuditLogRequest) GetActorId() string {
 	if x != nil {
 		return x.ActorId
 	}
 	return ""
 }
 
-func (x *QueryAuditLogRequest) GetAction() string {
+func (x *ExportAuditLogRequest) GetAction() string {
 	if x != nil {
 		return x.Action
 	}
 	return ""
 }
 
-func (x *QueryAuditLogRequest) GetResource() string {
+func (x *ExportAuditLogRequest) GetResource() string {
 	if x != nil {
 		return x.Resource
 	}
 	return ""
 }
 
-func (x *QueryAuditLogRequest) GetResourceId() string {
+func (x *ExportAuditLogRequest) GetResourceId() string {
 	if x != nil {
 		return x.ResourceId
 	}
 	return ""
 }
 
-func (x *QueryAuditLogRequest) GetFrom() *timestamppb.Timestamp {
+func (x *ExportAuditLogRequest) GetFrom() *timestamppb.Timestamp {
 	if x != nil {
 		return x.From
 	}
 	return nil
 }
 
-func (x *QueryAuditLogRequest) GetTo() *timestamppb.Timestamp {
+func (x *ExportAuditLogRequest) GetTo() *timestamppb.Timestamp {
 	if x != nil {
 		return x.To
 	}
 	return nil
 }
 
-func (x *QueryAuditLogRequest) GetPageSize() int32 {
-	if x != nil {
-		return x.PageSize
-	}
-	return 0
-}
-
-func (x *QueryAuditLogRequest) GetPageToken() string {
-	if x != nil {
-		return x.PageToken
-	}
-	return ""
-}
-
-type QueryAuditLogResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
-	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *QueryAuditLogResponse) Reset() {
-	*x = QueryAuditLogResponse{}
-	mi := &file_user_proto_msgTypes[68]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *QueryAuditLogResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*QueryAuditLogResponse) ProtoMessage() {}
-
-func (x *QueryAuditLogResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[68]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use QueryAuditLogResponse.ProtoReflect.Descriptor instead.
-func (*QueryAuditLogResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{68}
-}
-
-func (x *QueryAuditLogResponse) GetEvents() []*AuditEvent {
-	if x != nil {
-		return x.Events
-	}
-	return nil
-}
-
-func (x *QueryAuditLogResponse) GetNextPageToken() string {
-	if x != nil {
-		return x.NextPageToken
-	}
-	return ""
-}
-
-func (x *QueryAuditLogResponse) GetTotalCount() int32 {
+func (x *ExportAuditLogRequest) GetFormat() AuditExportFormat {
 	if x != nil {
-		return x.TotalCount
+		return x.Format
 	}
-	return 0
+	return AuditExportFormat_AUDIT_EXPORT_FORMAT_UNSPECIFIED
 }
 
 type Invitation struct {
@@ -4470,7 +6403,7 @@ type Invitation struct {
 
 func (x *Invitation) Reset() {
 	*x = Invitation{}
-	mi := &file_user_proto_msgTypes[69]
+	mi := &file_user_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4482,7 +6415,7 @@ func (x *Invitation) String() string {
 func (*Invitation) ProtoMessage() {}
 
 func (x *Invitation) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[69]
+	mi := &file_user_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4495,7 +6428,7 @@ func (x *Invitation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Invitation.ProtoReflect.Descriptor instead.
 func (*Invitation) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{69}
+	return file_user_proto_rawDescGZIP(), []int{96}
 }
 
 func (x *Invitation) GetId() string {
@@ -4565,7 +6498,7 @@ type CreateInvitationRequest struct {
 
 func (x *CreateInvitationRequest) Reset() {
 	*x = CreateInvitationRequest{}
-	mi := &file_user_proto_msgTypes[70]
+	mi := &file_user_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4577,7 +6510,7 @@ func (x *CreateInvitationRequest) String() string {
 func (*CreateInvitationRequest) ProtoMessage() {}
 
 func (x *CreateInvitationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[70]
+	mi := &file_user_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4590,7 +6523,7 @@ func (x *CreateInvitationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateInvitationRequest.ProtoReflect.Descriptor instead.
 func (*CreateInvitationRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{70}
+	return file_user_proto_rawDescGZIP(), []int{97}
 }
 
 func (x *CreateInvitationRequest) GetOrgId() string {
@@ -4624,7 +6557,7 @@ type CreateInvitationResponse struct {
 
 func (x *CreateInvitationResponse) Reset() {
 	*x = CreateInvitationResponse{}
-	mi := &file_user_proto_msgTypes[71]
+	mi := &file_user_proto_msgTypes[98]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4636,7 +6569,7 @@ func (x *CreateInvitationResponse) String() string {
 func (*CreateInvitationResponse) ProtoMessage() {}
 
 func (x *CreateInvitationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[71]
+	mi := &file_user_proto_msgTypes[98]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4649,7 +6582,7 @@ func (x *CreateInvitationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateInvitationResponse.ProtoReflect.Descriptor instead.
 func (*CreateInvitationResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{71}
+	return file_user_proto_rawDescGZIP(), []int{98}
 }
 
 func (x *CreateInvitationResponse) GetInvitation() *Invitation {
@@ -4675,7 +6608,7 @@ type AcceptInvitationRequest struct {
 
 func (x *AcceptInvitationRequest) Reset() {
 	*x = AcceptInvitationRequest{}
-	mi := &file_user_proto_msgTypes[72]
+	mi := &file_user_proto_msgTypes[99]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4687,7 +6620,7 @@ func (x *AcceptInvitationRequest) String() string {
 func (*AcceptInvitationRequest) ProtoMessage() {}
 
 func (x *AcceptInvitationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[72]
+	mi := &file_user_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4700,7 +6633,7 @@ func (x *AcceptInvitationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AcceptInvitationRequest.ProtoReflect.Descriptor instead.
 func (*AcceptInvitationRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{72}
+	return file_user_proto_rawDescGZIP(), []int{99}
 }
 
 func (x *AcceptInvitationRequest) GetToken() string {
@@ -4719,7 +6652,7 @@ type AcceptInvitationResponse struct {
 
 func (x *AcceptInvitationResponse) Reset() {
 	*x = AcceptInvitationResponse{}
-	mi := &file_user_proto_msgTypes[73]
+	mi := &file_user_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4731,7 +6664,7 @@ func (x *AcceptInvitationResponse) String() string {
 func (*AcceptInvitationResponse) ProtoMessage() {}
 
 func (x *AcceptInvitationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[73]
+	mi := &file_user_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4744,7 +6677,7 @@ func (x *AcceptInvitationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AcceptInvitationResponse.ProtoReflect.Descriptor instead.
 func (*AcceptInvitationResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{73}
+	return file_user_proto_rawDescGZIP(), []int{100}
 }
 
 func (x *AcceptInvitationResponse) GetOrganization() *Organization {
@@ -4764,7 +6697,7 @@ type ListInvitationsRequest struct {
 
 func (x *ListInvitationsRequest) Reset() {
 	*x = ListInvitationsRequest{}
-	mi := &file_user_proto_msgTypes[74]
+	mi := &file_user_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4776,7 +6709,7 @@ func (x *ListInvitationsRequest) String() string {
 func (*ListInvitationsRequest) ProtoMessage() {}
 
 func (x *ListInvitationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[74]
+	mi := &file_user_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4789,7 +6722,7 @@ func (x *ListInvitationsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListInvitationsRequest.ProtoReflect.Descriptor instead.
 func (*ListInvitationsRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{74}
+	return file_user_proto_rawDescGZIP(), []int{101}
 }
 
 func (x *ListInvitationsRequest) GetOrgId() string {
@@ -4815,7 +6748,7 @@ type ListInvitationsResponse struct {
 
 func (x *ListInvitationsResponse) Reset() {
 	*x = ListInvitationsResponse{}
-	mi := &file_user_proto_msgTypes[75]
+	mi := &file_user_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4827,7 +6760,7 @@ func (x *ListInvitationsResponse) String() string {
 func (*ListInvitationsResponse) ProtoMessage() {}
 
 func (x *ListInvitationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[75]
+	mi := &file_user_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4840,7 +6773,7 @@ func (x *ListInvitationsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListInvitationsResponse.ProtoReflect.Descriptor instead.
 func (*ListInvitationsResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{75}
+	return file_user_proto_rawDescGZIP(), []int{102}
 }
 
 func (x *ListInvitationsResponse) GetInvitations() []*Invitation {
@@ -4859,7 +6792,7 @@ type RevokeInvitationRequest struct {
 
 func (x *RevokeInvitationRequest) Reset() {
 	*x = RevokeInvitationRequest{}
-	mi := &file_user_proto_msgTypes[76]
+	mi := &file_user_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4871,7 +6804,7 @@ func (x *RevokeInvitationRequest) String() string {
 func (*RevokeInvitationRequest) ProtoMessage() {}
 
 func (x *RevokeInvitationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[76]
+	mi := &file_user_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4884,7 +6817,7 @@ func (x *RevokeInvitationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RevokeInvitationRequest.ProtoReflect.Descriptor instead.
 func (*RevokeInvitationRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{76}
+	return file_user_proto_rawDescGZIP(), []int{103}
 }
 
 func (x *RevokeInvitationRequest) GetId() string {
@@ -4905,7 +6838,7 @@ type SearchUsersRequest struct {
 
 func (x *SearchUsersRequest) Reset() {
 	*x = SearchUsersRequest{}
-	mi := &file_user_proto_msgTypes[77]
+	mi := &file_user_proto_msgTypes[104]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4917,7 +6850,7 @@ func (x *SearchUsersRequest) String() string {
 func (*SearchUsersRequest) ProtoMessage() {}
 
 func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[77]
+	mi := &file_user_proto_msgTypes[104]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4930,7 +6863,7 @@ func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
 func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{77}
+	return file_user_proto_rawDescGZIP(), []int{104}
 }
 
 func (x *SearchUsersRequest) GetQuery() string {
@@ -4965,7 +6898,7 @@ type SearchUsersResponse struct {
 
 func (x *SearchUsersResponse) Reset() {
 	*x = SearchUsersResponse{}
-	mi := &file_user_proto_msgTypes[78]
+	mi := &file_user_proto_msgTypes[105]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4977,7 +6910,7 @@ func (x *SearchUsersResponse) String() string {
 func (*SearchUsersResponse) ProtoMessage() {}
 
 func (x *SearchUsersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[78]
+	mi := &file_user_proto_msgTypes[105]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4990,7 +6923,7 @@ func (x *SearchUsersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchUsersResponse.ProtoReflect.Descriptor instead.
 func (*SearchUsersResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{78}
+	return file_user_proto_rawDescGZIP(), []int{105}
 }
 
 func (x *SearchUsersResponse) GetUsers() []*User {
@@ -5024,7 +6957,7 @@ type SuspendUserRequest struct {
 
 func (x *SuspendUserRequest) Reset() {
 	*x = SuspendUserRequest{}
-	mi := &file_user_proto_msgTypes[79]
+	mi := &file_user_proto_msgTypes[106]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5036,7 +6969,7 @@ func (x *SuspendUserRequest) String() string {
 func (*SuspendUserRequest) ProtoMessage() {}
 
 func (x *SuspendUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[79]
+	mi := &file_user_proto_msgTypes[106]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5049,7 +6982,7 @@ func (x *SuspendUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SuspendUserRequest.ProtoReflect.Descriptor instead.
 func (*SuspendUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{79}
+	return file_user_proto_rawDescGZIP(), []int{106}
 }
 
 func (x *SuspendUserRequest) GetUserId() string {
@@ -5075,7 +7008,7 @@ type UnsuspendUserRequest struct {
 
 func (x *UnsuspendUserRequest) Reset() {
 	*x = UnsuspendUserRequest{}
-	mi := &file_user_proto_msgTypes[80]
+	mi := &file_user_proto_msgTypes[107]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5087,7 +7020,7 @@ func (x *UnsuspendUserRequest) String() string {
 func (*UnsuspendUserRequest) ProtoMessage() {}
 
 func (x *UnsuspendUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[80]
+	mi := &file_user_proto_msgTypes[107]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5100,7 +7033,7 @@ func (x *UnsuspendUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnsuspendUserRequest.ProtoReflect.Descriptor instead.
 func (*UnsuspendUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{80}
+	return file_user_proto_rawDescGZIP(), []int{107}
 }
 
 func (x *UnsuspendUserRequest) GetUserId() string {
@@ -5119,7 +7052,7 @@ type ImpersonateUserRequest struct {
 
 func (x *ImpersonateUserRequest) Reset() {
 	*x = ImpersonateUserRequest{}
-	mi := &file_user_proto_msgTypes[81]
+	mi := &file_user_proto_msgTypes[108]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5131,7 +7064,7 @@ func (x *ImpersonateUserRequest) String() string {
 func (*ImpersonateUserRequest) ProtoMessage() {}
 
 func (x *ImpersonateUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[81]
+	mi := &file_user_proto_msgTypes[108]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5144,7 +7077,7 @@ func (x *ImpersonateUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImpersonateUserRequest.ProtoReflect.Descriptor instead.
 func (*ImpersonateUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{81}
+	return file_user_proto_rawDescGZIP(), []int{108}
 }
 
 func (x *ImpersonateUserRequest) GetUserId() string {
@@ -5164,7 +7097,7 @@ type ImpersonateUserResponse struct {
 
 func (x *ImpersonateUserResponse) Reset() {
 	*x = ImpersonateUserResponse{}
-	mi := &file_user_proto_msgTypes[82]
+	mi := &file_user_proto_msgTypes[109]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5176,7 +7109,7 @@ func (x *ImpersonateUserResponse) String() string {
 func (*ImpersonateUserResponse) ProtoMessage() {}
 
 func (x *ImpersonateUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[82]
+	mi := &file_user_proto_msgTypes[109]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5189,7 +7122,7 @@ func (x *ImpersonateUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImpersonateUserResponse.ProtoReflect.Descriptor instead.
 func (*ImpersonateUserResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{82}
+	return file_user_proto_rawDescGZIP(), []int{109}
 }
 
 func (x *ImpersonateUserResponse) GetAccessToken() string {
@@ -5217,7 +7150,7 @@ type ListActiveSessionsRequest struct {
 
 func (x *ListActiveSessionsRequest) Reset() {
 	*x = ListActiveSessionsRequest{}
-	mi := &file_user_proto_msgTypes[83]
+	mi := &file_user_proto_msgTypes[110]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5229,7 +7162,7 @@ func (x *ListActiveSessionsRequest) String() string {
 func (*ListActiveSessionsRequest) ProtoMessage() {}
 
 func (x *ListActiveSessionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[83]
+	mi := &file_user_proto_msgTypes[110]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5242,7 +7175,7 @@ func (x *ListActiveSessionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListActiveSessionsRequest.ProtoReflect.Descriptor instead.
 func (*ListActiveSessionsRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{83}
+	return file_user_proto_rawDescGZIP(), []int{110}
 }
 
 func (x *ListActiveSessionsRequest) GetUserId() string {
@@ -5281,7 +7214,7 @@ type SessionInfo struct {
 
 func (x *SessionInfo) Reset() {
 	*x = SessionInfo{}
-	mi := &file_user_proto_msgTypes[84]
+	mi := &file_user_proto_msgTypes[111]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5293,7 +7226,7 @@ func (x *SessionInfo) String() string {
 func (*SessionInfo) ProtoMessage() {}
 
 func (x *SessionInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[84]
+	mi := &file_user_proto_msgTypes[111]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5306,7 +7239,7 @@ func (x *SessionInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionInfo.ProtoReflect.Descriptor instead.
 func (*SessionInfo) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{84}
+	return file_user_proto_rawDescGZIP(), []int{111}
 }
 
 func (x *SessionInfo) GetId() string {
@@ -5368,7 +7301,7 @@ type ListActiveSessionsResponse struct {
 
 func (x *ListActiveSessionsResponse) Reset() {
 	*x = ListActiveSessionsResponse{}
-	mi := &file_user_proto_msgTypes[85]
+	mi := &file_user_proto_msgTypes[112]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5380,7 +7313,7 @@ func (x *ListActiveSessionsResponse) String() string {
 func (*ListActiveSessionsResponse) ProtoMessage() {}
 
 func (x *ListActiveSessionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[85]
+	mi := &file_user_proto_msgTypes[112]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5393,7 +7326,7 @@ func (x *ListActiveSessionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListActiveSessionsResponse.ProtoReflect.Descriptor instead.
 func (*ListActiveSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{85}
+	return file_user_proto_rawDescGZIP(), []int{112}
 }
 
 func (x *ListActiveSessionsResponse) GetSessions() []*SessionInfo {
@@ -5419,7 +7352,7 @@ type GetOrgEntitlementsRequest struct {
 
 func (x *GetOrgEntitlementsRequest) Reset() {
 	*x = GetOrgEntitlementsRequest{}
-	mi := &file_user_proto_msgTypes[86]
+	mi := &file_user_proto_msgTypes[113]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5431,7 +7364,7 @@ func (x *GetOrgEntitlementsRequest) String() string {
 func (*GetOrgEntitlementsRequest) ProtoMessage() {}
 
 func (x *GetOrgEntitlementsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[86]
+	mi := &file_user_proto_msgTypes[113]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5444,7 +7377,7 @@ func (x *GetOrgEntitlementsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrgEntitlementsRequest.ProtoReflect.Descriptor instead.
 func (*GetOrgEntitlementsRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{86}
+	return file_user_proto_rawDescGZIP(), []int{113}
 }
 
 func (x *GetOrgEntitlementsRequest) GetOrgId() string {
@@ -5464,7 +7397,7 @@ type GetOrgEntitlementsResponse struct {
 
 func (x *GetOrgEntitlementsResponse) Reset() {
 	*x = GetOrgEntitlementsResponse{}
-	mi := &file_user_proto_msgTypes[87]
+	mi := &file_user_proto_msgTypes[114]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5476,7 +7409,7 @@ func (x *GetOrgEntitlementsResponse) String() string {
 func (*GetOrgEntitlementsResponse) ProtoMessage() {}
 
 func (x *GetOrgEntitlementsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[87]
+	mi := &file_user_proto_msgTypes[114]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5489,7 +7422,7 @@ func (x *GetOrgEntitlementsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrgEntitlementsResponse.ProtoReflect.Descriptor instead.
 func (*GetOrgEntitlementsResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{87}
+	return file_user_proto_rawDescGZIP(), []int{114}
 }
 
 func (x *GetOrgEntitlementsResponse) GetPlanName() string {
@@ -5518,7 +7451,7 @@ type EntitlementInfo struct {
 
 func (x *EntitlementInfo) Reset() {
 	*x = EntitlementInfo{}
-	mi := &file_user_proto_msgTypes[88]
+	mi := &file_user_proto_msgTypes[115]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5530,7 +7463,7 @@ func (x *EntitlementInfo) String() string {
 func (*EntitlementInfo) ProtoMessage() {}
 
 func (x *EntitlementInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[88]
+	mi := &file_user_proto_msgTypes[115]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5543,7 +7476,7 @@ func (x *EntitlementInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EntitlementInfo.ProtoReflect.Descriptor instead.
 func (*EntitlementInfo) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{88}
+	return file_user_proto_rawDescGZIP(), []int{115}
 }
 
 func (x *EntitlementInfo) GetFeature() string {
@@ -5586,7 +7519,7 @@ type OverrideEntitlementRequest struct {
 
 func (x *OverrideEntitlementRequest) Reset() {
 	*x = OverrideEntitlementRequest{}
-	mi := &file_user_proto_msgTypes[89]
+	mi := &file_user_proto_msgTypes[116]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5598,7 +7531,7 @@ func (x *OverrideEntitlementRequest) String() string {
 func (*OverrideEntitlementRequest) ProtoMessage() {}
 
 func (x *OverrideEntitlementRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[89]
+	mi := &file_user_proto_msgTypes[116]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5611,7 +7544,7 @@ func (x *OverrideEntitlementRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OverrideEntitlementRequest.ProtoReflect.Descriptor instead.
 func (*OverrideEntitlementRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{89}
+	return file_user_proto_rawDescGZIP(), []int{116}
 }
 
 func (x *OverrideEntitlementRequest) GetOrgId() string {
@@ -5651,7 +7584,7 @@ type OverrideEntitlementResponse struct {
 
 func (x *OverrideEntitlementResponse) Reset() {
 	*x = OverrideEntitlementResponse{}
-	mi := &file_user_proto_msgTypes[90]
+	mi := &file_user_proto_msgTypes[117]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5663,7 +7596,7 @@ func (x *OverrideEntitlementResponse) String() string {
 func (*OverrideEntitlementResponse) ProtoMessage() {}
 
 func (x *OverrideEntitlementResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[90]
+	mi := &file_user_proto_msgTypes[117]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5676,7 +7609,7 @@ func (x *OverrideEntitlementResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OverrideEntitlementResponse.ProtoReflect.Descriptor instead.
 func (*OverrideEntitlementResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{90}
+	return file_user_proto_rawDescGZIP(), []int{117}
 }
 
 func (x *OverrideEntitlementResponse) GetId() string {
@@ -5691,10 +7624,21 @@ var File_user_proto protoreflect.FileDescriptor
 const file_user_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"user.proto\x12\tcustomers\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1bbuf/validate/validate.proto\"\x10\n" +
+	"user.proto\x12\tcustomers\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/httpbody.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1bbuf/validate/validate.proto\"\x10\n" +
 	"\x0eVersionRequest\"4\n" +
 	"\x0fVersionResponse\x12!\n" +
-	"\aversion\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\aversion\"\xcd\x03\n" +
+	"\aversion\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\aversion\"\x0f\n" +
+	"\rHealthRequest\"\xac\x02\n" +
+	"\x0eHealthResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12C\n" +
+	"\bdegraded\x18\x02 \x03(\v2'.customers.HealthResponse.DegradedEntryR\bdegraded\x12C\n" +
+	"\bcounters\x18\x03 \x03(\v2'.customers.HealthResponse.CountersEntryR\bcounters\x1a;\n" +
+	"\rDegradedEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a;\n" +
+	"\rCountersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xcd\x03\n" +
 	"\x04User\x12\x1c\n" +
 	"\x04uuid\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x04uuid\x12,\n" +
 	"\rprimary_email\x18\x02 \x01(\tB\a\xbaH\x04r\x02`\x01R\fprimaryEmail\x129\n" +
@@ -5913,7 +7857,7 @@ const file_user_proto_rawDesc = "" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x15\n" +
 	"\x06org_id\x18\x02 \x01(\tR\x05orgId\x12\x14\n" +
 	"\x05roles\x18\x03 \x03(\tR\x05roles\x12\x14\n" +
-	"\x05found\x18\x04 \x01(\bR\x05found\"\xe4\x03\n" +
+	"\x05found\x18\x04 \x01(\bR\x05found\"\xd9\x06\n" +
 	"\x06APIKey\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
 	"\x0forganization_id\x18\x02 \x01(\tR\x0eorganizationId\x12\x17\n" +
@@ -5930,7 +7874,21 @@ const file_user_proto_rawDesc = "" +
 	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
 	"lastUsedAt\x129\n" +
 	"\n" +
-	"revoked_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\"\x92\x02\n" +
+	"revoked_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\x12&\n" +
+	"\x0frotated_from_id\x18\f \x01(\tR\rrotatedFromId\x127\n" +
+	"\trevoke_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\brevokeAt\x12;\n" +
+	"\vdisabled_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"disabledAt\x12'\n" +
+	"\x0fdisabled_reason\x18\x0f \x01(\tR\x0edisabledReason\x12 \n" +
+	"\flast_used_ip\x18\x10 \x01(\tR\n" +
+	"lastUsedIp\x12<\n" +
+	"\vdaily_usage\x18\x11 \x03(\v2\x1b.customers.APIKeyDailyUsageR\n" +
+	"dailyUsage\x12#\n" +
+	"\rallowed_cidrs\x18\x12 \x03(\tR\fallowedCidrs\x12'\n" +
+	"\x0fallowed_origins\x18\x13 \x03(\tR\x0eallowedOrigins\"B\n" +
+	"\x10APIKeyDailyUsage\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x1a\n" +
+	"\brequests\x18\x02 \x01(\x03R\brequests\"\xf4\x02\n" +
 	"\x13CreateAPIKeyRequest\x121\n" +
 	"\x0forganization_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x0eorganizationId\x12\x1e\n" +
 	"\x04name\x18\x02 \x01(\tB\n" +
@@ -5938,27 +7896,96 @@ const file_user_proto_rawDesc = "" +
 	"\x06scopes\x18\x03 \x03(\v2\x15.customers.PermissionR\x06scopes\x12>\n" +
 	"\venvironment\x18\x04 \x01(\x0e2\x1c.customers.APIKeyEnvironmentR\venvironment\x129\n" +
 	"\n" +
-	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"`\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12-\n" +
+	"\rallowed_cidrs\x18\x06 \x03(\tB\b\xbaH\x05\x92\x01\x02\x10dR\fallowedCidrs\x121\n" +
+	"\x0fallowed_origins\x18\a \x03(\tB\b\xbaH\x05\x92\x01\x02\x10dR\x0eallowedOrigins\"`\n" +
 	"\x14CreateAPIKeyResponse\x12#\n" +
 	"\x03key\x18\x01 \x01(\v2\x11.customers.APIKeyR\x03key\x12#\n" +
-	"\rplaintext_key\x18\x02 \x01(\tR\fplaintextKey\"\x8e\x01\n" +
+	"\rplaintext_key\x18\x02 \x01(\tR\fplaintextKey\"\xf7\x01\n" +
 	"\x12ListAPIKeysRequest\x121\n" +
 	"\x0forganization_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x0eorganizationId\x12&\n" +
 	"\tpage_size\x18\x02 \x01(\x05B\t\xbaH\x06\x1a\x04\x18d \x00R\bpageSize\x12\x1d\n" +
 	"\n" +
-	"page_token\x18\x03 \x01(\tR\tpageToken\"d\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12(\n" +
+	"\n" +
+	"usage_days\x18\x04 \x01(\x05B\t\xbaH\x06\x1a\x04\x18Z(\x00R\tusageDays\x12=\n" +
+	"\funused_since\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vunusedSince\"d\n" +
 	"\x13ListAPIKeysResponse\x12%\n" +
 	"\x04keys\x18\x01 \x03(\v2\x11.customers.APIKeyR\x04keys\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"/\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x91\x01\n" +
+	"\x13UpdateAPIKeyRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12#\n" +
+	"\x03key\x18\x02 \x01(\v2\x11.customers.APIKeyR\x03key\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"/\n" +
 	"\x13RevokeAPIKeyRequest\x12\x18\n" +
-	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\";\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"o\n" +
+	"\x14RevokeAPIKeysRequest\x121\n" +
+	"\x0forganization_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x0eorganizationId\x12$\n" +
+	"\x03ids\x18\x02 \x03(\tB\x12\xbaH\x0f\x92\x01\f\b\x01\x10\xe8\a\"\x05r\x03\xb0\x01\x01R\x03ids\"8\n" +
+	"\x15RevokeAPIKeysResponse\x12\x1f\n" +
+	"\vrevoked_ids\x18\x01 \x03(\tR\n" +
+	"revokedIds\"\xaa\x01\n" +
+	"\x13RotateAPIKeyRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12>\n" +
+	"\x14grace_period_seconds\x18\x02 \x01(\x03B\f\xbaH\t\"\a\x18\x80\x9a\x9e\x01(\x00R\x12gracePeriodSeconds\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\x96\x01\n" +
+	"\x14RotateAPIKeyResponse\x12#\n" +
+	"\x03key\x18\x01 \x01(\v2\x11.customers.APIKeyR\x03key\x12#\n" +
+	"\rplaintext_key\x18\x02 \x01(\tR\fplaintextKey\x124\n" +
+	"\fprevious_key\x18\x03 \x01(\v2\x11.customers.APIKeyR\vpreviousKey\"M\n" +
+	"\x18ReevaluateAPIKeysRequest\x121\n" +
+	"\x0forganization_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x0eorganizationId\"\xb7\x01\n" +
+	"\x19ReevaluateAPIKeysResponse\x12\x18\n" +
+	"\achecked\x18\x01 \x01(\x05R\achecked\x12-\n" +
+	"\bdisabled\x18\x02 \x03(\v2\x11.customers.APIKeyR\bdisabled\x12+\n" +
+	"\aenabled\x18\x03 \x03(\v2\x11.customers.APIKeyR\aenabled\x12$\n" +
+	"\x0efailed_key_ids\x18\x04 \x03(\tR\ffailedKeyIds\"S\n" +
 	"\x15ValidateAPIKeyRequest\x12\"\n" +
-	"\bkey_hash\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\akeyHash\"\x88\x01\n" +
+	"\bkey_hash\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\akeyHash\x12\x16\n" +
+	"\x06origin\x18\x02 \x01(\tR\x06origin\"\x98\x03\n" +
 	"\x16ValidateAPIKeyResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12'\n" +
 	"\x0forganization_id\x18\x03 \x01(\tR\x0eorganizationId\x12\x16\n" +
-	"\x06scopes\x18\x04 \x03(\tR\x06scopes\"\xed\x02\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12\x15\n" +
+	"\x06key_id\x18\x05 \x01(\tR\x05keyId\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\x06 \x01(\bR\n" +
+	"deprecated\x127\n" +
+	"\trevoke_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\brevokeAt\x12\x18\n" +
+	"\awarning\x18\b \x01(\tR\awarning\x126\n" +
+	"\x06reason\x18\t \x01(\x0e2\x1e.customers.APIKeyInvalidReasonR\x06reason\x12#\n" +
+	"\rallowed_cidrs\x18\n" +
+	" \x03(\tR\fallowedCidrs\x12'\n" +
+	"\x0fallowed_origins\x18\v \x03(\tR\x0eallowedOrigins\"h\n" +
+	"\x12LeakedAPIKeyReport\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\"b\n" +
+	"\x1aReportLeakedAPIKeysRequest\x12D\n" +
+	"\areports\x18\x01 \x03(\v2\x1d.customers.LeakedAPIKeyReportB\v\xbaH\b\x92\x01\x05\b\x01\x10\xe8\aR\areports\"O\n" +
+	"\x12LeakedAPIKeyResult\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rtrue_positive\x18\x02 \x01(\bR\ftruePositive\"V\n" +
+	"\x1bReportLeakedAPIKeysResponse\x127\n" +
+	"\aresults\x18\x01 \x03(\v2\x1d.customers.LeakedAPIKeyResultR\aresults\"7\n" +
+	"\x14GetRateLimitsRequest\x12\x1f\n" +
+	"\x06org_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x05orgId\"\xe2\x01\n" +
+	"\x15GetRateLimitsResponse\x12*\n" +
+	"\x11api_calls_monthly\x18\x01 \x01(\x03R\x0fapiCallsMonthly\x12$\n" +
+	"\x0eapi_calls_used\x18\x02 \x01(\x03R\fapiCallsUsed\x12<\n" +
+	"\x1bapi_key_requests_per_minute\x18\x03 \x01(\x03R\x17apiKeyRequestsPerMinute\x129\n" +
+	"\n" +
+	"period_end\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tperiodEnd\"v\n" +
+	"\vUsageRecord\x12\x1f\n" +
+	"\x06org_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x05orgId\x12!\n" +
+	"\afeature\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\afeature\x12#\n" +
+	"\bquantity\x18\x03 \x01(\x03B\a\xbaH\x04\"\x02 \x00R\bquantity\"S\n" +
+	"\x12RecordUsageRequest\x12=\n" +
+	"\arecords\x18\x01 \x03(\v2\x16.customers.UsageRecordB\v\xbaH\b\x92\x01\x05\b\x01\x10\xe8\aR\arecords\"\xed\x02\n" +
 	"\x13AuthenticateRequest\x127\n" +
 	"\bprovider\x18\x01 \x01(\tB\x1b\xbaH\x18r\x16\x10\x01\x1822\x10^[a-zA-Z0-9_-]+$R\bprovider\x12+\n" +
 	"\vprovider_id\x18\x02 \x01(\tB\n" +
@@ -5984,11 +8011,12 @@ const file_user_proto_rawDesc = "" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
 	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1d\n" +
 	"\n" +
-	"expires_in\x18\x03 \x01(\x03R\texpiresIn\"=\n" +
+	"expires_in\x18\x03 \x01(\x03R\texpiresIn\"`\n" +
 	"\rLogoutRequest\x12,\n" +
-	"\rrefresh_token\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\frefreshToken\"+\n" +
+	"\rrefresh_token\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\frefreshToken\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"+\n" +
 	"\fJWKSResponse\x12\x1b\n" +
-	"\tkeys_json\x18\x01 \x01(\tR\bkeysJson\"\x9a\x03\n" +
+	"\tkeys_json\x18\x01 \x01(\tR\bkeysJson\"\xe8\x03\n" +
 	"\n" +
 	"AuditEvent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
@@ -6005,7 +8033,10 @@ const file_user_proto_rawDesc = "" +
 	"ip_address\x18\t \x01(\tR\tipAddress\x129\n" +
 	"\n" +
 	"created_at\x18\n" +
-	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x1a;\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1b\n" +
+	"\tchain_seq\x18\v \x01(\x03R\bchainSeq\x12\x1b\n" +
+	"\tprev_hash\x18\f \x01(\tR\bprevHash\x12\x12\n" +
+	"\x04hash\x18\r \x01(\tR\x04hash\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc0\x02\n" +
@@ -6025,7 +8056,58 @@ const file_user_proto_rawDesc = "" +
 	"\x06events\x18\x01 \x03(\v2\x15.customers.AuditEventR\x06events\x12&\n" +
 	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1f\n" +
 	"\vtotal_count\x18\x03 \x01(\x05R\n" +
-	"totalCount\"\xa7\x02\n" +
+	"totalCount\"\x8c\x01\n" +
+	"\x17VerifyAuditChainRequest\x12\x15\n" +
+	"\x06org_id\x18\x01 \x01(\tR\x05orgId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"a\n" +
+	"\x0fAuditChainBreak\x12\x1b\n" +
+	"\tchain_seq\x18\x01 \x01(\x03R\bchainSeq\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\tR\aeventId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xb8\x01\n" +
+	"\x18VerifyAuditChainResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x18\n" +
+	"\achecked\x18\x02 \x01(\x03R\achecked\x12/\n" +
+	"\x13checkpoints_checked\x18\x03 \x01(\x05R\x12checkpointsChecked\x12;\n" +
+	"\vfirst_break\x18\x04 \x01(\v2\x1a.customers.AuditChainBreakR\n" +
+	"firstBreak\"\xc2\x01\n" +
+	"\x0fAuditCheckpoint\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06org_id\x18\x02 \x01(\tR\x05orgId\x12\x1b\n" +
+	"\tchain_seq\x18\x03 \x01(\x03R\bchainSeq\x12\x12\n" +
+	"\x04hash\x18\x04 \x01(\tR\x04hash\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\tR\tsignature\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x90\x01\n" +
+	"\x1bListAuditCheckpointsRequest\x12\x15\n" +
+	"\x06org_id\x18\x01 \x01(\tR\x05orgId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"\\\n" +
+	"\x1cListAuditCheckpointsResponse\x12<\n" +
+	"\vcheckpoints\x18\x01 \x03(\v2\x1a.customers.AuditCheckpointR\vcheckpoints\"\xa6\x02\n" +
+	"\x14WatchAuditLogRequest\x12\x1f\n" +
+	"\x06org_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x05orgId\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1a\n" +
+	"\bresource\x18\x04 \x01(\tR\bresource\x12\x1f\n" +
+	"\vresource_id\x18\x05 \x01(\tR\n" +
+	"resourceId\x12.\n" +
+	"\x04from\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12!\n" +
+	"\fresume_token\x18\b \x01(\tR\vresumeToken\"g\n" +
+	"\x15WatchAuditLogResponse\x12+\n" +
+	"\x05event\x18\x01 \x01(\v2\x15.customers.AuditEventR\x05event\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\"\xba\x02\n" +
+	"\x15ExportAuditLogRequest\x12\x1f\n" +
+	"\x06org_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x05orgId\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1a\n" +
+	"\bresource\x18\x04 \x01(\tR\bresource\x12\x1f\n" +
+	"\vresource_id\x18\x05 \x01(\tR\n" +
+	"resourceId\x12.\n" +
+	"\x04from\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x124\n" +
+	"\x06format\x18\b \x01(\x0e2\x1c.customers.AuditExportFormatR\x06format\"\xa7\x02\n" +
 	"\n" +
 	"Invitation\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
@@ -6145,15 +8227,30 @@ const file_user_proto_rawDesc = "" +
 	"\x11APIKeyEnvironment\x12#\n" +
 	"\x1fAPI_KEY_ENVIRONMENT_UNSPECIFIED\x10\x00\x12\x1c\n" +
 	"\x18API_KEY_ENVIRONMENT_LIVE\x10\x01\x12\x1c\n" +
-	"\x18API_KEY_ENVIRONMENT_TEST\x10\x02*\xb2\x01\n" +
+	"\x18API_KEY_ENVIRONMENT_TEST\x10\x02*\xd0\x02\n" +
+	"\x13APIKeyInvalidReason\x12&\n" +
+	"\"API_KEY_INVALID_REASON_UNSPECIFIED\x10\x00\x12$\n" +
+	" API_KEY_INVALID_REASON_NOT_FOUND\x10\x01\x12\"\n" +
+	"\x1eAPI_KEY_INVALID_REASON_REVOKED\x10\x02\x12\"\n" +
+	"\x1eAPI_KEY_INVALID_REASON_EXPIRED\x10\x03\x12#\n" +
+	"\x1fAPI_KEY_INVALID_REASON_DISABLED\x10\x04\x12)\n" +
+	"%API_KEY_INVALID_REASON_IP_NOT_ALLOWED\x10\x05\x12-\n" +
+	")API_KEY_INVALID_REASON_ORIGIN_NOT_ALLOWED\x10\x06\x12$\n" +
+	" API_KEY_INVALID_REASON_MALFORMED\x10\a*u\n" +
+	"\x11AuditExportFormat\x12#\n" +
+	"\x1fAUDIT_EXPORT_FORMAT_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aAUDIT_EXPORT_FORMAT_NDJSON\x10\x01\x12\x1b\n" +
+	"\x17AUDIT_EXPORT_FORMAT_CSV\x10\x02*\xb2\x01\n" +
 	"\x10InvitationStatus\x12!\n" +
 	"\x1dINVITATION_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
 	"\x19INVITATION_STATUS_PENDING\x10\x01\x12\x1e\n" +
 	"\x1aINVITATION_STATUS_ACCEPTED\x10\x02\x12\x1d\n" +
 	"\x19INVITATION_STATUS_REVOKED\x10\x03\x12\x1d\n" +
-	"\x19INVITATION_STATUS_EXPIRED\x10\x042\x96\b\n" +
+	"\x19INVITATION_STATUS_EXPIRED\x10\x042\xe9\b\n" +
 	"\vUserService\x12U\n" +
-	"\aVersion\x12\x19.customers.VersionRequest\x1a\x1a.customers.VersionResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/version\x12X\n" +
+	"\aVersion\x12\x19.customers.VersionRequest\x1a\x1a.customers.VersionResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/version\x12Q\n" +
+	"\x06Health\x12\x18.customers.HealthRequest\x1a\x19.customers.HealthResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/v1/health\x12X\n" +
 	"\aGetSelf\x12\x19.customers.GetSelfRequest\x1a\x1a.customers.GetSelfResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/users/self\x12e\n" +
 	"\fRegisterUser\x12\x1e.customers.RegisterUserRequest\x1a\x1f.customers.RegisterUserResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/users\x12d\n" +
 	"\aGetUser\x12\x19.customers.GetUserRequest\x1a\x0f.customers.User\"-\x82\xd3\xe4\x93\x02'Z\x13\x12\x11/v1/users:byEmail\x12\x10/v1/users/{uuid}\x12Y\n" +
@@ -6191,19 +8288,31 @@ const file_user_proto_rawDesc = "" +
 	"RevokeRole\x12\x1c.customers.RevokeRoleRequest\x1a\x16.google.protobuf.Empty\"\x1c\x82\xd3\xe4\x93\x02\x16*\x14/v1/role-assignments\x12z\n" +
 	"\x0fCheckPermission\x12!.customers.CheckPermissionRequest\x1a\".customers.CheckPermissionResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/permissions:check2\x8c\x01\n" +
 	"\x0fIdentityService\x12y\n" +
-	"\x0fResolveIdentity\x12!.customers.ResolveIdentityRequest\x1a\".customers.ResolveIdentityResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/identity:resolve2\x97\x03\n" +
+	"\x0fResolveIdentity\x12!.customers.ResolveIdentityRequest\x1a\".customers.ResolveIdentityResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/identity:resolve2\xd4\a\n" +
 	"\rAPIKeyService\x12h\n" +
 	"\fCreateAPIKey\x12\x1e.customers.CreateAPIKeyRequest\x1a\x1f.customers.CreateAPIKeyResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/api-keys\x12b\n" +
 	"\vListAPIKeys\x12\x1d.customers.ListAPIKeysRequest\x1a\x1e.customers.ListAPIKeysResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/api-keys\x12a\n" +
-	"\fRevokeAPIKey\x12\x1e.customers.RevokeAPIKeyRequest\x1a\x16.google.protobuf.Empty\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/api-keys/{id}\x12U\n" +
-	"\x0eValidateAPIKey\x12 .customers.ValidateAPIKeyRequest\x1a!.customers.ValidateAPIKeyResponse2\xaa\x03\n" +
+	"\fUpdateAPIKey\x12\x1e.customers.UpdateAPIKeyRequest\x1a\x11.customers.APIKey\"\x1e\x82\xd3\xe4\x93\x02\x18:\x03key2\x11/v1/api-keys/{id}\x12a\n" +
+	"\fRevokeAPIKey\x12\x1e.customers.RevokeAPIKeyRequest\x1a\x16.google.protobuf.Empty\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/api-keys/{id}\x12w\n" +
+	"\rRevokeAPIKeys\x12\x1f.customers.RevokeAPIKeysRequest\x1a .customers.RevokeAPIKeysResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/api-keys:batchRevoke\x12t\n" +
+	"\fRotateAPIKey\x12\x1e.customers.RotateAPIKeyRequest\x1a\x1f.customers.RotateAPIKeyResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/api-keys/{id}:rotate\x12\x82\x01\n" +
+	"\x11ReevaluateAPIKeys\x12#.customers.ReevaluateAPIKeysRequest\x1a$.customers.ReevaluateAPIKeysResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/api-keys:reevaluate\x12U\n" +
+	"\x0eValidateAPIKey\x12 .customers.ValidateAPIKeyRequest\x1a!.customers.ValidateAPIKeyResponse\x12d\n" +
+	"\x13ReportLeakedAPIKeys\x12%.customers.ReportLeakedAPIKeysRequest\x1a&.customers.ReportLeakedAPIKeysResponse2\xab\x01\n" +
+	"\x0fMeteringService\x12R\n" +
+	"\rGetRateLimits\x12\x1f.customers.GetRateLimitsRequest\x1a .customers.GetRateLimitsResponse\x12D\n" +
+	"\vRecordUsage\x12\x1d.customers.RecordUsageRequest\x1a\x16.google.protobuf.Empty2\xaa\x03\n" +
 	"\vAuthService\x12q\n" +
 	"\fAuthenticate\x12\x1e.customers.AuthenticateRequest\x1a\x1f.customers.AuthenticateResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/auth/authenticate\x12l\n" +
 	"\fRefreshToken\x12\x1e.customers.RefreshTokenRequest\x1a\x1f.customers.RefreshTokenResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/auth/refresh\x12V\n" +
 	"\x06Logout\x12\x18.customers.LogoutRequest\x1a\x16.google.protobuf.Empty\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/auth/logout\x12b\n" +
-	"\aGetJWKS\x12\x16.google.protobuf.Empty\x1a\x17.customers.JWKSResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/v1/auth/.well-known/jwks.json2y\n" +
+	"\aGetJWKS\x12\x16.google.protobuf.Empty\x1a\x17.customers.JWKSResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/v1/auth/.well-known/jwks.json2\xde\x04\n" +
 	"\fAuditService\x12i\n" +
-	"\rQueryAuditLog\x12\x1f.customers.QueryAuditLogRequest\x1a .customers.QueryAuditLogResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/audit-log2\xab\a\n" +
+	"\rQueryAuditLog\x12\x1f.customers.QueryAuditLogRequest\x1a .customers.QueryAuditLogResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/audit-log\x12y\n" +
+	"\x10VerifyAuditChain\x12\".customers.VerifyAuditChainRequest\x1a#.customers.VerifyAuditChainResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/audit-log/verify\x12\x8a\x01\n" +
+	"\x14ListAuditCheckpoints\x12&.customers.ListAuditCheckpointsRequest\x1a'.customers.ListAuditCheckpointsResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/v1/audit-log/checkpoints\x12q\n" +
+	"\rWatchAuditLog\x12\x1f.customers.WatchAuditLogRequest\x1a .customers.WatchAuditLogResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/audit-log/watch0\x01\x12h\n" +
+	"\x0eExportAuditLog\x12 .customers.ExportAuditLogRequest\x1a\x14.google.api.HttpBody\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/audit-log/export0\x012\xab\a\n" +
 	"\fAdminService\x12e\n" +
 	"\vSearchUsers\x12\x1d.customers.SearchUsersRequest\x1a\x1e.customers.SearchUsersResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/admin/users\x12r\n" +
 	"\vSuspendUser\x12\x1d.customers.SuspendUserRequest\x1a\x16.google.protobuf.Empty\",\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/admin/users/{user_id}:suspend\x12x\n" +
@@ -6231,295 +8340,383 @@ func file_user_proto_rawDescGZIP() []byte {
 	return file_user_proto_rawDescData
 }
 
-var file_user_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 97)
+var file_user_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 126)
 var file_user_proto_goTypes = []any{
-	(UserStatus)(0),                     // 0: customers.UserStatus
-	(OrgRole)(0),                        // 1: customers.OrgRole
-	(TeamRole)(0),                       // 2: customers.TeamRole
-	(SubjectKind)(0),                    // 3: customers.SubjectKind
-	(APIKeyEnvironment)(0),              // 4: customers.APIKeyEnvironment
-	(InvitationStatus)(0),               // 5: customers.InvitationStatus
-	(*VersionRequest)(nil),              // 6: customers.VersionRequest
-	(*VersionResponse)(nil),             // 7: customers.VersionResponse
-	(*User)(nil),                        // 8: customers.User
-	(*UserIdentity)(nil),                // 9: customers.UserIdentity
-	(*Organization)(nil),                // 10: customers.Organization
-	(*OrgMembership)(nil),               // 11: customers.OrgMembership
-	(*Team)(nil),                        // 12: customers.Team
-	(*TeamMembership)(nil),              // 13: customers.TeamMembership
-	(*Permission)(nil),                  // 14: customers.Permission
-	(*Role)(nil),                        // 15: customers.Role
-	(*RoleAssignment)(nil),              // 16: customers.RoleAssignment
-	(*RegisterUserRequest)(nil),         // 17: customers.RegisterUserRequest
-	(*RegisterUserResponse)(nil),        // 18: customers.RegisterUserResponse
-	(*GetUserRequest)(nil),              // 19: customers.GetUserRequest
-	(*GetSelfRequest)(nil),              // 20: customers.GetSelfRequest
-	(*GetSelfResponse)(nil),             // 21: customers.GetSelfResponse
-	(*ListUsersRequest)(nil),            // 22: customers.ListUsersRequest
-	(*ListUsersResponse)(nil),           // 23: customers.ListUsersResponse
-	(*UpdateUserRequest)(nil),           // 24: customers.UpdateUserRequest
-	(*AddIdentityRequest)(nil),          // 25: customers.AddIdentityRequest
-	(*FindUserByIdentityRequest)(nil),   // 26: customers.FindUserByIdentityRequest
-	(*ListUserIdentitiesRequest)(nil),   // 27: customers.ListUserIdentitiesRequest
-	(*ListUserIdentitiesResponse)(nil),  // 28: customers.ListUserIdentitiesResponse
-	(*CreateOrganizationRequest)(nil),   // 29: customers.CreateOrganizationRequest
-	(*CreateOrganizationResponse)(nil),  // 30: customers.CreateOrganizationResponse
-	(*GetOrganizationRequest)(nil),      // 31: customers.GetOrganizationRequest
-	(*ListOrganizationsRequest)(nil),    // 32: customers.ListOrganizationsRequest
-	(*ListOrganizationsResponse)(nil),   // 33: customers.ListOrganizationsResponse
-	(*AddOrgMemberRequest)(nil),         // 34: customers.AddOrgMemberRequest
-	(*RemoveOrgMemberRequest)(nil),      // 35: customers.RemoveOrgMemberRequest
-	(*ListOrgMembersRequest)(nil),       // 36: customers.ListOrgMembersRequest
-	(*ListOrgMembersResponse)(nil),      // 37: customers.ListOrgMembersResponse
-	(*CreateTeamRequest)(nil),           // 38: customers.CreateTeamRequest
-	(*CreateTeamResponse)(nil),          // 39: customers.CreateTeamResponse
-	(*ListTeamsRequest)(nil),            // 40: customers.ListTeamsRequest
-	(*ListTeamsResponse)(nil),           // 41: customers.ListTeamsResponse
-	(*AddTeamMemberRequest)(nil),        // 42: customers.AddTeamMemberRequest
-	(*RemoveTeamMemberRequest)(nil),     // 43: customers.RemoveTeamMemberRequest
-	(*ListTeamMembersRequest)(nil),      // 44: customers.ListTeamMembersRequest
-	(*ListTeamMembersResponse)(nil),     // 45: customers.ListTeamMembersResponse
-	(*CreateRoleRequest)(nil),           // 46: customers.CreateRoleRequest
-	(*CreateRoleResponse)(nil),          // 47: customers.CreateRoleResponse
-	(*ListRolesRequest)(nil),            // 48: customers.ListRolesRequest
-	(*ListRolesResponse)(nil),           // 49: customers.ListRolesResponse
-	(*DeleteRoleRequest)(nil),           // 50: customers.DeleteRoleRequest
-	(*AssignRoleRequest)(nil),           // 51: customers.AssignRoleRequest
-	(*AssignRoleResponse)(nil),          // 52: customers.AssignRoleResponse
-	(*RevokeRoleRequest)(nil),           // 53: customers.RevokeRoleRequest
-	(*CheckPermissionRequest)(nil),      // 54: customers.CheckPermissionRequest
-	(*CheckPermissionResponse)(nil),     // 55: customers.CheckPermissionResponse
-	(*ResolveIdentityRequest)(nil),      // 56: customers.ResolveIdentityRequest
-	(*ResolveIdentityResponse)(nil),     // 57: customers.ResolveIdentityResponse
-	(*APIKey)(nil),                      // 58: customers.APIKey
-	(*CreateAPIKeyRequest)(nil),         // 59: customers.CreateAPIKeyRequest
-	(*CreateAPIKeyResponse)(nil),        // 60: customers.CreateAPIKeyResponse
-	(*ListAPIKeysRequest)(nil),          // 61: customers.ListAPIKeysRequest
-	(*ListAPIKeysResponse)(nil),         // 62: customers.ListAPIKeysResponse
-	(*RevokeAPIKeyRequest)(nil),         // 63: customers.RevokeAPIKeyRequest
-	(*ValidateAPIKeyRequest)(nil),       // 64: customers.ValidateAPIKeyRequest
-	(*ValidateAPIKeyResponse)(nil),      // 65: customers.ValidateAPIKeyResponse
-	(*AuthenticateRequest)(nil),         // 66: customers.AuthenticateRequest
-	(*AuthenticateResponse)(nil),        // 67: customers.AuthenticateResponse
-	(*RefreshTokenRequest)(nil),         // 68: customers.RefreshTokenRequest
-	(*RefreshTokenResponse)(nil),        // 69: customers.RefreshTokenResponse
-	(*LogoutRequest)(nil),               // 70: customers.LogoutRequest
-	(*JWKSResponse)(nil),                // 71: customers.JWKSResponse
-	(*AuditEvent)(nil),                  // 72: customers.AuditEvent
-	(*QueryAuditLogRequest)(nil),        // 73: customers.QueryAuditLogRequest
-	(*QueryAuditLogResponse)(nil),       // 74: customers.QueryAuditLogResponse
-	(*Invitation)(nil),                  // 75: customers.Invitation
-	(*CreateInvitationRequest)(nil),     // 76: customers.CreateInvitationRequest
-	(*CreateInvitationResponse)(nil),    // 77: customers.CreateInvitationResponse
-	(*AcceptInvitationRequest)(nil),     // 78: customers.AcceptInvitationRequest
-	(*AcceptInvitationResponse)(nil),    // 79: customers.AcceptInvitationResponse
-	(*ListInvitationsRequest)(nil),      // 80: customers.ListInvitationsRequest
-	(*ListInvitationsResponse)(nil),     // 81: customers.ListInvitationsResponse
-	(*RevokeInvitationRequest)(nil),     // 82: customers.RevokeInvitationRequest
-	(*SearchUsersRequest)(nil),          // 83: customers.SearchUsersRequest
-	(*SearchUsersResponse)(nil),         // 84: customers.SearchUsersResponse
-	(*SuspendUserRequest)(nil),          // 85: customers.SuspendUserRequest
-	(*UnsuspendUserRequest)(nil),        // 86: customers.UnsuspendUserRequest
-	(*ImpersonateUserRequest)(nil),      // 87: customers.ImpersonateUserRequest
-	(*ImpersonateUserResponse)(nil),     // 88: customers.ImpersonateUserResponse
-	(*ListActiveSessionsRequest)(nil),   // 89: customers.ListActiveSessionsRequest
-	(*SessionInfo)(nil),                 // 90: customers.SessionInfo
-	(*ListActiveSessionsResponse)(nil),  // 91: customers.ListActiveSessionsResponse
-	(*GetOrgEntitlementsRequest)(nil),   // 92: customers.GetOrgEntitlementsRequest
-	(*GetOrgEntitlementsResponse)(nil),  // 93: customers.GetOrgEntitlementsResponse
-	(*EntitlementInfo)(nil),             // 94: customers.EntitlementInfo
-	(*OverrideEntitlementRequest)(nil),  // 95: customers.OverrideEntitlementRequest
-	(*OverrideEntitlementResponse)(nil), // 96: customers.OverrideEntitlementResponse
-	nil,                                 // 97: customers.User.ProfileEntry
-	nil,                                 // 98: customers.UserIdentity.ProviderDataEntry
-	nil,                                 // 99: customers.RegisterUserRequest.ProfileEntry
-	nil,                                 // 100: customers.AuthenticateRequest.ProfileEntry
-	nil,                                 // 101: customers.AuditEvent.MetadataEntry
-	nil,                                 // 102: customers.SessionInfo.DeviceInfoEntry
-	(*timestamppb.Timestamp)(nil),       // 103: google.protobuf.Timestamp
-	(*fieldmaskpb.FieldMask)(nil),       // 104: google.protobuf.FieldMask
-	(*emptypb.Empty)(nil),               // 105: google.protobuf.Empty
+	(UserStatus)(0),                      // 0: customers.UserStatus
+	(OrgRole)(0),                         // 1: customers.OrgRole
+	(TeamRole)(0),                        // 2: customers.TeamRole
+	(SubjectKind)(0),                     // 3: customers.SubjectKind
+	(APIKeyEnvironment)(0),               // 4: customers.APIKeyEnvironment
+	(APIKeyInvalidReason)(0),             // 5: customers.APIKeyInvalidReason
+	(AuditExportFormat)(0),               // 6: customers.AuditExportFormat
+	(InvitationStatus)(0),                // 7: customers.InvitationStatus
+	(*VersionRequest)(nil),               // 8: customers.VersionRequest
+	(*VersionResponse)(nil),              // 9: customers.VersionResponse
+	(*HealthRequest)(nil),                // 10: customers.HealthRequest
+	(*HealthResponse)(nil),               // 11: customers.HealthResponse
+	(*User)(nil),                         // 12: customers.User
+	(*UserIdentity)(nil),                 // 13: customers.UserIdentity
+	(*Organization)(nil),                 // 14: customers.Organization
+	(*OrgMembership)(nil),                // 15: customers.OrgMembership
+	(*Team)(nil),                         // 16: customers.Team
+	(*TeamMembership)(nil),               // 17: customers.TeamMembership
+	(*Permission)(nil),                   // 18: customers.Permission
+	(*Role)(nil),                         // 19: customers.Role
+	(*RoleAssignment)(nil),               // 20: customers.RoleAssignment
+	(*RegisterUserRequest)(nil),          // 21: customers.RegisterUserRequest
+	(*RegisterUserResponse)(nil),         // 22: customers.RegisterUserResponse
+	(*GetUserRequest)(nil),               // 23: customers.GetUserRequest
+	(*GetSelfRequest)(nil),               // 24: customers.GetSelfRequest
+	(*GetSelfResponse)(nil),              // 25: customers.GetSelfResponse
+	(*ListUsersRequest)(nil),             // 26: customers.ListUsersRequest
+	(*ListUsersResponse)(nil),            // 27: customers.ListUsersResponse
+	(*UpdateUserRequest)(nil),            // 28: customers.UpdateUserRequest
+	(*AddIdentityRequest)(nil),           // 29: customers.AddIdentityRequest
+	(*FindUserByIdentityRequest)(nil),    // 30: customers.FindUserByIdentityRequest
+	(*ListUserIdentitiesRequest)(nil),    // 31: customers.ListUserIdentitiesRequest
+	(*ListUserIdentitiesResponse)(nil),   // 32: customers.ListUserIdentitiesResponse
+	(*CreateOrganizationRequest)(nil),    // 33: customers.CreateOrganizationRequest
+	(*CreateOrganizationResponse)(nil),   // 34: customers.CreateOrganizationResponse
+	(*GetOrganizationRequest)(nil),       // 35: customers.GetOrganizationRequest
+	(*ListOrganizationsRequest)(nil),     // 36: customers.ListOrganizationsRequest
+	(*ListOrganizationsResponse)(nil),    // 37: customers.ListOrganizationsResponse
+	(*AddOrgMemberRequest)(nil),          // 38: customers.AddOrgMemberRequest
+	(*RemoveOrgMemberRequest)(nil),       // 39: customers.RemoveOrgMemberRequest
+	(*ListOrgMembersRequest)(nil),        // 40: customers.ListOrgMembersRequest
+	(*ListOrgMembersResponse)(nil),       // 41: customers.ListOrgMembersResponse
+	(*CreateTeamRequest)(nil),            // 42: customers.CreateTeamRequest
+	(*CreateTeamResponse)(nil),           // 43: customers.CreateTeamResponse
+	(*ListTeamsRequest)(nil),             // 44: customers.ListTeamsRequest
+	(*ListTeamsResponse)(nil),            // 45: customers.ListTeamsResponse
+	(*AddTeamMemberRequest)(nil),         // 46: customers.AddTeamMemberRequest
+	(*RemoveTeamMemberRequest)(nil),      // 47: customers.RemoveTeamMemberRequest
+	(*ListTeamMembersRequest)(nil),       // 48: customers.ListTeamMembersRequest
+	(*ListTeamMembersResponse)(nil),      // 49: customers.ListTeamMembersResponse
+	(*CreateRoleRequest)(nil),            // 50: customers.CreateRoleRequest
+	(*CreateRoleResponse)(nil),           // 51: customers.CreateRoleResponse
+	(*ListRolesRequest)(nil),             // 52: customers.ListRolesRequest
+	(*ListRolesResponse)(nil),            // 53: customers.ListRolesResponse
+	(*DeleteRoleRequest)(nil),            // 54: customers.DeleteRoleRequest
+	(*AssignRoleRequest)(nil),            // 55: customers.AssignRoleRequest
+	(*AssignRoleResponse)(nil),           // 56: customers.AssignRoleResponse
+	(*RevokeRoleRequest)(nil),            // 57: customers.RevokeRoleRequest
+	(*CheckPermissionRequest)(nil),       // 58: customers.CheckPermissionRequest
+	(*CheckPermissionResponse)(nil),      // 59: customers.CheckPermissionResponse
+	(*ResolveIdentityRequest)(nil),       // 60: customers.ResolveIdentityRequest
+	(*ResolveIdentityResponse)(nil),      // 61: customers.ResolveIdentityResponse
+	(*APIKey)(nil),                       // 62: customers.APIKey
+	(*APIKeyDailyUsage)(nil),             // 63: customers.APIKeyDailyUsage
+	(*CreateAPIKeyRequest)(nil),          // 64: customers.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),         // 65: customers.CreateAPIKeyResponse
+	(*ListAPIKeysRequest)(nil),           // 66: customers.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),          // 67: customers.ListAPIKeysResponse
+	(*UpdateAPIKeyRequest)(nil),          // 68: customers.UpdateAPIKeyRequest
+	(*RevokeAPIKeyRequest)(nil),          // 69: customers.RevokeAPIKeyRequest
+	(*RevokeAPIKeysRequest)(nil),         // 70: customers.RevokeAPIKeysRequest
+	(*RevokeAPIKeysResponse)(nil),        // 71: customers.RevokeAPIKeysResponse
+	(*RotateAPIKeyRequest)(nil),          // 72: customers.RotateAPIKeyRequest
+	(*RotateAPIKeyResponse)(nil),         // 73: customers.RotateAPIKeyResponse
+	(*ReevaluateAPIKeysRequest)(nil),     // 74: customers.ReevaluateAPIKeysRequest
+	(*ReevaluateAPIKeysResponse)(nil),    // 75: customers.ReevaluateAPIKeysResponse
+	(*ValidateAPIKeyRequest)(nil),        // 76: customers.ValidateAPIKeyRequest
+	(*ValidateAPIKeyResponse)(nil),       // 77: customers.ValidateAPIKeyResponse
+	(*LeakedAPIKeyReport)(nil),           // 78: customers.LeakedAPIKeyReport
+	(*ReportLeakedAPIKeysRequest)(nil),   // 79: customers.ReportLeakedAPIKeysRequest
+	(*LeakedAPIKeyResult)(nil),           // 80: customers.LeakedAPIKeyResult
+	(*ReportLeakedAPIKeysResponse)(nil),  // 81: customers.ReportLeakedAPIKeysResponse
+	(*GetRateLimitsRequest)(nil),         // 82: customers.GetRateLimitsRequest
+	(*GetRateLimitsResponse)(nil),        // 83: customers.GetRateLimitsResponse
+	(*UsageRecord)(nil),                  // 84: customers.UsageRecord
+	(*RecordUsageRequest)(nil),           // 85: customers.RecordUsageRequest
+	(*AuthenticateRequest)(nil),          // 86: customers.AuthenticateRequest
+	(*AuthenticateResponse)(nil),         // 87: customers.AuthenticateResponse
+	(*RefreshTokenRequest)(nil),          // 88: customers.RefreshTokenRequest
+	(*RefreshTokenResponse)(nil),         // 89: customers.RefreshTokenResponse
+	(*LogoutRequest)(nil),                // 90: customers.LogoutRequest
+	(*JWKSResponse)(nil),                 // 91: customers.JWKSResponse
+	(*AuditEvent)(nil),                   // 92: customers.AuditEvent
+	(*QueryAuditLogRequest)(nil),         // 93: customers.QueryAuditLogRequest
+	(*QueryAuditLogResponse)(nil),        // 94: customers.QueryAuditLogResponse
+	(*VerifyAuditChainRequest)(nil),      // 95: customers.VerifyAuditChainRequest
+	(*AuditChainBreak)(nil),              // 96: customers.AuditChainBreak
+	(*VerifyAuditChainResponse)(nil),     // 97: customers.VerifyAuditChainResponse
+	(*AuditCheckpoint)(nil),              // 98: customers.AuditCheckpoint
+	(*ListAuditCheckpointsRequest)(nil),  // 99: customers.ListAuditCheckpointsRequest
+	(*ListAuditCheckpointsResponse)(nil), // 100: customers.ListAuditCheckpointsResponse
+	(*WatchAuditLogRequest)(nil),         // 101: customers.WatchAuditLogRequest
+	(*WatchAuditLogResponse)(nil),        // 102: customers.WatchAuditLogResponse
+	(*ExportAuditLogRequest)(nil),        // 103: customers.ExportAuditLogRequest
+	(*Invitation)(nil),                   // 104: customers.Invitation
+	(*CreateInvitationRequest)(nil),      // 105: customers.CreateInvitationRequest
+	(*CreateInvitationResponse)(nil),     // 106: customers.CreateInvitationResponse
+	(*AcceptInvitationRequest)(nil),      // 107: customers.AcceptInvitationRequest
+	(*AcceptInvitationResponse)(nil),     // 108: customers.AcceptInvitationResponse
+	(*ListInvitationsRequest)(nil),       // 109: customers.ListInvitationsRequest
+	(*ListInvitationsResponse)(nil),      // 110: customers.ListInvitationsResponse
+	(*RevokeInvitationRequest)(nil),      // 111: customers.RevokeInvitationRequest
+	(*SearchUsersRequest)(nil),           // 112: customers.SearchUsersRequest
+	(*SearchUsersResponse)(nil),          // 113: customers.SearchUsersResponse
+	(*SuspendUserRequest)(nil),           // 114: customers.SuspendUserRequest
+	(*UnsuspendUserRequest)(nil),         // 115: customers.UnsuspendUserRequest
+	(*ImpersonateUserRequest)(nil),       // 116: customers.ImpersonateUserRequest
+	(*ImpersonateUserResponse)(nil),      // 117: customers.ImpersonateUserResponse
+	(*ListActiveSessionsRequest)(nil),    // 118: customers.ListActiveSessionsRequest
+	(*SessionInfo)(nil),                  // 119: customers.SessionInfo
+	(*ListActiveSessionsResponse)(nil),   // 120: customers.ListActiveSessionsResponse
+	(*GetOrgEntitlementsRequest)(nil),    // 121: customers.GetOrgEntitlementsRequest
+	(*GetOrgEntitlementsResponse)(nil),   // 122: customers.GetOrgEntitlementsResponse
+	(*EntitlementInfo)(nil),              // 123: customers.EntitlementInfo
+	(*OverrideEntitlementRequest)(nil),   // 124: customers.OverrideEntitlementRequest
+	(*OverrideEntitlementResponse)(nil),  // 125: customers.OverrideEntitlementResponse
+	nil,                                  // 126: customers.HealthResponse.DegradedEntry
+	nil,                                  // 127: customers.HealthResponse.CountersEntry
+	nil,                                  // 128: customers.User.ProfileEntry
+	nil,                                  // 129: customers.UserIdentity.ProviderDataEntry
+	nil,                                  // 130: customers.RegisterUserRequest.ProfileEntry
+	nil,                                  // 131: customers.AuthenticateRequest.ProfileEntry
+	nil,                                  // 132: customers.AuditEvent.MetadataEntry
+	nil,                                  // 133: customers.SessionInfo.DeviceInfoEntry
+	(*timestamppb.Timestamp)(nil),        // 134: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),        // 135: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),                // 136: google.protobuf.Empty
+	(*httpbody.HttpBody)(nil),            // 137: google.api.HttpBody
 }
 var file_user_proto_depIdxs = []int32{
-	103, // 0: customers.User.created_at:type_name -> google.protobuf.Timestamp
-	103, // 1: customers.User.updated_at:type_name -> google.protobuf.Timestamp
-	103, // 2: customers.User.last_login:type_name -> google.protobuf.Timestamp
-	0,   // 3: customers.User.status:type_name -> customers.UserStatus
-	97,  // 4: customers.User.profile:type_name -> customers.User.ProfileEntry
-	103, // 5: customers.UserIdentity.created_at:type_name -> google.protobuf.Timestamp
-	103, // 6: customers.UserIdentity.last_used:type_name -> google.protobuf.Timestamp
-	98,  // 7: customers.UserIdentity.provider_data:type_name -> customers.UserIdentity.ProviderDataEntry
-	103, // 8: customers.Organization.created_at:type_name -> google.protobuf.Timestamp
-	1,   // 9: customers.OrgMembership.role:type_name -> customers.OrgRole
-	103, // 10: customers.OrgMembership.joined_at:type_name -> google.protobuf.Timestamp
-	103, // 11: customers.Team.created_at:type_name -> google.protobuf.Timestamp
-	2,   // 12: customers.TeamMembership.role:type_name -> customers.TeamRole
-	103, // 13: customers.TeamMembership.joined_at:type_name -> google.protobuf.Timestamp
-	14,  // 14: customers.Role.permissions:type_name -> customers.Permission
-	3,   // 15: customers.RoleAssignment.subject_kind:type_name -> customers.SubjectKind
-	103, // 16: customers.RoleAssignment.assigned_at:type_name -> google.protobuf.Timestamp
-	99,  // 17: customers.RegisterUserRequest.profile:type_name -> customers.RegisterUserRequest.ProfileEntry
-	9,   // 18: customers.RegisterUserRequest.identity:type_name -> customers.UserIdentity
-	8,   // 19: customers.RegisterUserResponse.user:type_name -> customers.User
-	9,   // 20: customers.RegisterUserResponse.identity:type_name -> customers.UserIdentity
-	8,   // 21: customers.GetSelfResponse.user:type_name -> customers.User
-	9,   // 22: customers.GetSelfResponse.identities:type_name -> customers.UserIdentity
-	10,  // 23: customers.GetSelfResponse.organizations:type_name -> customers.Organization
-	16,  // 24: customers.GetSelfResponse.role_assignments:type_name -> customers.RoleAssignment
-	0,   // 25: customers.ListUsersRequest.status:type_name -> customers.UserStatus
-	8,   // 26: customers.ListUsersResponse.users:type_name -> customers.User
-	8,   // 27: customers.UpdateUserRequest.user:type_name -> customers.User
-	104, // 28: customers.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
-	9,   // 29: customers.AddIdentityRequest.identity:type_name -> customers.UserIdentity
-	9,   // 30: customers.ListUserIdentitiesResponse.identities:type_name -> customers.UserIdentity
-	10,  // 31: customers.CreateOrganizationResponse.organization:type_name -> customers.Organization
-	10,  // 32: customers.ListOrganizationsResponse.organizations:type_name -> customers.Organization
-	1,   // 33: customers.AddOrgMemberRequest.role:type_name -> customers.OrgRole
-	11,  // 34: customers.ListOrgMembersResponse.members:type_name -> customers.OrgMembership
-	12,  // 35: customers.CreateTeamResponse.team:type_name -> customers.Team
-	12,  // 36: customers.ListTeamsResponse.teams:type_name -> customers.Team
-	2,   // 37: customers.AddTeamMemberRequest.role:type_name -> customers.TeamRole
-	13,  // 38: customers.ListTeamMembersResponse.members:type_name -> customers.TeamMembership
-	14,  // 39: customers.CreateRoleRequest.permissions:type_name -> customers.Permission
-	15,  // 40: customers.CreateRoleResponse.role:type_name -> customers.Role
-	15,  // 41: customers.ListRolesResponse.roles:type_name -> customers.Role
-	3,   // 42: customers.AssignRoleRequest.subject_kind:type_name -> customers.SubjectKind
-	16,  // 43: customers.AssignRoleResponse.assignment:type_name -> customers.RoleAssignment
-	3,   // 44: customers.CheckPermissionRequest.subject_kind:type_name -> customers.SubjectKind
-	14,  // 45: customers.APIKey.scopes:type_name -> customers.Permission
-	4,   // 46: customers.APIKey.environment:type_name -> customers.APIKeyEnvironment
-	103, // 47: customers.APIKey.created_at:type_name -> google.protobuf.Timestamp
-	103, // 48: customers.APIKey.expires_at:type_name -> google.protobuf.Timestamp
-	103, // 49: customers.APIKey.last_used_at:type_name -> google.protobuf.Timestamp
-	103, // 50: customers.APIKey.revoked_at:type_name -> google.protobuf.Timestamp
-	14,  // 51: customers.CreateAPIKeyRequest.scopes:type_name -> customers.Permission
-	4,   // 52: customers.CreateAPIKeyRequest.environment:type_name -> customers.APIKeyEnvironment
-	103, // 53: customers.CreateAPIKeyRequest.expires_at:type_name -> google.protobuf.Timestamp
-	58,  // 54: customers.CreateAPIKeyResponse.key:type_name -> customers.APIKey
-	58,  // 55: customers.ListAPIKeysResponse.keys:type_name -> customers.APIKey
-	100, // 56: customers.AuthenticateRequest.profile:type_name -> customers.AuthenticateRequest.ProfileEntry
-	8,   // 57: customers.AuthenticateResponse.user:type_name -> customers.User
-	101, // 58: customers.AuditEvent.metadata:type_name -> customers.AuditEvent.MetadataEntry
-	103, // 59: customers.AuditEvent.created_at:type_name -> google.protobuf.Timestamp
-	103, // 60: customers.QueryAuditLogRequest.from:type_name -> google.protobuf.Timestamp
-	103, // 61: customers.QueryAuditLogRequest.to:type_name -> google.protobuf.Timestamp
-	72,  // 62: customers.QueryAuditLogResponse.events:type_name -> customers.AuditEvent
-	5,   // 63: customers.Invitation.status:type_name -> customers.InvitationStatus
-	103, // 64: customers.Invitation.expires_at:type_name -> google.protobuf.Timestamp
-	103, // 65: customers.Invitation.created_at:type_name -> google.protobuf.Timestamp
-	75,  // 66: customers.CreateInvitationResponse.invitation:type_name -> customers.Invitation
-	10,  // 67: customers.AcceptInvitationResponse.organization:type_name -> customers.Organization
-	5,   // 68: customers.ListInvitationsRequest.status:type_name -> customers.InvitationStatus
-	75,  // 69: customers.ListInvitationsResponse.invitations:type_name -> customers.Invitation
-	8,   // 70: customers.SearchUsersResponse.users:type_name -> customers.User
-	102, // 71: customers.SessionInfo.device_info:type_name -> customers.SessionInfo.DeviceInfoEntry
-	103, // 72: customers.SessionInfo.created_at:type_name -> google.protobuf.Timestamp
-	103, // 73: customers.SessionInfo.last_active_at:type_name -> google.protobuf.Timestamp
-	103, // 74: customers.SessionInfo.expires_at:type_name -> google.protobuf.Timestamp
-	90,  // 75: customers.ListActiveSessionsResponse.sessions:type_name -> customers.SessionInfo
-	94,  // 76: customers.GetOrgEntitlementsResponse.entitlements:type_name -> customers.EntitlementInfo
-	6,   // 77: customers.UserService.Version:input_type -> customers.VersionRequest
-	20,  // 78: customers.UserService.GetSelf:input_type -> customers.GetSelfRequest
-	17,  // 79: customers.UserService.RegisterUser:input_type -> customers.RegisterUserRequest
-	19,  // 80: customers.UserService.GetUser:input_type -> customers.GetUserRequest
-	22,  // 81: customers.UserService.ListUsers:input_type -> customers.ListUsersRequest
-	24,  // 82: customers.UserService.UpdateUser:input_type -> customers.UpdateUserRequest
-	19,  // 83: customers.UserService.DeleteUser:input_type -> customers.GetUserRequest
-	25,  // 84: customers.UserService.AddIdentity:input_type -> customers.AddIdentityRequest
-	26,  // 85: customers.UserService.FindUserByIdentity:input_type -> customers.FindUserByIdentityRequest
-	27,  // 86: customers.UserService.ListUserIdentities:input_type -> customers.ListUserIdentitiesRequest
-	29,  // 87: customers.OrganizationService.CreateOrganization:input_type -> customers.CreateOrganizationRequest
-	31,  // 88: customers.OrganizationService.GetOrganization:input_type -> customers.GetOrganizationRequest
-	32,  // 89: customers.OrganizationService.ListOrganizations:input_type -> customers.ListOrganizationsRequest
-	34,  // 90: customers.OrganizationService.AddMember:input_type -> customers.AddOrgMemberRequest
-	35,  // 91: customers.OrganizationService.RemoveMember:input_type -> customers.RemoveOrgMemberRequest
-	36,  // 92: customers.OrganizationService.ListMembers:input_type -> customers.ListOrgMembersRequest
-	38,  // 93: customers.TeamService.CreateTeam:input_type -> customers.CreateTeamRequest
-	40,  // 94: customers.TeamService.ListTeams:input_type -> customers.ListTeamsRequest
-	42,  // 95: customers.TeamService.AddMember:input_type -> customers.AddTeamMemberRequest
-	43,  // 96: customers.TeamService.RemoveMember:input_type -> customers.RemoveTeamMemberRequest
-	44,  // 97: customers.TeamService.ListMembers:input_type -> customers.ListTeamMembersRequest
-	46,  // 98: customers.PermissionService.CreateRole:input_type -> customers.CreateRoleRequest
-	48,  // 99: customers.PermissionService.ListRoles:input_type -> customers.ListRolesRequest
-	50,  // 100: customers.PermissionService.DeleteRole:input_type -> customers.DeleteRoleRequest
-	51,  // 101: customers.PermissionService.AssignRole:input_type -> customers.AssignRoleRequest
-	53,  // 102: customers.PermissionService.RevokeRole:input_type -> customers.RevokeRoleRequest
-	54,  // 103: customers.PermissionService.CheckPermission:input_type -> customers.CheckPermissionRequest
-	56,  // 104: customers.IdentityService.ResolveIdentity:input_type -> customers.ResolveIdentityRequest
-	59,  // 105: customers.APIKeyService.CreateAPIKey:input_type -> customers.CreateAPIKeyRequest
-	61,  // 106: customers.APIKeyService.ListAPIKeys:input_type -> customers.ListAPIKeysRequest
-	63,  // 107: customers.APIKeyService.RevokeAPIKey:input_type -> customers.RevokeAPIKeyRequest
-	64,  // 108: customers.APIKeyService.ValidateAPIKey:input_type -> customers.ValidateAPIKeyRequest
-	66,  // 109: customers.AuthService.Authenticate:input_type -> customers.AuthenticateRequest
-	68,  // 110: customers.AuthService.RefreshToken:input_type -> customers.RefreshTokenRequest
-	70,  // 111: customers.AuthService.Logout:input_type -> customers.LogoutRequest
-	105, // 112: customers.AuthService.GetJWKS:input_type -> google.protobuf.Empty
-	73,  // 113: customers.AuditService.QueryAuditLog:input_type -> customers.QueryAuditLogRequest
-	83,  // 114: customers.AdminService.SearchUsers:input_type -> customers.SearchUsersRequest
-	85,  // 115: customers.AdminService.SuspendUser:input_type -> customers.SuspendUserRequest
-	86,  // 116: customers.AdminService.UnsuspendUser:input_type -> customers.UnsuspendUserRequest
-	87,  // 117: customers.AdminService.ImpersonateUser:input_type -> customers.ImpersonateUserRequest
-	89,  // 118: customers.AdminService.ListActiveSessions:input_type -> customers.ListActiveSessionsRequest
-	92,  // 119: customers.AdminService.GetOrgEntitlements:input_type -> customers.GetOrgEntitlementsRequest
-	95,  // 120: customers.AdminService.OverrideEntitlement:input_type -> customers.OverrideEntitlementRequest
-	76,  // 121: customers.InvitationService.CreateInvitation:input_type -> customers.CreateInvitationRequest
-	78,  // 122: customers.InvitationService.AcceptInvitation:input_type -> customers.AcceptInvitationRequest
-	80,  // 123: customers.InvitationService.ListInvitations:input_type -> customers.ListInvitationsRequest
-	82,  // 124: customers.InvitationService.RevokeInvitation:input_type -> customers.RevokeInvitationRequest
-	7,   // 125: customers.UserService.Version:output_type -> customers.VersionResponse
-	21,  // 126: customers.UserService.GetSelf:output_type -> customers.GetSelfResponse
-	18,  // 127: customers.UserService.RegisterUser:output_type -> customers.RegisterUserResponse
-	8,   // 128: customers.UserService.GetUser:output_type -> customers.User
-	23,  // 129: customers.UserService.ListUsers:output_type -> customers.ListUsersResponse
-	8,   // 130: customers.UserService.UpdateUser:output_type -> customers.User
-	105, // 131: customers.UserService.DeleteUser:output_type -> google.protobuf.Empty
-	9,   // 132: customers.UserService.AddIdentity:output_type -> customers.UserIdentity
-	8,   // 133: customers.UserService.FindUserByIdentity:output_type -> customers.User
-	28,  // 134: customers.UserService.ListUserIdentities:output_type -> customers.ListUserIdentitiesResponse
-	30,  // 135: customers.OrganizationService.CreateOrganization:output_type -> customers.CreateOrganizationResponse
-	10,  // 136: customers.OrganizationService.GetOrganization:output_type -> customers.Organization
-	33,  // 137: customers.OrganizationService.ListOrganizations:output_type -> customers.ListOrganizationsResponse
-	105, // 138: customers.OrganizationService.AddMember:output_type -> google.protobuf.Empty
-	105, // 139: customers.OrganizationService.RemoveMember:output_type -> google.protobuf.Empty
-	37,  // 140: customers.OrganizationService.ListMembers:output_type -> customers.ListOrgMembersResponse
-	39,  // 141: customers.TeamService.CreateTeam:output_type -> customers.CreateTeamResponse
-	41,  // 142: customers.TeamService.ListTeams:output_type -> customers.ListTeamsResponse
-	105, // 143: customers.TeamService.AddMember:output_type -> google.protobuf.Empty
-	105, // 144: customers.TeamService.RemoveMember:output_type -> google.protobuf.Empty
-	45,  // 145: customers.TeamService.ListMembers:output_type -> customers.ListTeamMembersResponse
-	47,  // 146: customers.PermissionService.CreateRole:output_type -> customers.CreateRoleResponse
-	49,  // 147: customers.PermissionService.ListRoles:output_type -> customers.ListRolesResponse
-	105, // 148: customers.PermissionService.DeleteRole:output_type -> google.protobuf.Empty
-	52,  // 149: customers.PermissionService.AssignRole:output_type -> customers.AssignRoleResponse
-	105, // 150: customers.PermissionService.RevokeRole:output_type -> google.protobuf.Empty
-	55,  // 151: customers.PermissionService.CheckPermission:output_type -> customers.CheckPermissionResponse
-	57,  // 152: customers.IdentityService.ResolveIdentity:output_type -> customers.ResolveIdentityResponse
-	60,  // 153: customers.APIKeyService.CreateAPIKey:output_type -> customers.CreateAPIKeyResponse
-	62,  // 154: customers.APIKeyService.ListAPIKeys:output_type -> customers.ListAPIKeysResponse
-	105, // 155: customers.APIKeyService.RevokeAPIKey:output_type -> google.protobuf.Empty
-	65,  // 156: customers.APIKeyService.ValidateAPIKey:output_type -> customers.ValidateAPIKeyResponse
-	67,  // 157: customers.AuthService.Authenticate:output_type -> customers.AuthenticateResponse
-	69,  // 158: customers.AuthService.RefreshToken:output_type -> customers.RefreshTokenResponse
-	105, // 159: customers.AuthService.Logout:output_type -> google.protobuf.Empty
-	71,  // 160: customers.AuthService.GetJWKS:output_type -> customers.JWKSResponse
-	74,  // 161: customers.AuditService.QueryAuditLog:output_type -> customers.QueryAuditLogResponse
-	84,  // 162: customers.AdminService.SearchUsers:output_type -> customers.SearchUsersResponse
-	105, // 163: customers.AdminService.SuspendUser:output_type -> google.protobuf.Empty
-	105, // 164: customers.AdminService.UnsuspendUser:output_type -> google.protobuf.Empty
-	88,  // 165: customers.AdminService.ImpersonateUser:output_type -> customers.ImpersonateUserResponse
-	91,  // 166: customers.AdminService.ListActiveSessions:output_type -> customers.ListActiveSessionsResponse
-	93,  // 167: customers.AdminService.GetOrgEntitlements:output_type -> customers.GetOrgEntitlementsResponse
-	96,  // 168: customers.AdminService.OverrideEntitlement:output_type -> customers.OverrideEntitlementResponse
-	77,  // 169: customers.InvitationService.CreateInvitation:output_type -> customers.CreateInvitationResponse
-	79,  // 170: customers.InvitationService.AcceptInvitation:output_type -> customers.AcceptInvitationResponse
-	81,  // 171: customers.InvitationService.ListInvitations:output_type -> customers.ListInvitationsResponse
-	105, // 172: customers.InvitationService.RevokeInvitation:output_type -> google.protobuf.Empty
-	125, // [125:173] is the sub-list for method output_type
-	77,  // [77:125] is the sub-list for method input_type
-	77,  // [77:77] is the sub-list for extension type_name
-	77,  // [77:77] is the sub-list for extension extendee
-	0,   // [0:77] is the sub-list for field type_name
+	126, // 0: customers.HealthResponse.degraded:type_name -> customers.HealthResponse.DegradedEntry
+	127, // 1: customers.HealthResponse.counters:type_name -> customers.HealthResponse.CountersEntry
+	134, // 2: customers.User.created_at:type_name -> google.protobuf.Timestamp
+	134, // 3: customers.User.updated_at:type_name -> google.protobuf.Timestamp
+	134, // 4: customers.User.last_login:type_name -> google.protobuf.Timestamp
+	0,   // 5: customers.User.status:type_name -> customers.UserStatus
+	128, // 6: customers.User.profile:type_name -> customers.User.ProfileEntry
+	134, // 7: customers.UserIdentity.created_at:type_name -> google.protobuf.Timestamp
+	134, // 8: customers.UserIdentity.last_used:type_name -> google.protobuf.Timestamp
+	129, // 9: customers.UserIdentity.provider_data:type_name -> customers.UserIdentity.ProviderDataEntry
+	134, // 10: customers.Organization.created_at:type_name -> google.protobuf.Timestamp
+	1,   // 11: customers.OrgMembership.role:type_name -> customers.OrgRole
+	134, // 12: customers.OrgMembership.joined_at:type_name -> google.protobuf.Timestamp
+	134, // 13: customers.Team.created_at:type_name -> google.protobuf.Timestamp
+	2,   // 14: customers.TeamMembership.role:type_name -> customers.TeamRole
+	134, // 15: customers.TeamMembership.joined_at:type_name -> google.protobuf.Timestamp
+	18,  // 16: customers.Role.permissions:type_name -> customers.Permission
+	3,   // 17: customers.RoleAssignment.subject_kind:type_name -> customers.SubjectKind
+	134, // 18: customers.RoleAssignment.assigned_at:type_name -> google.protobuf.Timestamp
+	130, // 19: customers.RegisterUserRequest.profile:type_name -> customers.RegisterUserRequest.ProfileEntry
+	13,  // 20: customers.RegisterUserRequest.identity:type_name -> customers.UserIdentity
+	12,  // 21: customers.RegisterUserResponse.user:type_name -> customers.User
+	13,  // 22: customers.RegisterUserResponse.identity:type_name -> customers.UserIdentity
+	12,  // 23: customers.GetSelfResponse.user:type_name -> customers.User
+	13,  // 24: customers.GetSelfResponse.identities:type_name -> customers.UserIdentity
+	14,  // 25: customers.GetSelfResponse.organizations:type_name -> customers.Organization
+	20,  // 26: customers.GetSelfResponse.role_assignments:type_name -> customers.RoleAssignment
+	0,   // 27: customers.ListUsersRequest.status:type_name -> customers.UserStatus
+	12,  // 28: customers.ListUsersResponse.users:type_name -> customers.User
+	12,  // 29: customers.UpdateUserRequest.user:type_name -> customers.User
+	135, // 30: customers.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
+	13,  // 31: customers.AddIdentityRequest.identity:type_name -> customers.UserIdentity
+	13,  // 32: customers.ListUserIdentitiesResponse.identities:type_name -> customers.UserIdentity
+	14,  // 33: customers.CreateOrganizationResponse.organization:type_name -> customers.Organization
+	14,  // 34: customers.ListOrganizationsResponse.organizations:type_name -> customers.Organization
+	1,   // 35: customers.AddOrgMemberRequest.role:type_name -> customers.OrgRole
+	15,  // 36: customers.ListOrgMembersResponse.members:type_name -> customers.OrgMembership
+	16,  // 37: customers.CreateTeamResponse.team:type_name -> customers.Team
+	16,  // 38: customers.ListTeamsResponse.teams:type_name -> customers.Team
+	2,   // 39: customers.AddTeamMemberRequest.role:type_name -> customers.TeamRole
+	17,  // 40: customers.ListTeamMembersResponse.members:type_name -> customers.TeamMembership
+	18,  // 41: customers.CreateRoleRequest.permissions:type_name -> customers.Permission
+	19,  // 42: customers.CreateRoleResponse.role:type_name -> customers.Role
+	19,  // 43: customers.ListRolesResponse.roles:type_name -> customers.Role
+	3,   // 44: customers.AssignRoleRequest.subject_kind:type_name -> customers.SubjectKind
+	20,  // 45: customers.AssignRoleResponse.assignment:type_name -> customers.RoleAssignment
+	3,   // 46: customers.CheckPermissionRequest.subject_kind:type_name -> customers.SubjectKind
+	18,  // 47: customers.APIKey.scopes:type_name -> customers.Permission
+	4,   // 48: customers.APIKey.environment:type_name -> customers.APIKeyEnvironment
+	134, // 49: customers.APIKey.created_at:type_name -> google.protobuf.Timestamp
+	134, // 50: customers.APIKey.expires_at:type_name -> google.protobuf.Timestamp
+	134, // 51: customers.APIKey.last_used_at:type_name -> google.protobuf.Timestamp
+	134, // 52: customers.APIKey.revoked_at:type_name -> google.protobuf.Timestamp
+	134, // 53: customers.APIKey.revoke_at:type_name -> google.protobuf.Timestamp
+	134, // 54: customers.APIKey.disabled_at:type_name -> google.protobuf.Timestamp
+	63,  // 55: customers.APIKey.daily_usage:type_name -> customers.APIKeyDailyUsage
+	18,  // 56: customers.CreateAPIKeyRequest.scopes:type_name -> customers.Permission
+	4,   // 57: customers.CreateAPIKeyRequest.environment:type_name -> customers.APIKeyEnvironment
+	134, // 58: customers.CreateAPIKeyRequest.expires_at:type_name -> google.protobuf.Timestamp
+	62,  // 59: customers.CreateAPIKeyResponse.key:type_name -> customers.APIKey
+	134, // 60: customers.ListAPIKeysRequest.unused_since:type_name -> google.protobuf.Timestamp
+	62,  // 61: customers.ListAPIKeysResponse.keys:type_name -> customers.APIKey
+	62,  // 62: customers.UpdateAPIKeyRequest.key:type_name -> customers.APIKey
+	135, // 63: customers.UpdateAPIKeyRequest.update_mask:type_name -> google.protobuf.FieldMask
+	134, // 64: customers.RotateAPIKeyRequest.expires_at:type_name -> google.protobuf.Timestamp
+	62,  // 65: customers.RotateAPIKeyResponse.key:type_name -> customers.APIKey
+	62,  // 66: customers.RotateAPIKeyResponse.previous_key:type_name -> customers.APIKey
+	62,  // 67: customers.ReevaluateAPIKeysResponse.disabled:type_name -> customers.APIKey
+	62,  // 68: customers.ReevaluateAPIKeysResponse.enabled:type_name -> customers.APIKey
+	134, // 69: customers.ValidateAPIKeyResponse.revoke_at:type_name -> google.protobuf.Timestamp
+	5,   // 70: customers.ValidateAPIKeyResponse.reason:type_name -> customers.APIKeyInvalidReason
+	78,  // 71: customers.ReportLeakedAPIKeysRequest.reports:type_name -> customers.LeakedAPIKeyReport
+	80,  // 72: customers.ReportLeakedAPIKeysResponse.results:type_name -> customers.LeakedAPIKeyResult
+	134, // 73: customers.GetRateLimitsResponse.period_end:type_name -> google.protobuf.Timestamp
+	84,  // 74: customers.RecordUsageRequest.records:type_name -> customers.UsageRecord
+	131, // 75: customers.AuthenticateRequest.profile:type_name -> customers.AuthenticateRequest.ProfileEntry
+	12,  // 76: customers.AuthenticateResponse.user:type_name -> customers.User
+	132, // 77: customers.AuditEvent.metadata:type_name -> customers.AuditEvent.MetadataEntry
+	134, // 78: customers.AuditEvent.created_at:type_name -> google.protobuf.Timestamp
+	134, // 79: customers.QueryAuditLogRequest.from:type_name -> google.protobuf.Timestamp
+	134, // 80: customers.QueryAuditLogRequest.to:type_name -> google.protobuf.Timestamp
+	92,  // 81: customers.QueryAuditLogResponse.events:type_name -> customers.AuditEvent
+	134, // 82: customers.VerifyAuditChainRequest.from:type_name -> google.protobuf.Timestamp
+	134, // 83: customers.VerifyAuditChainRequest.to:type_name -> google.protobuf.Timestamp
+	96,  // 84: customers.VerifyAuditChainResponse.first_break:type_name -> customers.AuditChainBreak
+	134, // 85: customers.AuditCheckpoint.created_at:type_name -> google.protobuf.Timestamp
+	134, // 86: customers.ListAuditCheckpointsRequest.from:type_name -> google.protobuf.Timestamp
+	134, // 87: customers.ListAuditCheckpointsRequest.to:type_name -> google.protobuf.Timestamp
+	98,  // 88: customers.ListAuditCheckpointsResponse.checkpoints:type_name -> customers.AuditCheckpoint
+	134, // 89: customers.WatchAuditLogRequest.from:type_name -> google.protobuf.Timestamp
+	134, // 90: customers.WatchAuditLogRequest.to:type_name -> google.protobuf.Timestamp
+	92,  // 91: customers.WatchAuditLogResponse.event:type_name -> customers.AuditEvent
+	134, // 92: customers.ExportAuditLogRequest.from:type_name -> google.protobuf.Timestamp
+	134, // 93: customers.ExportAuditLogRequest.to:type_name -> google.protobuf.Timestamp
+	6,   // 94: customers.ExportAuditLogRequest.format:type_name -> customers.AuditExportFormat
+	7,   // 95: customers.Invitation.status:type_name -> customers.InvitationStatus
+	134, // 96: customers.Invitation.expires_at:type_name -> google.protobuf.Timestamp
+	134, // 97: customers.Invitation.created_at:type_name -> google.protobuf.Timestamp
+	104, // 98: customers.CreateInvitationResponse.invitation:type_name -> customers.Invitation
+	14,  // 99: customers.AcceptInvitationResponse.organization:type_name -> customers.Organization
+	7,   // 100: customers.ListInvitationsRequest.status:type_name -> customers.InvitationStatus
+	104, // 101: customers.ListInvitationsResponse.invitations:type_name -> customers.Invitation
+	12,  // 102: customers.SearchUsersResponse.users:type_name -> customers.User
+	133, // 103: customers.SessionInfo.device_info:type_name -> customers.SessionInfo.DeviceInfoEntry
+	134, // 104: customers.SessionInfo.created_at:type_name -> google.protobuf.Timestamp
+	134, // 105: customers.SessionInfo.last_active_at:type_name -> google.protobuf.Timestamp
+	134, // 106: customers.SessionInfo.expires_at:type_name -> google.protobuf.Timestamp
+	119, // 107: customers.ListActiveSessionsResponse.sessions:type_name -> customers.SessionInfo
+	123, // 108: customers.GetOrgEntitlementsResponse.entitlements:type_name -> customers.EntitlementInfo
+	8,   // 109: customers.UserService.Version:input_type -> customers.VersionRequest
+	10,  // 110: customers.UserService.Health:input_type -> customers.HealthRequest
+	24,  // 111: customers.UserService.GetSelf:input_type -> customers.GetSelfRequest
+	21,  // 112: customers.UserService.RegisterUser:input_type -> customers.RegisterUserRequest
+	23,  // 113: customers.UserService.GetUser:input_type -> customers.GetUserRequest
+	26,  // 114: customers.UserService.ListUsers:input_type -> customers.ListUsersRequest
+	28,  // 115: customers.UserService.UpdateUser:input_type -> customers.UpdateUserRequest
+	23,  // 116: customers.UserService.DeleteUser:input_type -> customers.GetUserRequest
+	29,  // 117: customers.UserService.AddIdentity:input_type -> customers.AddIdentityRequest
+	30,  // 118: customers.UserService.FindUserByIdentity:input_type -> customers.FindUserByIdentityRequest
+	31,  // 119: customers.UserService.ListUserIdentities:input_type -> customers.ListUserIdentitiesRequest
+	33,  // 120: customers.OrganizationService.CreateOrganization:input_type -> customers.CreateOrganizationRequest
+	35,  // 121: customers.OrganizationService.GetOrganization:input_type -> customers.GetOrganizationRequest
+	36,  // 122: customers.OrganizationService.ListOrganizations:input_type -> customers.ListOrganizationsRequest
+	38,  // 123: customers.OrganizationService.AddMember:input_type -> customers.AddOrgMemberRequest
+	39,  // 124: customers.OrganizationService.RemoveMember:input_type -> customers.RemoveOrgMemberRequest
+	40,  // 125: customers.OrganizationService.ListMembers:input_type -> customers.ListOrgMembersRequest
+	42,  // 126: customers.TeamService.CreateTeam:input_type -> customers.CreateTeamRequest
+	44,  // 127: customers.TeamService.ListTeams:input_type -> customers.ListTeamsRequest
+	46,  // 128: customers.TeamService.AddMember:input_type -> customers.AddTeamMemberRequest
+	47,  // 129: customers.TeamService.RemoveMember:input_type -> customers.RemoveTeamMemberRequest
+	48,  // 130: customers.TeamService.ListMembers:input_type -> customers.ListTeamMembersRequest
+	50,  // 131: customers.PermissionService.CreateRole:input_type -> customers.CreateRoleRequest
+	52,  // 132: customers.PermissionService.ListRoles:input_type -> customers.ListRolesRequest
+	54,  // 133: customers.PermissionService.DeleteRole:input_type -> customers.DeleteRoleRequest
+	55,  // 134: customers.PermissionService.AssignRole:input_type -> customers.AssignRoleRequest
+	57,  // 135: customers.PermissionService.RevokeRole:input_type -> customers.RevokeRoleRequest
+	58,  // 136: customers.PermissionService.CheckPermission:input_type -> customers.CheckPermissionRequest
+	60,  // 137: customers.IdentityService.ResolveIdentity:input_type -> customers.ResolveIdentityRequest
+	64,  // 138: customers.APIKeyService.CreateAPIKey:input_type -> customers.CreateAPIKeyRequest
+	66,  // 139: customers.APIKeyService.ListAPIKeys:input_type -> customers.ListAPIKeysRequest
+	68,  // 140: customers.APIKeyService.UpdateAPIKey:input_type -> customers.UpdateAPIKeyRequest
+	69,  // 141: customers.APIKeyService.RevokeAPIKey:input_type -> customers.RevokeAPIKeyRequest
+	70,  // 142: customers.APIKeyService.RevokeAPIKeys:input_type -> customers.RevokeAPIKeysRequest
+	72,  // 143: customers.APIKeyService.RotateAPIKey:input_type -> customers.RotateAPIKeyRequest
+	74,  // 144: customers.APIKeyService.ReevaluateAPIKeys:input_type -> customers.ReevaluateAPIKeysRequest
+	76,  // 145: customers.APIKeyService.ValidateAPIKey:input_type -> customers.ValidateAPIKeyRequest
+	79,  // 146: customers.APIKeyService.ReportLeakedAPIKeys:input_type -> customers.ReportLeakedAPIKeysRequest
+	82,  // 147: customers.MeteringService.GetRateLimits:input_type -> customers.GetRateLimitsRequest
+	85,  // 148: customers.MeteringService.RecordUsage:input_type -> customers.RecordUsageRequest
+	86,  // 149: customers.AuthService.Authenticate:input_type -> customers.AuthenticateRequest
+	88,  // 150: customers.AuthService.RefreshToken:input_type -> customers.RefreshTokenRequest
+	90,  // 151: customers.AuthService.Logout:input_type -> customers.LogoutRequest
+	136, // 152: customers.AuthService.GetJWKS:input_type -> google.protobuf.Empty
+	93,  // 153: customers.AuditService.QueryAuditLog:input_type -> customers.QueryAuditLogRequest
+	95,  // 154: customers.AuditService.VerifyAuditChain:input_type -> customers.VerifyAuditChainRequest
+	99,  // 155: customers.AuditService.ListAuditCheckpoints:input_type -> customers.ListAuditCheckpointsRequest
+	101, // 156: customers.AuditService.WatchAuditLog:input_type -> customers.WatchAuditLogRequest
+	103, // 157: customers.AuditService.ExportAuditLog:input_type -> customers.ExportAuditLogRequest
+	112, // 158: customers.AdminService.SearchUsers:input_type -> customers.SearchUsersRequest
+	114, // 159: customers.AdminService.SuspendUser:input_type -> customers.SuspendUserRequest
+	115, // 160: customers.AdminService.UnsuspendUser:input_type -> customers.UnsuspendUserRequest
+	116, // 161: customers.AdminService.ImpersonateUser:input_type -> customers.ImpersonateUserRequest
+	118, // 162: customers.AdminService.ListActiveSessions:input_type -> customers.ListActiveSessionsRequest
+	121, // 163: customers.AdminService.GetOrgEntitlements:input_type -> customers.GetOrgEntitlementsRequest
+	124, // 164: customers.AdminService.OverrideEntitlement:input_type -> customers.OverrideEntitlementRequest
+	105, // 165: customers.InvitationService.CreateInvitation:input_type -> customers.CreateInvitationRequest
+	107, // 166: customers.InvitationService.AcceptInvitation:input_type -> customers.AcceptInvitationRequest
+	109, // 167: customers.InvitationService.ListInvitations:input_type -> customers.ListInvitationsRequest
+	111, // 168: customers.InvitationService.RevokeInvitation:input_type -> customers.RevokeInvitationRequest
+	9,   // 169: customers.UserService.Version:output_type -> customers.VersionResponse
+	11,  // 170: customers.UserService.Health:output_type -> customers.HealthResponse
+	25,  // 171: customers.UserService.GetSelf:output_type -> customers.GetSelfResponse
+	22,  // 172: customers.UserService.RegisterUser:output_type -> customers.RegisterUserResponse
+	12,  // 173: customers.UserService.GetUser:output_type -> customers.User
+	27,  // 174: customers.UserService.ListUsers:output_type -> customers.ListUsersResponse
+	12,  // 175: customers.UserService.UpdateUser:output_type -> customers.User
+	136, // 176: customers.UserService.DeleteUser:output_type -> google.protobuf.Empty
+	13,  // 177: customers.UserService.AddIdentity:output_type -> customers.UserIdentity
+	12,  // 178: customers.UserService.FindUserByIdentity:output_type -> customers.User
+	32,  // 179: customers.UserService.ListUserIdentities:output_type -> customers.ListUserIdentitiesResponse
+	34,  // 180: customers.OrganizationService.CreateOrganization:output_type -> customers.CreateOrganizationResponse
+	14,  // 181: customers.OrganizationService.GetOrganization:output_type -> customers.Organization
+	37,  // 182: customers.OrganizationService.ListOrganizations:output_type -> customers.ListOrganizationsResponse
+	136, // 183: customers.OrganizationService.AddMember:output_type -> google.protobuf.Empty
+	136, // 184: customers.OrganizationService.RemoveMember:output_type -> google.protobuf.Empty
+	41,  // 185: customers.OrganizationService.ListMembers:output_type -> customers.ListOrgMembersResponse
+	43,  // 186: customers.TeamService.CreateTeam:output_type -> customers.CreateTeamResponse
+	45,  // 187: customers.TeamService.ListTeams:output_type -> customers.ListTeamsResponse
+	136, // 188: customers.TeamService.AddMember:output_type -> google.protobuf.Empty
+	136, // 189: customers.TeamService.RemoveMember:output_type -> google.protobuf.Empty
+	49,  // 190: customers.TeamService.ListMembers:output_type -> customers.ListTeamMembersResponse
+	51,  // 191: customers.PermissionService.CreateRole:output_type -> customers.CreateRoleResponse
+	53,  // 192: customers.PermissionService.ListRoles:output_type -> customers.ListRolesResponse
+	136, // 193: customers.PermissionService.DeleteRole:output_type -> google.protobuf.Empty
+	56,  // 194: customers.PermissionService.AssignRole:output_type -> customers.AssignRoleResponse
+	136, // 195: customers.PermissionService.RevokeRole:output_type -> google.protobuf.Empty
+	59,  // 196: customers.PermissionService.CheckPermission:output_type -> customers.CheckPermissionResponse
+	61,  // 197: customers.IdentityService.ResolveIdentity:output_type -> customers.ResolveIdentityResponse
+	65,  // 198: customers.APIKeyService.CreateAPIKey:output_type -> customers.CreateAPIKeyResponse
+	67,  // 199: customers.APIKeyService.ListAPIKeys:output_type -> customers.ListAPIKeysResponse
+	62,  // 200: customers.APIKeyService.UpdateAPIKey:output_type -> customers.APIKey
+	136, // 201: customers.APIKeyService.RevokeAPIKey:output_type -> google.protobuf.Empty
+	71,  // 202: customers.APIKeyService.RevokeAPIKeys:output_type -> customers.RevokeAPIKeysResponse
+	73,  // 203: customers.APIKeyService.RotateAPIKey:output_type -> customers.RotateAPIKeyResponse
+	75,  // 204: customers.APIKeyService.ReevaluateAPIKeys:output_type -> customers.ReevaluateAPIKeysResponse
+	77,  // 205: customers.APIKeyService.ValidateAPIKey:output_type -> customers.ValidateAPIKeyResponse
+	81,  // 206: customers.APIKeyService.ReportLeakedAPIKeys:output_type -> customers.ReportLeakedAPIKeysResponse
+	83,  // 207: customers.MeteringService.GetRateLimits:output_type -> customers.GetRateLimitsResponse
+	136, // 208: customers.MeteringService.RecordUsage:output_type -> google.protobuf.Empty
+	87,  // 209: customers.AuthService.Authenticate:output_type -> customers.AuthenticateResponse
+	89,  // 210: customers.AuthService.RefreshToken:output_type -> customers.RefreshTokenResponse
+	136, // 211: customers.AuthService.Logout:output_type -> google.protobuf.Empty
+	91,  // 212: customers.AuthService.GetJWKS:output_type -> customers.JWKSResponse
+	94,  // 213: customers.AuditService.QueryAuditLog:output_type -> customers.QueryAuditLogResponse
+	97,  // 214: customers.AuditService.VerifyAuditChain:output_type -> customers.VerifyAuditChainResponse
+	100, // 215: customers.AuditService.ListAuditCheckpoints:output_type -> customers.ListAuditCheckpointsResponse
+	102, // 216: customers.AuditService.WatchAuditLog:output_type -> customers.WatchAuditLogResponse
+	137, // 217: customers.AuditService.ExportAuditLog:output_type -> google.api.HttpBody
+	113, // 218: customers.AdminService.SearchUsers:output_type -> customers.SearchUsersResponse
+	136, // 219: customers.AdminService.SuspendUser:output_type -> google.protobuf.Empty
+	136, // 220: customers.AdminService.UnsuspendUser:output_type -> google.protobuf.Empty
+	117, // 221: customers.AdminService.ImpersonateUser:output_type -> customers.ImpersonateUserResponse
+	120, // 222: customers.AdminService.ListActiveSessions:output_type -> customers.ListActiveSessionsResponse
+	122, // 223: customers.AdminService.GetOrgEntitlements:output_type -> customers.GetOrgEntitlementsResponse
+	125, // 224: customers.AdminService.OverrideEntitlement:output_type -> customers.OverrideEntitlementResponse
+	106, // 225: customers.InvitationService.CreateInvitation:output_type -> customers.CreateInvitationResponse
+	108, // 226: customers.InvitationService.AcceptInvitation:output_type -> customers.AcceptInvitationResponse
+	110, // 227: customers.InvitationService.ListInvitations:output_type -> customers.ListInvitationsResponse
+	136, // 228: customers.InvitationService.RevokeInvitation:output_type -> google.protobuf.Empty
+	169, // [169:229] is the sub-list for method output_type
+	109, // [109:169] is the sub-list for method input_type
+	109, // [109:109] is the sub-list for extension type_name
+	109, // [109:109] is the sub-list for extension extendee
+	0,   // [0:109] is the sub-list for field type_name
 }
 
 func init() { file_user_proto_init() }
@@ -6527,7 +8724,7 @@ func file_user_proto_init() {
 	if File_user_proto != nil {
 		return
 	}
-	file_user_proto_msgTypes[13].OneofWrappers = []any{
+	file_user_proto_msgTypes[15].OneofWrappers = []any{
 		(*GetUserRequest_Uuid)(nil),
 		(*GetUserRequest_Email)(nil),
 	}
@@ -6536,10 +8733,10 @@ func file_user_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_proto_rawDesc), len(file_user_proto_rawDesc)),
-			NumEnums:      6,
-			NumMessages:   97,
+			NumEnums:      8,
+			NumMessages:   126,
 			NumExtensions: 0,
-			NumServices:   10,
+			NumServices:   11,
 		},
 		GoTypes:           file_user_proto_goTypes,
 		DependencyIndexes: file_user_proto_depIdxs,
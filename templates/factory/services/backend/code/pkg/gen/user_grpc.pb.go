@@ -8,6 +8,7 @@ package gen
 
 import (
 	context "context"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -21,6 +22,7 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	UserService_Version_FullMethodName            = "/customers.UserService/Version"
+	UserService_Health_FullMethodName             = "/customers.UserService/Health"
 	UserService_GetSelf_FullMethodName            = "/customers.UserService/GetSelf"
 	UserService_RegisterUser_FullMethodName       = "/customers.UserService/RegisterUser"
 	UserService_GetUser_FullMethodName            = "/customers.UserService/GetUser"
@@ -39,6 +41,7 @@ const (
 // UserService — user CRUD and identity management
 type UserServiceClient interface {
 	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
 	GetSelf(ctx context.Context, in *GetSelfRequest, opts ...grpc.CallOption) (*GetSelfResponse, error)
 	RegisterUser(ctx context.Context, in *RegisterUserRequest, opts ...grpc.CallOption) (*RegisterUserResponse, error)
 	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
@@ -68,6 +71,16 @@ func (c *userServiceClient) Version(ctx context.Context, in *VersionRequest, opt
 	return out, nil
 }
 
+func (c *userServiceClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, UserService_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *userServiceClient) GetSelf(ctx context.Context, in *GetSelfRequest, opts ...grpc.CallOption) (*GetSelfResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetSelfResponse)
@@ -165,6 +178,7 @@ func (c *userServiceClient) ListUserIdentities(ctx context.Context, in *ListUser
 // UserService — user CRUD and identity management
 type UserServiceServer interface {
 	Version(context.Context, *VersionRequest) (*VersionResponse, error)
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
 	GetSelf(context.Context, *GetSelfRequest) (*GetSelfResponse, error)
 	RegisterUser(context.Context, *RegisterUserRequest) (*RegisterUserResponse, error)
 	GetUser(context.Context, *GetUserRequest) (*User, error)
@@ -187,6 +201,9 @@ type UnimplementedUserServiceServer struct{}
 func (UnimplementedUserServiceServer) Version(context.Context, *VersionRequest) (*VersionResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Version not implemented")
 }
+func (UnimplementedUserServiceServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Health not implemented")
+}
 func (UnimplementedUserServiceServer) GetSelf(context.Context, *GetSelfRequest) (*GetSelfResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetSelf not implemented")
 }
@@ -253,6 +270,24 @@ func _UserService_Version_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UserService_GetSelf_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetSelfRequest)
 	if err := dec(in); err != nil {
@@ -426,6 +461,10 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _UserService_Version_Handler,
 		},
+		{
+			MethodName: "Health",
+			Handler:    _UserService_Health_Handler,
+		},
 		{
 			MethodName: "GetSelf",
 			Handler:    _UserService_GetSelf_Handler,
@@ -1424,10 +1463,15 @@ var IdentityService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	APIKeyService_CreateAPIKey_FullMethodName   = "/customers.APIKeyService/CreateAPIKey"
-	APIKeyService_ListAPIKeys_FullMethodName    = "/customers.APIKeyService/ListAPIKeys"
-	APIKeyService_RevokeAPIKey_FullMethodName   = "/customers.APIKeyService/RevokeAPIKey"
-	APIKeyService_ValidateAPIKey_FullMethodName = "/customers.APIKeyService/ValidateAPIKey"
+	APIKeyService_CreateAPIKey_FullMethodName        = "/customers.APIKeyService/CreateAPIKey"
+	APIKeyService_ListAPIKeys_FullMethodName         = "/customers.APIKeyService/ListAPIKeys"
+	APIKeyService_UpdateAPIKey_FullMethodName        = "/customers.APIKeyService/UpdateAPIKey"
+	APIKeyService_RevokeAPIKey_FullMethodName        = "/customers.APIKeyService/RevokeAPIKey"
+	APIKeyService_RevokeAPIKeys_FullMethodName       = "/customers.APIKeyService/RevokeAPIKeys"
+	APIKeyService_RotateAPIKey_FullMethodName        = "/customers.APIKeyService/RotateAPIKey"
+	APIKeyService_ReevaluateAPIKeys_FullMethodName   = "/customers.APIKeyService/ReevaluateAPIKeys"
+	APIKeyService_ValidateAPIKey_FullMethodName      = "/customers.APIKeyService/ValidateAPIKey"
+	APIKeyService_ReportLeakedAPIKeys_FullMethodName = "/customers.APIKeyService/ReportLeakedAPIKeys"
 )
 
 // APIKeyServiceClient is the client API for APIKeyService service.
@@ -1438,8 +1482,19 @@ const (
 type APIKeyServiceClient interface {
 	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
 	ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+	UpdateAPIKey(ctx context.Context, in *UpdateAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error)
 	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Revokes many keys of an org at once, e.g. the stale ones.
+	RevokeAPIKeys(ctx context.Context, in *RevokeAPIKeysRequest, opts ...grpc.CallOption) (*RevokeAPIKeysResponse, error)
+	RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*RotateAPIKeyResponse, error)
+	// Re-checks every key's scopes in the org against its owner's current
+	// permissions. Needs api_keys:manage in the org.
+	ReevaluateAPIKeys(ctx context.Context, in *ReevaluateAPIKeysRequest, opts ...grpc.CallOption) (*ReevaluateAPIKeysResponse, error)
 	ValidateAPIKey(ctx context.Context, in *ValidateAPIKeyRequest, opts ...grpc.CallOption) (*ValidateAPIKeyResponse, error)
+	// Keys found in public places by a secret-scanning partner, forwarded by
+	// the auth-sidecar once it has verified the report's signature. Live keys
+	// among them are revoked and their org's admins notified.
+	ReportLeakedAPIKeys(ctx context.Context, in *ReportLeakedAPIKeysRequest, opts ...grpc.CallOption) (*ReportLeakedAPIKeysResponse, error)
 }
 
 type aPIKeyServiceClient struct {
@@ -1470,6 +1525,16 @@ func (c *aPIKeyServiceClient) ListAPIKeys(ctx context.Context, in *ListAPIKeysRe
 	return out, nil
 }
 
+func (c *aPIKeyServiceClient) UpdateAPIKey(ctx context.Context, in *UpdateAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, APIKeyService_UpdateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIKeyServiceClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -1480,6 +1545,36 @@ func (c *aPIKeyServiceClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKey
 	return out, nil
 }
 
+func (c *aPIKeyServiceClient) RevokeAPIKeys(ctx context.Context, in *RevokeAPIKeysRequest, opts ...grpc.CallOption) (*RevokeAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAPIKeysResponse)
+	err := c.cc.Invoke(ctx, APIKeyService_RevokeAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeyServiceClient) RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*RotateAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, APIKeyService_RotateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeyServiceClient) ReevaluateAPIKeys(ctx context.Context, in *ReevaluateAPIKeysRequest, opts ...grpc.CallOption) (*ReevaluateAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReevaluateAPIKeysResponse)
+	err := c.cc.Invoke(ctx, APIKeyService_ReevaluateAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIKeyServiceClient) ValidateAPIKey(ctx context.Context, in *ValidateAPIKeyRequest, opts ...grpc.CallOption) (*ValidateAPIKeyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ValidateAPIKeyResponse)
@@ -1490,6 +1585,16 @@ func (c *aPIKeyServiceClient) ValidateAPIKey(ctx context.Context, in *ValidateAP
 	return out, nil
 }
 
+func (c *aPIKeyServiceClient) ReportLeakedAPIKeys(ctx context.Context, in *ReportLeakedAPIKeysRequest, opts ...grpc.CallOption) (*ReportLeakedAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportLeakedAPIKeysResponse)
+	err := c.cc.Invoke(ctx, APIKeyService_ReportLeakedAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // APIKeyServiceServer is the server API for APIKeyService service.
 // All implementations must embed UnimplementedAPIKeyServiceServer
 // for forward compatibility.
@@ -1498,8 +1603,19 @@ func (c *aPIKeyServiceClient) ValidateAPIKey(ctx context.Context, in *ValidateAP
 type APIKeyServiceServer interface {
 	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
 	ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	UpdateAPIKey(context.Context, *UpdateAPIKeyRequest) (*APIKey, error)
 	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*emptypb.Empty, error)
+	// Revokes many keys of an org at once, e.g. the stale ones.
+	RevokeAPIKeys(context.Context, *RevokeAPIKeysRequest) (*RevokeAPIKeysResponse, error)
+	RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*RotateAPIKeyResponse, error)
+	// Re-checks every key's scopes in the org against its owner's current
+	// permissions. Needs api_keys:manage in the org.
+	ReevaluateAPIKeys(context.Context, *ReevaluateAPIKeysRequest) (*ReevaluateAPIKeysResponse, error)
 	ValidateAPIKey(context.Context, *ValidateAPIKeyRequest) (*ValidateAPIKeyResponse, error)
+	// Keys found in public places by a secret-scanning partner, forwarded by
+	// the auth-sidecar once it has verified the report's signature. Live keys
+	// among them are revoked and their org's admins notified.
+	ReportLeakedAPIKeys(context.Context, *ReportLeakedAPIKeysRequest) (*ReportLeakedAPIKeysResponse, error)
 	mustEmbedUnimplementedAPIKeyServiceServer()
 }
 
@@ -1516,12 +1632,27 @@ func (UnimplementedAPIKeyServiceServer) CreateAPIKey(context.Context, *CreateAPI
 func (UnimplementedAPIKeyServiceServer) ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListAPIKeys not implemented")
 }
+func (UnimplementedAPIKeyServiceServer) UpdateAPIKey(context.Context, *UpdateAPIKeyRequest) (*APIKey, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateAPIKey not implemented")
+}
 func (UnimplementedAPIKeyServiceServer) RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method RevokeAPIKey not implemented")
 }
+func (UnimplementedAPIKeyServiceServer) RevokeAPIKeys(context.Context, *RevokeAPIKeysRequest) (*RevokeAPIKeysResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeAPIKeys not implemented")
+}
+func (UnimplementedAPIKeyServiceServer) RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*RotateAPIKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RotateAPIKey not implemented")
+}
+func (UnimplementedAPIKeyServiceServer) ReevaluateAPIKeys(context.Context, *ReevaluateAPIKeysRequest) (*ReevaluateAPIKeysResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReevaluateAPIKeys not implemented")
+}
 func (UnimplementedAPIKeyServiceServer) ValidateAPIKey(context.Context, *ValidateAPIKeyRequest) (*ValidateAPIKeyResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ValidateAPIKey not implemented")
 }
+func (UnimplementedAPIKeyServiceServer) ReportLeakedAPIKeys(context.Context, *ReportLeakedAPIKeysRequest) (*ReportLeakedAPIKeysResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReportLeakedAPIKeys not implemented")
+}
 func (UnimplementedAPIKeyServiceServer) mustEmbedUnimplementedAPIKeyServiceServer() {}
 func (UnimplementedAPIKeyServiceServer) testEmbeddedByValue()                       {}
 
@@ -1579,6 +1710,24 @@ func _APIKeyService_ListAPIKeys_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _APIKeyService_UpdateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeyServiceServer).UpdateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIKeyService_UpdateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeyServiceServer).UpdateAPIKey(ctx, req.(*UpdateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _APIKeyService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RevokeAPIKeyRequest)
 	if err := dec(in); err != nil {
@@ -1597,6 +1746,60 @@ func _APIKeyService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _APIKeyService_RevokeAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeyServiceServer).RevokeAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIKeyService_RevokeAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeyServiceServer).RevokeAPIKeys(ctx, req.(*RevokeAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIKeyService_RotateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeyServiceServer).RotateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIKeyService_RotateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeyServiceServer).RotateAPIKey(ctx, req.(*RotateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIKeyService_ReevaluateAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReevaluateAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeyServiceServer).ReevaluateAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIKeyService_ReevaluateAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeyServiceServer).ReevaluateAPIKeys(ctx, req.(*ReevaluateAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _APIKeyService_ValidateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ValidateAPIKeyRequest)
 	if err := dec(in); err != nil {
@@ -1615,6 +1818,24 @@ func _APIKeyService_ValidateAPIKey_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _APIKeyService_ReportLeakedAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportLeakedAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeyServiceServer).ReportLeakedAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIKeyService_ReportLeakedAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeyServiceServer).ReportLeakedAPIKeys(ctx, req.(*ReportLeakedAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // APIKeyService_ServiceDesc is the grpc.ServiceDesc for APIKeyService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1630,14 +1851,178 @@ var APIKeyService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListAPIKeys",
 			Handler:    _APIKeyService_ListAPIKeys_Handler,
 		},
+		{
+			MethodName: "UpdateAPIKey",
+			Handler:    _APIKeyService_UpdateAPIKey_Handler,
+		},
 		{
 			MethodName: "RevokeAPIKey",
 			Handler:    _APIKeyService_RevokeAPIKey_Handler,
 		},
+		{
+			MethodName: "RevokeAPIKeys",
+			Handler:    _APIKeyService_RevokeAPIKeys_Handler,
+		},
+		{
+			MethodName: "RotateAPIKey",
+			Handler:    _APIKeyService_RotateAPIKey_Handler,
+		},
+		{
+			MethodName: "ReevaluateAPIKeys",
+			Handler:    _APIKeyService_ReevaluateAPIKeys_Handler,
+		},
 		{
 			MethodName: "ValidateAPIKey",
 			Handler:    _APIKeyService_ValidateAPIKey_Handler,
 		},
+		{
+			MethodName: "ReportLeakedAPIKeys",
+			Handler:    _APIKeyService_ReportLeakedAPIKeys_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "user.proto",
+}
+
+const (
+	MeteringService_GetRateLimits_FullMethodName = "/customers.MeteringService/GetRateLimits"
+	MeteringService_RecordUsage_FullMethodName   = "/customers.MeteringService/RecordUsage"
+)
+
+// MeteringServiceClient is the client API for MeteringService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MeteringService — rate limit lookup and batched usage recording
+type MeteringServiceClient interface {
+	GetRateLimits(ctx context.Context, in *GetRateLimitsRequest, opts ...grpc.CallOption) (*GetRateLimitsResponse, error)
+	RecordUsage(ctx context.Context, in *RecordUsageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type meteringServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMeteringServiceClient(cc grpc.ClientConnInterface) MeteringServiceClient {
+	return &meteringServiceClient{cc}
+}
+
+func (c *meteringServiceClient) GetRateLimits(ctx context.Context, in *GetRateLimitsRequest, opts ...grpc.CallOption) (*GetRateLimitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRateLimitsResponse)
+	err := c.cc.Invoke(ctx, MeteringService_GetRateLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meteringServiceClient) RecordUsage(ctx context.Context, in *RecordUsageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MeteringService_RecordUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MeteringServiceServer is the server API for MeteringService service.
+// All implementations must embed UnimplementedMeteringServiceServer
+// for forward compatibility.
+//
+// MeteringService — rate limit lookup and batched usage recording
+type MeteringServiceServer interface {
+	GetRateLimits(context.Context, *GetRateLimitsRequest) (*GetRateLimitsResponse, error)
+	RecordUsage(context.Context, *RecordUsageRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedMeteringServiceServer()
+}
+
+// UnimplementedMeteringServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMeteringServiceServer struct{}
+
+func (UnimplementedMeteringServiceServer) GetRateLimits(context.Context, *GetRateLimitsRequest) (*GetRateLimitsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRateLimits not implemented")
+}
+func (UnimplementedMeteringServiceServer) RecordUsage(context.Context, *RecordUsageRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method RecordUsage not implemented")
+}
+func (UnimplementedMeteringServiceServer) mustEmbedUnimplementedMeteringServiceServer() {}
+func (UnimplementedMeteringServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeMeteringServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MeteringServiceServer will
+// result in compilation errors.
+type UnsafeMeteringServiceServer interface {
+	mustEmbedUnimplementedMeteringServiceServer()
+}
+
+func RegisterMeteringServiceServer(s grpc.ServiceRegistrar, srv MeteringServiceServer) {
+	// If the following call panics, it indicates UnimplementedMeteringServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MeteringService_ServiceDesc, srv)
+}
+
+func _MeteringService_GetRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRateLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeteringServiceServer).GetRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeteringService_GetRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeteringServiceServer).GetRateLimits(ctx, req.(*GetRateLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeteringService_RecordUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeteringServiceServer).RecordUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeteringService_RecordUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeteringServiceServer).RecordUsage(ctx, req.(*RecordUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MeteringService_ServiceDesc is the grpc.ServiceDesc for MeteringService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MeteringService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "customers.MeteringService",
+	HandlerType: (*MeteringServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRateLimits",
+			Handler:    _MeteringService_GetRateLimits_Handler,
+		},
+		{
+			MethodName: "RecordUsage",
+			Handler:    _MeteringService_RecordUsage_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user.proto",
@@ -1864,7 +2249,11 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	AuditService_QueryAuditLog_FullMethodName = "/customers.AuditService/QueryAuditLog"
+	AuditService_QueryAuditLog_FullMethodName        = "/customers.AuditService/QueryAuditLog"
+	AuditService_VerifyAuditChain_FullMethodName     = "/customers.AuditService/VerifyAuditChain"
+	AuditService_ListAuditCheckpoints_FullMethodName = "/customers.AuditService/ListAuditCheckpoints"
+	AuditService_WatchAuditLog_FullMethodName        = "/customers.AuditService/WatchAuditLog"
+	AuditService_ExportAuditLog_FullMethodName       = "/customers.AuditService/ExportAuditLog"
 )
 
 // AuditServiceClient is the client API for AuditService service.
@@ -1874,6 +2263,11 @@ const (
 // AuditService — append-only audit event log
 type AuditServiceClient interface {
 	QueryAuditLog(ctx context.Context, in *QueryAuditLogRequest, opts ...grpc.CallOption) (*QueryAuditLogResponse, error)
+	VerifyAuditChain(ctx context.Context, in *VerifyAuditChainRequest, opts ...grpc.CallOption) (*VerifyAuditChainResponse, error)
+	ListAuditCheckpoints(ctx context.Context, in *ListAuditCheckpointsRequest, opts ...grpc.CallOption) (*ListAuditCheckpointsResponse, error)
+	WatchAuditLog(ctx context.Context, in *WatchAuditLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchAuditLogResponse], error)
+	// ExportAuditLog streams the file in chunks (application/x-ndjson or text/csv)
+	ExportAuditLog(ctx context.Context, in *ExportAuditLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[httpbody.HttpBody], error)
 }
 
 type auditServiceClient struct {
@@ -1894,6 +2288,64 @@ func (c *auditServiceClient) QueryAuditLog(ctx context.Context, in *QueryAuditLo
 	return out, nil
 }
 
+func (c *auditServiceClient) VerifyAuditChain(ctx context.Context, in *VerifyAuditChainRequest, opts ...grpc.CallOption) (*VerifyAuditChainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyAuditChainResponse)
+	err := c.cc.Invoke(ctx, AuditService_VerifyAuditChain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auditServiceClient) ListAuditCheckpoints(ctx context.Context, in *ListAuditCheckpointsRequest, opts ...grpc.CallOption) (*ListAuditCheckpointsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditCheckpointsResponse)
+	err := c.cc.Invoke(ctx, AuditService_ListAuditCheckpoints_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auditServiceClient) WatchAuditLog(ctx context.Context, in *WatchAuditLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchAuditLogResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AuditService_ServiceDesc.Streams[0], AuditService_WatchAuditLog_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAuditLogRequest, WatchAuditLogResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuditService_WatchAuditLogClient = grpc.ServerStreamingClient[WatchAuditLogResponse]
+
+func (c *auditServiceClient) ExportAuditLog(ctx context.Context, in *ExportAuditLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[httpbody.HttpBody], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AuditService_ServiceDesc.Streams[1], AuditService_ExportAuditLog_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportAuditLogRequest, httpbody.HttpBody]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuditService_ExportAuditLogClient = grpc.ServerStreamingClient[httpbody.HttpBody]
+
 // AuditServiceServer is the server API for AuditService service.
 // All implementations must embed UnimplementedAuditServiceServer
 // for forward compatibility.
@@ -1901,6 +2353,11 @@ func (c *auditServiceClient) QueryAuditLog(ctx context.Context, in *QueryAuditLo
 // AuditService — append-only audit event log
 type AuditServiceServer interface {
 	QueryAuditLog(context.Context, *QueryAuditLogRequest) (*QueryAuditLogResponse, error)
+	VerifyAuditChain(context.Context, *VerifyAuditChainRequest) (*VerifyAuditChainResponse, error)
+	ListAuditCheckpoints(context.Context, *ListAuditCheckpointsRequest) (*ListAuditCheckpointsResponse, error)
+	WatchAuditLog(*WatchAuditLogRequest, grpc.ServerStreamingServer[WatchAuditLogResponse]) error
+	// ExportAuditLog streams the file in chunks (application/x-ndjson or text/csv)
+	ExportAuditLog(*ExportAuditLogRequest, grpc.ServerStreamingServer[httpbody.HttpBody]) error
 	mustEmbedUnimplementedAuditServiceServer()
 }
 
@@ -1914,6 +2371,18 @@ type UnimplementedAuditServiceServer struct{}
 func (UnimplementedAuditServiceServer) QueryAuditLog(context.Context, *QueryAuditLogRequest) (*QueryAuditLogResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method QueryAuditLog not implemented")
 }
+func (UnimplementedAuditServiceServer) VerifyAuditChain(context.Context, *VerifyAuditChainRequest) (*VerifyAuditChainResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method VerifyAuditChain not implemented")
+}
+func (UnimplementedAuditServiceServer) ListAuditCheckpoints(context.Context, *ListAuditCheckpointsRequest) (*ListAuditCheckpointsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAuditCheckpoints not implemented")
+}
+func (UnimplementedAuditServiceServer) WatchAuditLog(*WatchAuditLogRequest, grpc.ServerStreamingServer[WatchAuditLogResponse]) error {
+	return status.Error(codes.Unimplemented, "method WatchAuditLog not implemented")
+}
+func (UnimplementedAuditServiceServer) ExportAuditLog(*ExportAuditLogRequest, grpc.ServerStreamingServer[httpbody.HttpBody]) error {
+	return status.Error(codes.Unimplemented, "method ExportAuditLog not implemented")
+}
 func (UnimplementedAuditServiceServer) mustEmbedUnimplementedAuditServiceServer() {}
 func (UnimplementedAuditServiceServer) testEmbeddedByValue()                      {}
 
@@ -1953,6 +2422,64 @@ func _AuditService_QueryAuditLog_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuditService_VerifyAuditChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyAuditChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditServiceServer).VerifyAuditChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuditService_VerifyAuditChain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditServiceServer).VerifyAuditChain(ctx, req.(*VerifyAuditChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuditService_ListAuditCheckpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditCheckpointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditServiceServer).ListAuditCheckpoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuditService_ListAuditCheckpoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditServiceServer).ListAuditCheckpoints(ctx, req.(*ListAuditCheckpointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuditService_WatchAuditLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAuditLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuditServiceServer).WatchAuditLog(m, &grpc.GenericServerStream[WatchAuditLogRequest, WatchAuditLogResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuditService_WatchAuditLogServer = grpc.ServerStreamingServer[WatchAuditLogResponse]
+
+func _AuditService_ExportAuditLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportAuditLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuditServiceServer).ExportAuditLog(m, &grpc.GenericServerStream[ExportAuditLogRequest, httpbody.HttpBody]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuditService_ExportAuditLogServer = grpc.ServerStreamingServer[httpbody.HttpBody]
+
 // AuditService_ServiceDesc is the grpc.ServiceDesc for AuditService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1964,8 +2491,27 @@ var AuditService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryAuditLog",
 			Handler:    _AuditService_QueryAuditLog_Handler,
 		},
+		{
+			MethodName: "VerifyAuditChain",
+			Handler:    _AuditService_VerifyAuditChain_Handler,
+		},
+		{
+			MethodName: "ListAuditCheckpoints",
+			Handler:    _AuditService_ListAuditCheckpoints_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAuditLog",
+			Handler:       _AuditService_WatchAuditLog_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportAuditLog",
+			Handler:       _AuditService_ExportAuditLog_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "user.proto",
 }
 
@@ -0,0 +1,85 @@
+package infra
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	codefly "github.com/codefly-dev/sdk-go"
+)
+
+// LocalAuditArchive keeps archived audit partitions on local storage, as
+// gzip-compressed NDJSON files: <dir>/<partition>.ndjson.gz.
+type LocalAuditArchive struct {
+	dir string
+}
+
+// NewLocalAuditArchive archives to the "audit" archive_dir configuration.
+// It returns nil without error when no directory is configured.
+func NewLocalAuditArchive(ctx context.Context) (*LocalAuditArchive, error) {
+	dir, err := codefly.For(ctx).Configuration("audit", "archive_dir")
+	if err != nil || dir == "" {
+		return nil, nil
+	}
+	return NewLocalAuditArchiveIn(dir)
+}
+
+// NewLocalAuditArchiveIn archives to dir, created if missing.
+func NewLocalAuditArchiveIn(dir string) (*LocalAuditArchive, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("no audit archive directory")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("cannot create audit archive directory: %w", err)
+	}
+	return &LocalAuditArchive{dir: dir}, nil
+}
+
+// Path is the file of a partition's archive.
+func (a *LocalAuditArchive) Path(name string) string {
+	return filepath.Join(a.dir, name+".ndjson.gz")
+}
+
+// Store implements business.AuditArchive. The file is written aside and
+// renamed once complete and synced, replacing any previous archive.
+func (a *LocalAuditArchive) Store(ctx context.Context, name string, write func(out io.Writer) error) error {
+	tmp, err := os.CreateTemp(a.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	buffered := bufio.NewWriterSize(tmp, 64*1024)
+	compressed := gzip.NewWriter(buffered)
+	compressed.Name = name + ".ndjson"
+	err = write(compressed)
+	if err == nil {
+		err = compressed.Close()
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), a.Path(name)); err != nil {
+		return err
+	}
+	// Persist the rename
+	dir, err := os.Open(a.dir)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
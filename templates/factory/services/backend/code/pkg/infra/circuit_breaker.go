@@ -0,0 +1,66 @@
+package infra
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops calls to a failing dependency. After threshold
+// consecutive failures it opens: calls are refused until the cooldown is
+// over, then a single probe is let through and its outcome closes or reopens
+// the circuit.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+	lastErr  error
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a call may go through.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record reports the outcome of an allowed call.
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		b.lastErr = nil
+		return
+	}
+	b.failures++
+	b.lastErr = err
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// open returns the last failure while the circuit is open, nil otherwise.
+func (b *circuitBreaker) open() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	return b.lastErr
+}
@@ -2,20 +2,30 @@ package infra
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
-	"io"
+	"maps"
+	"math/big"
 	"net/http"
+	"slices"
 	"time"
 
 	codefly "github.com/codefly-dev/sdk-go"
 	"github.com/codefly-dev/core/wool"
 	"github.com/golang-jwt/jwt/v5"
+
+	"backend/pkg/business"
 )
 
 const (
@@ -24,6 +34,16 @@ const (
 	TokenIssuer     = "codefly-user-mgmt"
 )
 
+// Supported JWT signing algorithms.
+const (
+	AlgEdDSA = "EdDSA"
+	AlgES256 = "ES256"
+	AlgRS256 = "RS256"
+)
+
+// SupportedAlgorithms lists the algorithms TokenService can sign and verify with.
+var SupportedAlgorithms = []string{AlgEdDSA, AlgES256, AlgRS256}
+
 // AccessClaims are the JWT claims embedded in access tokens.
 type AccessClaims struct {
 	jwt.RegisteredClaims
@@ -33,77 +53,270 @@ type AccessClaims struct {
 
 // TokenService handles JWT signing/verification and refresh token generation.
 type TokenService struct {
-	privateKey ed25519.PrivateKey
-	publicKey  ed25519.PublicKey
+	method     jwt.SigningMethod
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
 	keyID      string
+
+	// retired are the public keys of previous signing keys, by key ID: they
+	// still verify what was signed before a rotation (e.g. audit checkpoints)
+	retired map[string]verificationKey
+}
+
+type verificationKey struct {
+	method    jwt.SigningMethod
+	publicKey crypto.PublicKey
 }
 
-// NewTokenService creates a TokenService by fetching the Ed25519 key from Vault KV.
-func NewTokenService(ctx context.Context) (*TokenService, error) {
+// NewTokenService creates a TokenService by fetching the signing key from Vault KV
+// through the shared client. The algorithm comes from the "jwt" configuration
+// and defaults to EdDSA.
+func NewTokenService(ctx context.Context, vault *VaultClient) (*TokenService, error) {
 	w := wool.Get(ctx).In("NewTokenService")
 
-	vaultAddr, err := codefly.For(ctx).Service("vault").Configuration("vault", "address")
+	algorithm := jwtAlgorithm(ctx)
+
+	// Fetch key from Vault KV v2
+	data, err := vault.request(ctx, http.MethodGet, "/v1/secret/data/jwt-signing-key", "")
 	if err != nil {
-		return nil, w.Wrapf(err, "failed to get vault address")
+		return nil, w.Wrapf(err, "cannot fetch JWT key from vault")
+	}
+	secret, _ := data["data"].(map[string]interface{})
+	encoded, _ := secret["private_key"].(string)
+	if encoded == "" {
+		return nil, w.NewError("no private_key in vault secret jwt-signing-key")
 	}
-	vaultToken, err := codefly.For(ctx).Service("vault").Secret("vault", "token")
+
+	privateKey, err := ParseSigningKey(algorithm, encoded)
 	if err != nil {
-		return nil, w.Wrapf(err, "failed to get vault token")
+		return nil, w.Wrapf(err, "cannot parse %s signing key", algorithm)
 	}
 
-	// Fetch key from Vault KV v2
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, vaultAddr+"/v1/secret/data/jwt-signing-key", nil)
+	t, err := NewTokenServiceFromKey(algorithm, privateKey)
 	if err != nil {
-		return nil, w.Wrapf(err, "cannot create vault request")
+		return nil, w.Wrapf(err, "cannot create token service")
+	}
+
+	// Public keys of the keys rotated out, as PEM blocks
+	if previous, _ := secret["previous_public_keys"].(string); previous != "" {
+		if err := t.RetainPublicKeys(previous); err != nil {
+			return nil, w.Wrapf(err, "cannot load previous JWT keys")
+		}
+	}
+
+	w.Debug("JWT token service initialized",
+		wool.Field("keyID", t.keyID), wool.Field("algorithm", algorithm))
+
+	return t, nil
+}
+
+// jwtAlgorithm reads the "jwt" algorithm configuration, EdDSA by default.
+func jwtAlgorithm(ctx context.Context) string {
+	algorithm, err := codefly.For(ctx).Configuration("jwt", "algorithm")
+	if err != nil || algorithm == "" {
+		return AlgEdDSA
 	}
-	req.Header.Set("X-Vault-Token", vaultToken)
+	return algorithm
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// NewTokenServiceFromKey creates a TokenService from an already-loaded private key.
+// The key type must match the algorithm: Ed25519 for EdDSA, P-256 ECDSA for ES256,
+// RSA (at least 2048 bits) for RS256.
+func NewTokenServiceFromKey(algorithm string, privateKey crypto.Signer) (*TokenService, error) {
+	var method jwt.SigningMethod
+	switch algorithm {
+	case AlgEdDSA:
+		if _, ok := privateKey.(ed25519.PrivateKey); !ok {
+			return nil, fmt.Errorf("EdDSA requires an Ed25519 key, got %T", privateKey)
+		}
+		method = jwt.SigningMethodEdDSA
+	case AlgES256:
+		k, ok := privateKey.(*ecdsa.PrivateKey)
+		if !ok || k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ES256 requires a P-256 ECDSA key, got %T", privateKey)
+		}
+		method = jwt.SigningMethodES256
+	case AlgRS256:
+		k, ok := privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("RS256 requires an RSA key, got %T", privateKey)
+		}
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RS256 requires an RSA key of at least 2048 bits, got %d", k.N.BitLen())
+		}
+		method = jwt.SigningMethodRS256
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+
+	publicKey := privateKey.Public()
+	keyID, err := keyIDFor(publicKey)
 	if err != nil {
-		return nil, w.Wrapf(err, "cannot fetch JWT key from vault")
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return nil, w.NewError("vault returned %d fetching JWT key: %s", resp.StatusCode, string(body))
+	return &TokenService{
+		method:     method,
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		keyID:      keyID,
+	}, nil
+}
+
+// ParseSigningKey decodes a private key as stored in Vault.
+// EdDSA keys are a base64 32-byte seed. ES256 and RS256 keys are PEM or
+// base64 DER, in PKCS#8 or the algorithm's native format (SEC 1 / PKCS#1).
+func ParseSigningKey(algorithm string, encoded string) (crypto.Signer, error) {
+	if algorithm == AlgEdDSA {
+		seed, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode private key: %w", err)
+		}
+		if len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("ed25519 seed must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
 	}
 
-	var envelope struct {
-		Data struct {
-			Data struct {
-				PrivateKey string `json:"private_key"`
-				PublicKey  string `json:"public_key"`
-			} `json:"data"`
-		} `json:"data"`
+	der := []byte(encoded)
+	if block, _ := pem.Decode(der); block != nil {
+		der = block.Bytes
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode private key: %w", err)
+		}
+		der = decoded
 	}
-	if err := json.Unmarshal(body, &envelope); err != nil {
-		return nil, w.Wrapf(err, "cannot parse vault response")
+
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported PKCS#8 key type %T", key)
+		}
+		return signer, nil
+	}
+	switch algorithm {
+	case AlgES256:
+		return x509.ParseECPrivateKey(der)
+	case AlgRS256:
+		return x509.ParsePKCS1PrivateKey(der)
 	}
+	return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+}
 
-	seed, err := base64.StdEncoding.DecodeString(envelope.Data.Data.PrivateKey)
+// GenerateSigningKey creates a private key for the algorithm, encoded as
+// ParseSigningKey reads it: a base64 seed for EdDSA, PKCS#8 PEM otherwise.
+func GenerateSigningKey(algorithm string) (string, error) {
+	var key crypto.Signer
+	var err error
+	switch algorithm {
+	case AlgEdDSA:
+		seed := make([]byte, ed25519.SeedSize)
+		if _, err := rand.Read(seed); err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(seed), nil
+	case AlgES256:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgRS256:
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		return "", fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
 	if err != nil {
-		return nil, w.Wrapf(err, "cannot decode private key")
+		return "", err
 	}
-	pub, err := base64.StdEncoding.DecodeString(envelope.Data.Data.PublicKey)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
 	if err != nil {
-		return nil, w.Wrapf(err, "cannot decode public key")
+		return "", err
 	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
 
-	privateKey := ed25519.NewKeyFromSeed(seed)
-	publicKey := ed25519.PublicKey(pub)
+// RetainPublicKeys keeps the public keys of previous signing keys, given as
+// PEM "PUBLIC KEY" blocks, to verify what they signed and publish them in
+// the JWKS. Their algorithm follows from the key type.
+func (t *TokenService) RetainPublicKeys(encoded string) error {
+	rest := []byte(encoded)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("cannot parse public key: %w", err)
+		}
+		if err := t.RetainKey(publicKey); err != nil {
+			return err
+		}
+	}
+}
 
-	// Key ID = first 8 bytes of SHA-256 of public key, base64url
-	h := sha256.Sum256(publicKey)
-	keyID := base64.RawURLEncoding.EncodeToString(h[:8])
+// RetainKey keeps the public key of a previous signing key.
+func (t *TokenService) RetainKey(publicKey crypto.PublicKey) error {
+	var method jwt.SigningMethod
+	switch k := publicKey.(type) {
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return fmt.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+		}
+		method = jwt.SigningMethodES256
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	keyID, err := keyIDFor(publicKey)
+	if err != nil {
+		return err
+	}
+	if keyID == t.keyID {
+		return nil
+	}
+	if t.retired == nil {
+		t.retired = make(map[string]verificationKey)
+	}
+	t.retired[keyID] = verificationKey{method: method, publicKey: publicKey}
+	return nil
+}
 
-	w.Debug("JWT token service initialized", wool.Field("keyID", keyID))
+// verificationKey picks the key a token was signed with by its kid: the
+// current key, or a retired one. Tokens without a kid predate key IDs and
+// were signed with the current key.
+func (t *TokenService) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key := verificationKey{method: t.method, publicKey: t.publicKey}
+	if kid != "" && kid != t.keyID {
+		retired, ok := t.retired[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		key = retired
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.publicKey, nil
+}
 
-	return &TokenService{
-		privateKey: privateKey,
-		publicKey:  publicKey,
-		keyID:      keyID,
-	}, nil
+// keyIDFor derives the key ID: first 8 bytes of the SHA-256 of the public key, base64url.
+// Ed25519 hashes the raw key bytes so existing key IDs stay stable.
+func keyIDFor(publicKey crypto.PublicKey) (string, error) {
+	material, ok := publicKey.(ed25519.PublicKey)
+	if !ok {
+		der, err := x509.MarshalPKIXPublicKey(publicKey)
+		if err != nil {
+			return "", fmt.Errorf("cannot encode public key: %w", err)
+		}
+		material = der
+	}
+	h := sha256.Sum256(material)
+	return base64.RawURLEncoding.EncodeToString(h[:8]), nil
 }
 
 // SignAccessToken creates a signed JWT access token.
@@ -121,22 +334,17 @@ func (t *TokenService) SignAccessToken(userID, orgID string, roles []string) (st
 		Roles: roles,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token := jwt.NewWithClaims(t.method, claims)
 	token.Header["kid"] = t.keyID
 	return token.SignedString(t.privateKey)
 }
 
 // VerifyAccessToken parses and validates a JWT, returning the claims.
 func (t *TokenService) VerifyAccessToken(tokenString string) (*AccessClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return t.publicKey, nil
-	},
+	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, t.verificationKey,
 		jwt.WithIssuer(TokenIssuer),
 		jwt.WithExpirationRequired(),
-		jwt.WithValidMethods([]string{"EdDSA"}),
+		jwt.WithValidMethods(t.Algorithms()),
 	)
 	if err != nil {
 		return nil, err
@@ -149,6 +357,76 @@ func (t *TokenService) VerifyAccessToken(tokenString string) (*AccessClaims, err
 	return claims, nil
 }
 
+// AccessTokenID verifies an access token and returns its jti and expiry.
+func (t *TokenService) AccessTokenID(tokenString string) (string, time.Time, error) {
+	claims, err := t.VerifyAccessToken(tokenString)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if claims.ID == "" {
+		return "", time.Time{}, fmt.Errorf("token has no jti")
+	}
+	return claims.ID, claims.ExpiresAt.Time, nil
+}
+
+// auditCheckpointSubject is the subject of audit checkpoint JWTs.
+const auditCheckpointSubject = "audit-checkpoint"
+
+// AuditCheckpointClaims are the JWT claims of a signed audit checkpoint.
+// Checkpoints don't expire.
+type AuditCheckpointClaims struct {
+	jwt.RegisteredClaims
+	OrgID    string `json:"org"`
+	ChainSeq int64  `json:"seq"`
+	Hash     string `json:"hash"`
+}
+
+// SignAuditCheckpoint signs an audit chain head as a JWT.
+func (t *TokenService) SignAuditCheckpoint(cp business.AuditCheckpoint) (string, error) {
+	claims := AuditCheckpointClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:   TokenIssuer,
+			Subject:  auditCheckpointSubject,
+			IssuedAt: jwt.NewNumericDate(cp.CreatedAt),
+		},
+		OrgID:    cp.OrgID,
+		ChainSeq: cp.ChainSeq,
+		Hash:     cp.Hash,
+	}
+
+	token := jwt.NewWithClaims(t.method, claims)
+	token.Header["kid"] = t.keyID
+	return token.SignedString(t.privateKey)
+}
+
+// VerifyAuditCheckpoint verifies a checkpoint JWT and returns what it signs.
+// Checkpoints signed before a key rotation verify with the retained key.
+func (t *TokenService) VerifyAuditCheckpoint(signature string) (*business.AuditCheckpoint, error) {
+	token, err := jwt.ParseWithClaims(signature, &AuditCheckpointClaims{}, t.verificationKey,
+		jwt.WithIssuer(TokenIssuer),
+		jwt.WithSubject(auditCheckpointSubject),
+		jwt.WithValidMethods(t.Algorithms()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*AuditCheckpointClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid checkpoint claims")
+	}
+	cp := &business.AuditCheckpoint{
+		OrgID:     claims.OrgID,
+		ChainSeq:  claims.ChainSeq,
+		Hash:      claims.Hash,
+		Signature: signature,
+	}
+	if claims.IssuedAt != nil {
+		cp.CreatedAt = claims.IssuedAt.Time
+	}
+	return cp, nil
+}
+
 // GenerateRefreshToken creates a cryptographically random opaque token and its SHA-256 hash.
 func (t *TokenService) GenerateRefreshToken() (plaintext string, hash string, err error) {
 	raw := make([]byte, 32)
@@ -161,21 +439,25 @@ func (t *TokenService) GenerateRefreshToken() (plaintext string, hash string, er
 	return plaintext, hash, nil
 }
 
-// JWKS returns the JSON Web Key Set containing the public key.
+// JWKS returns the JSON Web Key Set containing the public key, and the
+// retained keys of previous rotations.
 func (t *TokenService) JWKS() (string, error) {
-	x := base64.RawURLEncoding.EncodeToString(t.publicKey)
+	key, err := jwk(t.keyID, t.method, t.publicKey)
+	if err != nil {
+		return "", err
+	}
+	keys := []map[string]any{key}
+	for _, kid := range slices.Sorted(maps.Keys(t.retired)) {
+		retired := t.retired[kid]
+		key, err := jwk(kid, retired.method, retired.publicKey)
+		if err != nil {
+			return "", err
+		}
+		keys = append(keys, key)
+	}
 
 	jwks := map[string]any{
-		"keys": []map[string]any{
-			{
-				"kty": "OKP",
-				"crv": "Ed25519",
-				"x":   x,
-				"kid": t.keyID,
-				"use": "sig",
-				"alg": "EdDSA",
-			},
-		},
+		"keys": keys,
 	}
 
 	data, err := json.Marshal(jwks)
@@ -185,8 +467,49 @@ func (t *TokenService) JWKS() (string, error) {
 	return string(data), nil
 }
 
-// PublicKey returns the Ed25519 public key for direct use (e.g., by sidecar).
-func (t *TokenService) PublicKey() ed25519.PublicKey {
+func jwk(kid string, method jwt.SigningMethod, publicKey crypto.PublicKey) (map[string]any, error) {
+	key := map[string]any{
+		"kid": kid,
+		"use": "sig",
+		"alg": method.Alg(),
+	}
+
+	switch pub := publicKey.(type) {
+	case ed25519.PublicKey:
+		key["kty"] = "OKP"
+		key["crv"] = "Ed25519"
+		key["x"] = base64.RawURLEncoding.EncodeToString(pub)
+	case *ecdsa.PublicKey:
+		// Coordinates are fixed-width, left-padded to the curve size (RFC 7518 §6.2.1.2)
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		key["kty"] = "EC"
+		key["crv"] = pub.Curve.Params().Name
+		key["x"] = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		key["y"] = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	case *rsa.PublicKey:
+		key["kty"] = "RSA"
+		key["n"] = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		key["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return key, nil
+}
+
+// Algorithms returns the JWT algorithms accepted by VerifyAccessToken: the
+// current key's, and the retained keys'.
+func (t *TokenService) Algorithms() []string {
+	algorithms := []string{t.method.Alg()}
+	for _, key := range t.retired {
+		if !slices.Contains(algorithms, key.method.Alg()) {
+			algorithms = append(algorithms, key.method.Alg())
+		}
+	}
+	return algorithms
+}
+
+// PublicKey returns the verification key for direct use (e.g., by sidecar).
+func (t *TokenService) PublicKey() crypto.PublicKey {
 	return t.publicKey
 }
 
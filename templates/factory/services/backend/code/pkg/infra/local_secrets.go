@@ -0,0 +1,270 @@
+package infra
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codefly-dev/core/wool"
+	codefly "github.com/codefly-dev/sdk-go"
+
+	"backend/pkg/business"
+)
+
+// localHMACScheme prefixes API key hashes made with the local HMAC key.
+const localHMACScheme = "local-hmac:v1"
+
+// hmacKeySize is the size of generated API key HMAC keys.
+const hmacKeySize = 32
+
+// LocalSecretsConfig configures LocalSecrets. Each key is taken from its
+// value (usually an environment variable), else from its file.
+type LocalSecretsConfig struct {
+	Algorithm string // JWT signing algorithm
+
+	JWTSigningKey       string // as read by ParseSigningKey
+	JWTSigningKeyFile   string
+	JWTPreviousKeys     string // PEM public keys of rotated-out signing keys
+	JWTPreviousKeysFile string
+	HMACKey             string // base64, at least 32 bytes
+	HMACKeyFile         string
+	PIIKey              string // base64, 32 bytes: wraps PII data keys
+	PIIKeyFile          string
+	PIIIndexKey         string // base64, at least 32 bytes: keys blind indexes
+	PIIIndexKeyFile     string
+
+	// Dev generates missing keys and persists them to their files
+	Dev bool
+}
+
+// LocalSecrets provides keys from files or environment variables, for local
+// development and installs without Vault.
+type LocalSecrets struct {
+	algorithm     string
+	jwtSigningKey string
+	jwtPrevious   string
+	hmacKey       []byte
+	piiKey        []byte
+	piiIndexKey   []byte
+}
+
+// NewLocalSecrets reads the "secrets" configuration: jwt_signing_key_file,
+// jwt_previous_keys_file, api_key_hmac_key_file, pii_key_file,
+// pii_index_key_file and dev ("true" to generate missing keys), with the keys
+// themselves in the jwt_signing_key, jwt_previous_keys, api_key_hmac_key,
+// pii_key and pii_index_key secrets. In dev mode, files
+// default to the "dir" configuration (.secrets).
+func NewLocalSecrets(ctx context.Context) (*LocalSecrets, error) {
+	config := LocalSecretsConfig{Algorithm: jwtAlgorithm(ctx)}
+	config.JWTSigningKey, _ = codefly.For(ctx).Secret("secrets", "jwt_signing_key")
+	config.HMACKey, _ = codefly.For(ctx).Secret("secrets", "api_key_hmac_key")
+	config.JWTSigningKeyFile, _ = codefly.For(ctx).Configuration("secrets", "jwt_signing_key_file")
+	config.JWTPreviousKeys, _ = codefly.For(ctx).Secret("secrets", "jwt_previous_keys")
+	config.JWTPreviousKeysFile, _ = codefly.For(ctx).Configuration("secrets", "jwt_previous_keys_file")
+	config.HMACKeyFile, _ = codefly.For(ctx).Configuration("secrets", "api_key_hmac_key_file")
+	config.PIIKey, _ = codefly.For(ctx).Secret("secrets", "pii_key")
+	config.PIIIndexKey, _ = codefly.For(ctx).Secret("secrets", "pii_index_key")
+	config.PIIKeyFile, _ = codefly.For(ctx).Configuration("secrets", "pii_key_file")
+	config.PIIIndexKeyFile, _ = codefly.For(ctx).Configuration("secrets", "pii_index_key_file")
+	dev, _ := codefly.For(ctx).Configuration("secrets", "dev")
+	config.Dev = dev == "true"
+
+	if config.Dev {
+		dir, _ := codefly.For(ctx).Configuration("secrets", "dir")
+		if dir == "" {
+			dir = ".secrets"
+		}
+		if config.JWTSigningKeyFile == "" {
+			config.JWTSigningKeyFile = filepath.Join(dir, "jwt-signing-key")
+		}
+		if config.HMACKeyFile == "" {
+			config.HMACKeyFile = filepath.Join(dir, "api-key-hmac-key")
+		}
+		if config.PIIKeyFile == "" {
+			config.PIIKeyFile = filepath.Join(dir, "pii-key")
+		}
+		if config.PIIIndexKeyFile == "" {
+			config.PIIIndexKeyFile = filepath.Join(dir, "pii-index-key")
+		}
+	}
+	return NewLocalSecretsFromConfig(ctx, config)
+}
+
+// NewLocalSecretsFromConfig loads (or in dev mode, generates) both keys.
+func NewLocalSecretsFromConfig(ctx context.Context, config LocalSecretsConfig) (*LocalSecrets, error) {
+	w := wool.Get(ctx).In("NewLocalSecretsFromConfig")
+
+	if config.Algorithm == "" {
+		config.Algorithm = AlgEdDSA
+	}
+
+	jwtSigningKey, err := loadLocalSecret(ctx, config.JWTSigningKey, config.JWTSigningKeyFile, config.Dev, func() (string, error) {
+		return GenerateSigningKey(config.Algorithm)
+	})
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot load JWT signing key")
+	}
+	if _, err := ParseSigningKey(config.Algorithm, jwtSigningKey); err != nil {
+		return nil, w.Wrapf(err, "invalid %s signing key", config.Algorithm)
+	}
+
+	// Previous keys are optional and never generated
+	jwtPrevious := config.JWTPreviousKeys
+	if jwtPrevious == "" && config.JWTPreviousKeysFile != "" {
+		data, err := os.ReadFile(config.JWTPreviousKeysFile)
+		if err != nil {
+			return nil, w.Wrapf(err, "cannot read previous JWT keys")
+		}
+		jwtPrevious = string(data)
+	}
+
+	hmacKey, err := loadLocalKey(ctx, config.HMACKey, config.HMACKeyFile, config.Dev)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot load API key HMAC key")
+	}
+	piiKey, err := loadLocalKey(ctx, config.PIIKey, config.PIIKeyFile, config.Dev)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot load PII key")
+	}
+	piiIndexKey, err := loadLocalKey(ctx, config.PIIIndexKey, config.PIIIndexKeyFile, config.Dev)
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot load PII index key")
+	}
+
+	return &LocalSecrets{
+		algorithm:     config.Algorithm,
+		jwtSigningKey: jwtSigningKey,
+		jwtPrevious:   jwtPrevious,
+		hmacKey:       hmacKey,
+		piiKey:        piiKey,
+		piiIndexKey:   piiIndexKey,
+	}, nil
+}
+
+// loadLocalKey loads a base64 key of at least 32 bytes, generating one in dev mode.
+func loadLocalKey(ctx context.Context, value, path string, dev bool) ([]byte, error) {
+	w := wool.Get(ctx).In("loadLocalKey")
+
+	encoded, err := loadLocalSecret(ctx, value, path, dev, func() (string, error) {
+		key := make([]byte, hmacKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(key), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, w.Wrapf(err, "key must be base64")
+	}
+	if len(key) < hmacKeySize {
+		return nil, w.NewError("key must be at least %d bytes, got %d", hmacKeySize, len(key))
+	}
+	return key, nil
+}
+
+// loadLocalSecret returns value, else the content of path. In dev mode a
+// missing file is created with a generated secret, readable by the owner only.
+func loadLocalSecret(ctx context.Context, value, path string, dev bool, generate func() (string, error)) (string, error) {
+	w := wool.Get(ctx).In("loadLocalSecret")
+
+	if value != "" {
+		return value, nil
+	}
+	if path == "" {
+		return "", w.NewError("no value or file configured")
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) || !dev {
+		return "", w.Wrapf(err, "cannot read %s", path)
+	}
+
+	secret, err := generate()
+	if err != nil {
+		return "", w.Wrapf(err, "cannot generate secret")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", w.Wrapf(err, "cannot create %s", filepath.Dir(path))
+	}
+	if err := os.WriteFile(path, []byte(secret+"\n"), 0o600); err != nil {
+		return "", w.Wrapf(err, "cannot write %s", path)
+	}
+	w.Info("generated dev secret", wool.Field("path", path))
+	return secret, nil
+}
+
+// KeyHasher returns the HMAC-SHA256 hasher keyed with the local key.
+func (l *LocalSecrets) KeyHasher(_ context.Context) (business.KeyHasher, error) {
+	return &LocalKeyHasher{key: l.hmacKey}, nil
+}
+
+// TokenService signs with the local JWT signing key, and verifies with the
+// previous keys too.
+func (l *LocalSecrets) TokenService(_ context.Context) (*TokenService, error) {
+	privateKey, err := ParseSigningKey(l.algorithm, l.jwtSigningKey)
+	if err != nil {
+		return nil, err
+	}
+	t, err := NewTokenServiceFromKey(l.algorithm, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.RetainPublicKeys(l.jwtPrevious); err != nil {
+		return nil, fmt.Errorf("invalid previous JWT keys: %w", err)
+	}
+	return t, nil
+}
+
+// PIICipher wraps PII data keys with the local PII key.
+func (l *LocalSecrets) PIICipher(_ context.Context) (*PIICipher, error) {
+	wrapper, err := newLocalKeyWrapper(l.piiKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewPIICipher(wrapper, l.piiIndexKey)
+}
+
+// DegradedStartup is false: local keys are there at startup or not at all.
+func (l *LocalSecrets) DegradedStartup() bool {
+	return false
+}
+
+// Health is always fine once the keys are loaded.
+func (l *LocalSecrets) Health(_ context.Context) error {
+	return nil
+}
+
+// Close has nothing to release.
+func (l *LocalSecrets) Close() error {
+	return nil
+}
+
+// LocalKeyHasher hashes API keys with HMAC-SHA256 and a local key.
+type LocalKeyHasher struct {
+	key []byte
+}
+
+// HashKey returns "local-hmac:v1:" and the base64 HMAC.
+func (h *LocalKeyHasher) HashKey(_ context.Context, plaintext string) (string, error) {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(plaintext))
+	return localHMACScheme + ":" + base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// PreviousHashKeys only has the legacy SHA-256 scheme: keys hashed by Vault
+// can't be matched without it.
+func (h *LocalKeyHasher) PreviousHashKeys(_ context.Context, plaintext string) ([]string, error) {
+	return []string{legacySHA256Hash(plaintext)}, nil
+}
@@ -0,0 +1,81 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/codefly-dev/core/wool"
+	"github.com/redis/go-redis/v9"
+
+	"backend/pkg/business"
+)
+
+// NotificationStream is the Redis stream notifications are queued on. A
+// delivery worker (mailer, chat integration) consumes it with a consumer
+// group; each entry holds the kind and the JSON-encoded notification.
+const NotificationStream = "notifications"
+
+// notificationStreamMaxLen bounds the stream while no worker consumes it.
+const notificationStreamMaxLen = 100000
+
+// NotificationQueue queues notifications in the cache service for delivery.
+type NotificationQueue struct {
+	client *redis.Client
+}
+
+var _ business.Notifier = (*NotificationQueue)(nil)
+
+// NewNotificationQueue connects to the cache service's write endpoint.
+func NewNotificationQueue(ctx context.Context) (*NotificationQueue, error) {
+	client, err := connectCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewNotificationQueueFromClient(client), nil
+}
+
+// NewNotificationQueueFromClient wraps an existing Redis client.
+func NewNotificationQueueFromClient(client *redis.Client) *NotificationQueue {
+	return &NotificationQueue{client: client}
+}
+
+type notificationMessage struct {
+	Kind    string            `json:"kind"`
+	OrgID   string            `json:"org_id,omitempty"`
+	UserIDs []string          `json:"user_ids"`
+	Subject string            `json:"subject"`
+	Body    string            `json:"body"`
+	Data    map[string]string `json:"data,omitempty"`
+}
+
+// Notify queues n.
+func (q *NotificationQueue) Notify(ctx context.Context, n business.Notification) error {
+	w := wool.Get(ctx).In("NotificationQueue.Notify")
+
+	payload, err := json.Marshal(notificationMessage{
+		Kind:    n.Kind,
+		OrgID:   n.OrgID,
+		UserIDs: n.UserIDs,
+		Subject: n.Subject,
+		Body:    n.Body,
+		Data:    n.Data,
+	})
+	if err != nil {
+		return w.Wrapf(err, "cannot encode notification")
+	}
+	err = q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: NotificationStream,
+		MaxLen: notificationStreamMaxLen,
+		Approx: true,
+		Values: map[string]any{"kind": n.Kind, "notification": payload},
+	}).Err()
+	if err != nil {
+		return w.Wrapf(err, "cannot queue notification")
+	}
+	return nil
+}
+
+// Close closes the Redis client.
+func (q *NotificationQueue) Close() error {
+	return q.client.Close()
+}
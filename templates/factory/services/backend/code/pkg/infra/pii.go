@@ -0,0 +1,214 @@
+package infra
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// piiPrefix marks encrypted column values:
+//
+//	enc:v1:<base64 wrapped data key>:<base64 nonce and AES-256-GCM ciphertext>
+//
+// Values without it are plaintext rows not migrated yet (see cmd/encrypt-pii).
+const piiPrefix = "enc:v1:"
+
+// maxUnwrappedKeys bounds the cache of unwrapped data keys. There is one data
+// key per process lifetime, so the cache only grows with restarts.
+const maxUnwrappedKeys = 1024
+
+// KeyWrapper protects data keys with a key-encryption key that never leaves
+// it: Vault transit, or a local key.
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, key []byte) (string, error)
+	UnwrapKey(ctx context.Context, wrapped string) ([]byte, error)
+}
+
+// PIICipher encrypts PII columns with envelope encryption and computes their
+// blind indexes. Values are sealed under a data key generated on first use
+// and wrapped by the KeyWrapper; unwrapped keys are cached so the wrapper
+// (Vault) is called once per data key, not per row. The column name is
+// authenticated with each value so ciphertexts can't be swapped between
+// columns.
+type PIICipher struct {
+	wrapper  KeyWrapper
+	indexKey []byte
+
+	mu         sync.Mutex
+	dataKey    cipher.AEAD
+	wrappedKey string
+	unwrapped  map[string]cipher.AEAD
+}
+
+// NewPIICipher creates a cipher; indexKey keys the blind-index HMAC.
+func NewPIICipher(wrapper KeyWrapper, indexKey []byte) (*PIICipher, error) {
+	if len(indexKey) < hmacKeySize {
+		return nil, fmt.Errorf("blind index key must be at least %d bytes, got %d", hmacKeySize, len(indexKey))
+	}
+	return &PIICipher{wrapper: wrapper, indexKey: indexKey, unwrapped: map[string]cipher.AEAD{}}, nil
+}
+
+// Encrypt seals a column value.
+func (c *PIICipher) Encrypt(ctx context.Context, column, plaintext string) (string, error) {
+	aead, wrapped, err := c.currentKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(column))
+	return piiPrefix + base64.StdEncoding.EncodeToString([]byte(wrapped)) + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a column value. Plaintext values are returned as they are.
+func (c *PIICipher) Decrypt(ctx context.Context, column, value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, piiPrefix)
+	if !ok {
+		return value, nil
+	}
+	encodedKey, encodedSealed, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", fmt.Errorf("malformed encrypted %s", column)
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted %s: %w", column, err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encodedSealed)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted %s: %w", column, err)
+	}
+
+	aead, err := c.keyFor(ctx, string(wrapped))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted %s", column)
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(column))
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt %s: %w", column, err)
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex is the HMAC of the trimmed, lowercased value: equal emails in
+// any case share an index, so it backs exact and case-insensitive lookups and
+// unique indexes without revealing the value.
+func (c *PIICipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether a column value is already encrypted.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, piiPrefix)
+}
+
+func (c *PIICipher) currentKey(ctx context.Context) (cipher.AEAD, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dataKey != nil {
+		return c.dataKey, c.wrappedKey, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, "", err
+	}
+	wrapped, err := c.wrapper.WrapKey(ctx, key)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot wrap data key: %w", err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, "", err
+	}
+	c.dataKey, c.wrappedKey = aead, wrapped
+	c.unwrapped[wrapped] = aead
+	return aead, wrapped, nil
+}
+
+func (c *PIICipher) keyFor(ctx context.Context, wrapped string) (cipher.AEAD, error) {
+	c.mu.Lock()
+	aead, ok := c.unwrapped[wrapped]
+	c.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	key, err := c.wrapper.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unwrap data key: %w", err)
+	}
+	aead, err = newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.unwrapped) >= maxUnwrappedKeys {
+		c.unwrapped = map[string]cipher.AEAD{c.wrappedKey: c.dataKey}
+	}
+	c.unwrapped[wrapped] = aead
+	return aead, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// localKeyWrapper wraps data keys with a local AES-256-GCM key.
+type localKeyWrapper struct {
+	aead cipher.AEAD
+}
+
+const localWrapPrefix = "local:v1:"
+
+func newLocalKeyWrapper(key []byte) (*localKeyWrapper, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("PII key must be 32 bytes, got %d", len(key))
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &localKeyWrapper{aead: aead}, nil
+}
+
+func (l *localKeyWrapper) WrapKey(_ context.Context, key []byte) (string, error) {
+	nonce := make([]byte, l.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return localWrapPrefix + base64.StdEncoding.EncodeToString(l.aead.Seal(nonce, nonce, key, nil)), nil
+}
+
+func (l *localKeyWrapper) UnwrapKey(_ context.Context, wrapped string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(wrapped, localWrapPrefix)
+	if !ok {
+		return nil, fmt.Errorf("data key was not wrapped with the local PII key")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < l.aead.NonceSize() {
+		return nil, fmt.Errorf("malformed wrapped data key")
+	}
+	return l.aead.Open(nil, sealed[:l.aead.NonceSize()], sealed[l.aead.NonceSize():], nil)
+}
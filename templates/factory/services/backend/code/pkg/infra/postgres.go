@@ -27,6 +27,19 @@ type Close func()
 type PostgresStore struct {
 	Close
 	pool *pgxpool.Pool
+	pii  *PIICipher
+}
+
+// SetPIICipher sets the cipher for PII columns; writing users, identities
+// and invitations fails without one.
+func (s *PostgresStore) SetPIICipher(c *PIICipher) {
+	s.pii = c
+}
+
+// Pool is the underlying connection pool, for tests that set up rows the
+// store wouldn't write (e.g. plaintext PII from before encryption).
+func (s *PostgresStore) Pool() *pgxpool.Pool {
+	return s.pool
 }
 
 func NewPostgresStore(ctx context.Context) (*PostgresStore, error) {
@@ -54,7 +67,13 @@ func NewPostgresStore(ctx context.Context) (*PostgresStore, error) {
 
 var _ business.Store = (*PostgresStore)(nil)
 
+// RunInTransaction runs fn in a transaction, committed if fn succeeds. Inside
+// another RunInTransaction, fn joins the outer transaction.
 func (s *PostgresStore) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value("tx").(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
 	// Begin transaction
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -81,6 +100,33 @@ func (s *PostgresStore) RunInTransaction(ctx context.Context, fn func(ctx contex
 	return nil
 }
 
+// RunInSerializableTransaction runs fn in a serializable transaction. Inside
+// another serializable transaction, fn joins it; inside a weaker one it fails
+// rather than silently losing the isolation.
+func (s *PostgresStore) RunInSerializableTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value("tx").(pgx.Tx); ok {
+		if serializable, _ := ctx.Value("tx_serializable").(bool); !serializable {
+			return errors.New("serializable work cannot join a read committed transaction")
+		}
+		return fn(ctx)
+	}
+	return pgx.BeginTxFunc(ctx, s.pool, pgx.TxOptions{
+		IsoLevel: pgx.Serializable,
+	}, func(tx pgx.Tx) error {
+		ctx = context.WithValue(ctx, "tx", tx)
+		return fn(context.WithValue(ctx, "tx_serializable", true))
+	})
+}
+
+// Health pings the database. Without a PII cipher, the store can't write
+// users, identities or invitations.
+func (s *PostgresStore) Health(ctx context.Context) error {
+	if s.pii == nil {
+		return errNoPIICipher
+	}
+	return s.pool.Ping(ctx)
+}
+
 type QueryExecutor interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
@@ -111,7 +157,7 @@ func (s *PostgresStore) GetUserByIdentity(ctx context.Context, identity *gen.Use
 		createdAt time.Time
 		updatedAt time.Time
 		lastLogin *time.Time
-		profile   []byte // for JSONB
+		profile   []byte // encrypted JSON
 		status    string
 	)
 
@@ -142,10 +188,18 @@ func (s *PostgresStore) GetUserByIdentity(ctx context.Context, identity *gen.Use
 	// Parse status
 	user.Status = parseUserStatus(status)
 
-	// Parse profile JSONB
+	if user.PrimaryEmail, err = s.decryptPII(ctx, piiUserEmail, user.PrimaryEmail); err != nil {
+		return nil, w.Wrapf(err, "failed to decrypt email")
+	}
+
+	// Parse profile, a JSON object once decrypted
 	if len(profile) > 0 {
+		decrypted, err := s.decryptPII(ctx, piiUserProfile, string(profile))
+		if err != nil {
+			return nil, w.Wrapf(err, "failed to decrypt profile")
+		}
 		profileMap := make(map[string]string)
-		if err := json.Unmarshal(profile, &profileMap); err != nil {
+		if err := json.Unmarshal([]byte(decrypted), &profileMap); err != nil {
 			return nil, w.Wrapf(err, "failed to unmarshal profile")
 		}
 		user.Profile = profileMap
@@ -156,10 +210,7 @@ func (s *PostgresStore) GetUserByIdentity(ctx context.Context, identity *gen.Use
 func (s *PostgresStore) RegisterUser(ctx context.Context, user *gen.User, identity *gen.UserIdentity) error {
 	w := wool.Get(ctx).In("RegisterUser")
 
-	return pgx.BeginTxFunc(ctx, s.pool, pgx.TxOptions{
-		IsoLevel: pgx.Serializable,
-	}, func(tx pgx.Tx) error {
-		ctx = context.WithValue(ctx, "tx", tx)
+	return s.RunInSerializableTransaction(ctx, func(ctx context.Context) error {
 		executor := s.getQueryExecutor(ctx)
 
 		// First check if this identity already exists
@@ -183,12 +234,20 @@ func (s *PostgresStore) RegisterUser(ctx context.Context, user *gen.User, identi
 				identity.Provider, identity.ProviderId)
 		}
 
-		// If it's a new identity, check if email is already registered
+		email, emailIndex, err := s.encryptPII(ctx, piiUserEmail, user.PrimaryEmail)
+		if err != nil {
+			return w.Wrapf(err, "failed to encrypt email")
+		}
+
+		// If it's a new identity, check if email is already registered (by
+		// blind index, or in plaintext for rows not encrypted yet)
 		var existingEmailUserUUID string
 		err = executor.QueryRow(ctx, `
             SELECT uuid 
             FROM users 
-            WHERE primary_email = $1`,
+            WHERE primary_email_bidx = $1
+               OR (primary_email_bidx IS NULL AND LOWER(primary_email) = LOWER($2))`,
+			emailIndex,
 			user.PrimaryEmail,
 		).Scan(&existingEmailUserUUID)
 
@@ -208,19 +267,24 @@ func (s *PostgresStore) RegisterUser(ctx context.Context, user *gen.User, identi
 		if err != nil {
 			return w.Wrapf(err, "failed to marshal profile")
 		}
+		profile, _, err := s.encryptPII(ctx, piiUserProfile, string(profileJSON))
+		if err != nil {
+			return w.Wrapf(err, "failed to encrypt profile")
+		}
 
 		_, err = executor.Exec(ctx, `
             INSERT INTO users (
-                uuid, primary_email, created_at, updated_at, status,
+                uuid, primary_email, primary_email_bidx, created_at, updated_at, status,
                 profile, email_verified
             ) VALUES (
-                $1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, $3,
-                $4, $5
+                $1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, $4,
+                $5, $6
             )`,
 			user.Uuid,
-			user.PrimaryEmail,
+			email,
+			emailIndex,
 			userStatusToString(user.Status),
-			profileJSON,
+			profile,
 			identity.EmailVerified, // Use identity's email verification status
 		)
 		if err != nil {
@@ -233,19 +297,25 @@ func (s *PostgresStore) RegisterUser(ctx context.Context, user *gen.User, identi
 			return w.Wrapf(err, "failed to marshal provider data")
 		}
 
+		providerEmail, providerEmailIndex, err := s.encryptPII(ctx, piiIdentityEmail, identity.ProviderEmail)
+		if err != nil {
+			return w.Wrapf(err, "failed to encrypt provider email")
+		}
+
 		_, err = executor.Exec(ctx, `
             INSERT INTO user_identities (
-                uuid, user_uuid, provider, provider_id, provider_email,
+                uuid, user_uuid, provider, provider_id, provider_email, provider_email_bidx,
                 created_at, provider_data, email_verified
             ) VALUES (
-                $1, $2, $3, $4, $5,
-                CURRENT_TIMESTAMP, $6, $7
+                $1, $2, $3, $4, $5, $6,
+                CURRENT_TIMESTAMP, $7, $8
             )`,
 			identity.Uuid,
 			user.Uuid,
 			identity.Provider,
 			identity.ProviderId,
-			identity.ProviderEmail,
+			providerEmail,
+			providerEmailIndex,
 			providerDataJSON,
 			identity.EmailVerified,
 		)
@@ -260,10 +330,7 @@ func (s *PostgresStore) RegisterUser(ctx context.Context, user *gen.User, identi
 func (s *PostgresStore) LinkIdentity(ctx context.Context, userUUID string, identity *gen.UserIdentity) error {
 	w := wool.Get(ctx).In("LinkIdentity")
 
-	return pgx.BeginTxFunc(ctx, s.pool, pgx.TxOptions{
-		IsoLevel: pgx.Serializable,
-	}, func(tx pgx.Tx) error {
-		ctx = context.WithValue(ctx, "tx", tx)
+	return s.RunInSerializableTransaction(ctx, func(ctx context.Context) error {
 		executor := s.getQueryExecutor(ctx)
 
 		// Check if identity already exists
@@ -292,19 +359,25 @@ func (s *PostgresStore) LinkIdentity(ctx context.Context, userUUID string, ident
 			return w.Wrapf(err, "failed to marshal provider data")
 		}
 
+		providerEmail, providerEmailIndex, err := s.encryptPII(ctx, piiIdentityEmail, identity.ProviderEmail)
+		if err != nil {
+			return w.Wrapf(err, "failed to encrypt provider email")
+		}
+
 		_, err = executor.Exec(ctx, `
             INSERT INTO user_identities (
-                uuid, user_uuid, provider, provider_id, provider_email,
+                uuid, user_uuid, provider, provider_id, provider_email, provider_email_bidx,
                 created_at, provider_data, email_verified
             ) VALUES (
-                $1, $2, $3, $4, $5,
-                CURRENT_TIMESTAMP, $6, $7
+                $1, $2, $3, $4, $5, $6,
+                CURRENT_TIMESTAMP, $7, $8
             )`,
 			identity.Uuid,
 			userUUID,
 			identity.Provider,
 			identity.ProviderId,
-			identity.ProviderEmail,
+			providerEmail,
+			providerEmailIndex,
 			providerDataJSON,
 			identity.EmailVerified,
 		)
@@ -332,6 +405,7 @@ func (s *PostgresStore) ClearAll(ctx context.Context) error {
 		"DELETE FROM organizations",
 		"DELETE FROM user_identities",
 		"DELETE FROM users",
+		"DELETE FROM audit_outbox",
 	} {
 		_, _ = executor.Exec(ctx, stmt)
 	}
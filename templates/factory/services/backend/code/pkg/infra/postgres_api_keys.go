@@ -8,9 +8,15 @@ import (
 	"github.com/jackc/pgx/v5"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"backend/pkg/business"
 	"backend/pkg/gen"
 )
 
+// apiKeyColumns is the column list scanned by scanAPIKey.
+const apiKeyColumns = `id, organization_id, user_id, name, prefix, scopes, environment,
+		       created_at, expires_at, last_used_at, revoked_at, rotated_from, revoke_at,
+		       disabled_at, disabled_reason, last_used_ip, allowed_cidrs, allowed_origins`
+
 func (s *PostgresStore) CreateAPIKey(ctx context.Context, key *gen.APIKey, keyHash string) error {
 	q := s.getQueryExecutor(ctx)
 
@@ -25,67 +31,98 @@ func (s *PostgresStore) CreateAPIKey(ctx context.Context, key *gen.APIKey, keyHa
 		expiresAt = &t
 	}
 
+	var rotatedFrom *string
+	if key.RotatedFromId != "" {
+		rotatedFrom = &key.RotatedFromId
+	}
+
 	_, err = q.Exec(ctx, `
-		INSERT INTO api_keys (id, organization_id, user_id, name, prefix, key_hash, scopes, environment, expires_at, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		INSERT INTO api_keys (id, organization_id, user_id, name, prefix, key_hash, scopes, environment, expires_at, created_by, rotated_from,
+		                      allowed_cidrs, allowed_origins)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
 		key.Id, key.OrganizationId, key.UserId, key.Name, key.Prefix,
-		keyHash, scopes, apiKeyEnvToString(key.Environment), expiresAt, key.UserId)
+		keyHash, scopes, apiKeyEnvToString(key.Environment), expiresAt, key.UserId, rotatedFrom,
+		nonNilStrings(key.AllowedCidrs), nonNilStrings(key.AllowedOrigins))
 	return err
 }
 
-func (s *PostgresStore) GetAPIKeyByHash(ctx context.Context, keyHash string) (*gen.APIKey, error) {
+func (s *PostgresStore) GetAPIKey(ctx context.Context, keyID string) (*gen.APIKey, error) {
 	q := s.getQueryExecutor(ctx)
 
-	row := q.QueryRow(ctx, `
-		SELECT id, organization_id, user_id, name, prefix, scopes, environment,
-		       created_at, expires_at, last_used_at, revoked_at
-		FROM api_keys WHERE key_hash = $1`, keyHash)
+	row := q.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, keyID)
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return key, nil
+}
 
-	var key gen.APIKey
-	var scopesJSON []byte
-	var env string
-	var createdAt time.Time
-	var expiresAt, lastUsedAt, revokedAt *time.Time
+func (s *PostgresStore) GetAPIKeyByHash(ctx context.Context, keyHash string) (*gen.APIKey, error) {
+	q := s.getQueryExecutor(ctx)
 
-	err := row.Scan(&key.Id, &key.OrganizationId, &key.UserId, &key.Name, &key.Prefix,
-		&scopesJSON, &env, &createdAt, &expiresAt, &lastUsedAt, &revokedAt)
+	row := q.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`, keyHash)
+	key, err := scanAPIKey(row)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
+	return key, nil
+}
 
-	key.Environment = apiKeyEnvFromString(env)
-	key.CreatedAt = timestamppb.New(createdAt)
-	if expiresAt != nil {
-		key.ExpiresAt = timestamppb.New(*expiresAt)
-	}
-	if lastUsedAt != nil {
-		key.LastUsedAt = timestamppb.New(*lastUsedAt)
-	}
-	if revokedAt != nil {
-		key.RevokedAt = timestamppb.New(*revokedAt)
-	}
+// GetAPIKeyByHashes finds the key stored under any of keyHashes in one query,
+// preferring the earliest hash, and returns the hash that matched.
+func (s *PostgresStore) GetAPIKeyByHashes(ctx context.Context, keyHashes []string) (*gen.APIKey, string, error) {
+	q := s.getQueryExecutor(ctx)
 
-	var scopes []*gen.Permission
-	if err := json.Unmarshal(scopesJSON, &scopes); err == nil {
-		key.Scopes = scopes
+	var matched string
+	row := q.QueryRow(ctx, `
+		SELECT `+apiKeyColumns+`, key_hash
+		FROM api_keys
+		WHERE key_hash = ANY($1)
+		ORDER BY array_position($1, key_hash)
+		LIMIT 1`, keyHashes)
+	key, err := scanAPIKey(withHash{Row: row, hash: &matched})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, "", nil
+		}
+		return nil, "", err
 	}
+	return key, matched, nil
+}
 
-	return &key, nil
+// withHash scans the key_hash selected after the API key columns.
+type withHash struct {
+	pgx.Row
+	hash *string
 }
 
-func (s *PostgresStore) ListAPIKeys(ctx context.Context, orgID string, pageSize int32, pageToken string) ([]*gen.APIKey, string, error) {
+func (r withHash) Scan(dest ...any) error {
+	return r.Row.Scan(append(dest, r.hash)...)
+}
+
+func (s *PostgresStore) ListAPIKeys(ctx context.Context, orgID string, unusedSince *time.Time, pageSize int32, pageToken string) ([]*gen.APIKey, string, error) {
 	q := s.getQueryExecutor(ctx)
 
-	rows, err := q.Query(ctx, `
-		SELECT id, organization_id, user_id, name, prefix, scopes, environment,
-		       created_at, expires_at, last_used_at, revoked_at
+	query := `
+		SELECT ` + apiKeyColumns + `
 		FROM api_keys
-		WHERE organization_id = $1 AND revoked_at IS NULL
+		WHERE organization_id = $1 AND revoked_at IS NULL`
+	args := []any{orgID, pageSize}
+	if unusedSince != nil {
+		query += ` AND COALESCE(last_used_at, created_at) < $3`
+		args = append(args, *unusedSince)
+	}
+	query += `
 		ORDER BY created_at DESC
-		LIMIT $2`, orgID, pageSize)
+		LIMIT $2`
+
+	rows, err := q.Query(ctx, query, args...)
 	if err != nil {
 		return nil, "", err
 	}
@@ -93,38 +130,74 @@ func (s *PostgresStore) ListAPIKeys(ctx context.Context, orgID string, pageSize
 
 	var keys []*gen.APIKey
 	for rows.Next() {
-		var key gen.APIKey
-		var scopesJSON []byte
-		var env string
-		var createdAt time.Time
-		var expiresAt, lastUsedAt, revokedAt *time.Time
-
-		err := rows.Scan(&key.Id, &key.OrganizationId, &key.UserId, &key.Name, &key.Prefix,
-			&scopesJSON, &env, &createdAt, &expiresAt, &lastUsedAt, &revokedAt)
+		key, err := scanAPIKey(rows)
 		if err != nil {
 			return nil, "", err
 		}
+		keys = append(keys, key)
+	}
 
-		key.Environment = apiKeyEnvFromString(env)
-		key.CreatedAt = timestamppb.New(createdAt)
-		if expiresAt != nil {
-			key.ExpiresAt = timestamppb.New(*expiresAt)
-		}
-		if lastUsedAt != nil {
-			key.LastUsedAt = timestamppb.New(*lastUsedAt)
-		}
-		if revokedAt != nil {
-			key.RevokedAt = timestamppb.New(*revokedAt)
-		}
+	return keys, "", rows.Err()
+}
 
-		var scopes []*gen.Permission
-		if err := json.Unmarshal(scopesJSON, &scopes); err == nil {
-			key.Scopes = scopes
+// ListLiveAPIKeys returns every non-revoked key of an org, or of all orgs when
+// orgID is empty, disabled ones included.
+func (s *PostgresStore) ListLiveAPIKeys(ctx context.Context, orgID string) ([]*gen.APIKey, error) {
+	q := s.getQueryExecutor(ctx)
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE revoked_at IS NULL`
+	var args []any
+	if orgID != "" {
+		query += ` AND organization_id = $1`
+		args = append(args, orgID)
+	}
+	rows, err := q.Query(ctx, query+` ORDER BY organization_id, created_at`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*gen.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
 		}
-		keys = append(keys, &key)
+		keys = append(keys, key)
 	}
+	return keys, rows.Err()
+}
 
-	return keys, "", nil
+// SetAPIKeyDisabled disables a key with the given reason, or re-enables it
+// when reason is empty.
+func (s *PostgresStore) SetAPIKeyDisabled(ctx context.Context, keyID string, reason string) error {
+	q := s.getQueryExecutor(ctx)
+	if reason == "" {
+		_, err := q.Exec(ctx, `UPDATE api_keys SET disabled_at = NULL, disabled_reason = NULL WHERE id = $1`, keyID)
+		return err
+	}
+	_, err := q.Exec(ctx, `
+		UPDATE api_keys SET disabled_at = COALESCE(disabled_at, NOW()), disabled_reason = $2
+		WHERE id = $1`, keyID, reason)
+	return err
+}
+
+// UpdateAPIKey saves the name and network restrictions of a live key.
+func (s *PostgresStore) UpdateAPIKey(ctx context.Context, key *gen.APIKey) error {
+	q := s.getQueryExecutor(ctx)
+	_, err := q.Exec(ctx, `
+		UPDATE api_keys SET name = $2, allowed_cidrs = $3, allowed_origins = $4
+		WHERE id = $1 AND revoked_at IS NULL`,
+		key.Id, key.Name, nonNilStrings(key.AllowedCidrs), nonNilStrings(key.AllowedOrigins))
+	return err
+}
+
+// UpdateAPIKeyHash replaces a key's hash, unless another validation already
+// did (the old hash no longer matches).
+func (s *PostgresStore) UpdateAPIKeyHash(ctx context.Context, keyID, oldHash, newHash string) error {
+	q := s.getQueryExecutor(ctx)
+	_, err := q.Exec(ctx, `UPDATE api_keys SET key_hash = $3 WHERE id = $1 AND key_hash = $2`, keyID, oldHash, newHash)
+	return err
 }
 
 func (s *PostgresStore) RevokeAPIKey(ctx context.Context, keyID string) error {
@@ -133,12 +206,193 @@ func (s *PostgresStore) RevokeAPIKey(ctx context.Context, keyID string) error {
 	return err
 }
 
-func (s *PostgresStore) TouchAPIKeyUsage(ctx context.Context, keyID string, ip string) error {
+// ScheduleAPIKeyRevocation sets revoke_at on a live key that has no revocation
+// scheduled yet, and reports whether it did.
+func (s *PostgresStore) ScheduleAPIKeyRevocation(ctx context.Context, keyID string, at time.Time) (bool, error) {
+	q := s.getQueryExecutor(ctx)
+	tag, err := q.Exec(ctx, `
+		UPDATE api_keys SET revoke_at = $2
+		WHERE id = $1 AND revoked_at IS NULL AND revoke_at IS NULL`, keyID, at)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// RevokeDueAPIKeys revokes keys whose grace period is over and returns them
+// (ID and organization only).
+func (s *PostgresStore) RevokeDueAPIKeys(ctx context.Context) ([]*gen.APIKey, error) {
 	q := s.getQueryExecutor(ctx)
-	_, err := q.Exec(ctx, `UPDATE api_keys SET last_used_at = NOW(), last_used_ip = $2 WHERE id = $1`, keyID, ip)
+
+	rows, err := q.Query(ctx, `
+		UPDATE api_keys SET revoked_at = revoke_at
+		WHERE revoke_at <= NOW() AND revoked_at IS NULL
+		RETURNING id, organization_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*gen.APIKey
+	for rows.Next() {
+		var key gen.APIKey
+		if err := rows.Scan(&key.Id, &key.OrganizationId); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKeys revokes the org's keys among keyIDs and returns the IDs it revoked.
+func (s *PostgresStore) RevokeAPIKeys(ctx context.Context, orgID string, keyIDs []string) ([]string, error) {
+	q := s.getQueryExecutor(ctx)
+
+	rows, err := q.Query(ctx, `
+		UPDATE api_keys SET revoked_at = NOW()
+		WHERE organization_id = $1 AND id = ANY($2::uuid[]) AND revoked_at IS NULL
+		RETURNING id`, orgID, keyIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revoked []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		revoked = append(revoked, id)
+	}
+	return revoked, rows.Err()
+}
+
+// RecordAPIKeyUsage applies a batch of aggregated usage: last_used_at and
+// last_used_ip move forward only, daily counts are added. Usage of keys that
+// no longer exist is ignored.
+func (s *PostgresStore) RecordAPIKeyUsage(ctx context.Context, usage []business.APIKeyUsage) error {
+	q := s.getQueryExecutor(ctx)
+
+	ids := make([]string, len(usage))
+	days := make([]time.Time, len(usage))
+	counts := make([]int64, len(usage))
+	usedAt := make([]time.Time, len(usage))
+	ips := make([]string, len(usage))
+	for i, u := range usage {
+		ids[i], days[i], counts[i], usedAt[i], ips[i] = u.KeyID, u.Day, u.Requests, u.LastUsedAt, u.LastUsedIP
+	}
+
+	_, err := q.Exec(ctx, `
+		UPDATE api_keys k SET last_used_at = u.used_at, last_used_ip = NULLIF(u.ip, '')
+		FROM (
+			SELECT DISTINCT ON (id) id, used_at, ip
+			FROM unnest($1::uuid[], $2::timestamptz[], $3::text[]) AS t(id, used_at, ip)
+			ORDER BY id, used_at DESC
+		) u
+		WHERE k.id = u.id AND (k.last_used_at IS NULL OR k.last_used_at < u.used_at)`,
+		ids, usedAt, ips)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+		INSERT INTO api_key_usage_daily (api_key_id, day, request_count)
+		SELECT u.id, u.day, u.n
+		FROM unnest($1::uuid[], $2::date[], $3::bigint[]) AS u(id, day, n)
+		JOIN api_keys k ON k.id = u.id
+		ON CONFLICT (api_key_id, day)
+		DO UPDATE SET request_count = api_key_usage_daily.request_count + EXCLUDED.request_count`,
+		ids, days, counts)
 	return err
 }
 
+// GetAPIKeyDailyUsage returns the daily counts of the keys since a day, oldest first.
+func (s *PostgresStore) GetAPIKeyDailyUsage(ctx context.Context, keyIDs []string, since time.Time) (map[string][]*gen.APIKeyDailyUsage, error) {
+	q := s.getQueryExecutor(ctx)
+
+	rows, err := q.Query(ctx, `
+		SELECT api_key_id, day, request_count
+		FROM api_key_usage_daily
+		WHERE api_key_id = ANY($1::uuid[]) AND day >= $2::date
+		ORDER BY api_key_id, day`, keyIDs, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make(map[string][]*gen.APIKeyDailyUsage)
+	for rows.Next() {
+		var keyID string
+		var day time.Time
+		var n int64
+		if err := rows.Scan(&keyID, &day, &n); err != nil {
+			return nil, err
+		}
+		usage[keyID] = append(usage[keyID], &gen.APIKeyDailyUsage{Date: day.Format(time.DateOnly), Requests: n})
+	}
+	return usage, rows.Err()
+}
+
+// scanAPIKey reads a row selected with apiKeyColumns.
+func scanAPIKey(row pgx.Row) (*gen.APIKey, error) {
+	var key gen.APIKey
+	var scopesJSON []byte
+	var env string
+	var createdAt time.Time
+	var expiresAt, lastUsedAt, revokedAt, revokeAt, disabledAt *time.Time
+	var rotatedFrom, disabledReason, lastUsedIP *string
+
+	err := row.Scan(&key.Id, &key.OrganizationId, &key.UserId, &key.Name, &key.Prefix,
+		&scopesJSON, &env, &createdAt, &expiresAt, &lastUsedAt, &revokedAt, &rotatedFrom, &revokeAt,
+		&disabledAt, &disabledReason, &lastUsedIP, &key.AllowedCidrs, &key.AllowedOrigins)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Environment = apiKeyEnvFromString(env)
+	key.CreatedAt = timestamppb.New(createdAt)
+	if expiresAt != nil {
+		key.ExpiresAt = timestamppb.New(*expiresAt)
+	}
+	if lastUsedAt != nil {
+		key.LastUsedAt = timestamppb.New(*lastUsedAt)
+	}
+	if revokedAt != nil {
+		key.RevokedAt = timestamppb.New(*revokedAt)
+	}
+	if rotatedFrom != nil {
+		key.RotatedFromId = *rotatedFrom
+	}
+	if revokeAt != nil {
+		key.RevokeAt = timestamppb.New(*revokeAt)
+	}
+	if disabledAt != nil {
+		key.DisabledAt = timestamppb.New(*disabledAt)
+	}
+	if disabledReason != nil {
+		key.DisabledReason = *disabledReason
+	}
+	if lastUsedIP != nil {
+		key.LastUsedIp = *lastUsedIP
+	}
+
+	var scopes []*gen.Permission
+	if err := json.Unmarshal(scopesJSON, &scopes); err == nil {
+		key.Scopes = scopes
+	}
+
+	return &key, nil
+}
+
+// nonNilStrings keeps NOT NULL array columns from receiving NULL.
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
 func apiKeyEnvToString(env gen.APIKeyEnvironment) string {
 	switch env {
 	case gen.APIKeyEnvironment_API_KEY_ENVIRONMENT_TEST:
@@ -2,27 +2,34 @@ package infra
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"backend/pkg/business"
 	"backend/pkg/gen"
 )
 
-func (s *PostgresStore) InsertAuditEvent(ctx context.Context, entry business.AuditEntry) error {
+// EnqueueAuditEvent writes an event to the outbox, in the transaction of ctx
+// if there is one: the event is only recorded if that transaction commits.
+func (s *PostgresStore) EnqueueAuditEvent(ctx context.Context, entry business.AuditEntry) error {
 	q := s.getQueryExecutor(ctx)
 
 	metadata, err := json.Marshal(entry.Metadata)
-	if err != nil {
+	if err != nil || entry.Metadata == nil {
 		metadata = []byte("{}")
 	}
 
 	_, err = q.Exec(ctx, `
-		INSERT INTO audit_events (actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address)
+		INSERT INTO audit_outbox (actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
 		nilIfEmpty(entry.ActorID), entry.ActorType, entry.Action,
 		entry.Resource, nilIfEmpty(entry.ResourceID), nilIfEmpty(entry.OrgID),
@@ -30,48 +37,213 @@ func (s *PostgresStore) InsertAuditEvent(ctx context.Context, entry business.Aud
 	return err
 }
 
-func (s *PostgresStore) QueryAuditLog(ctx context.Context, orgID, actorID, action, resource, resourceID string,
-	from, to *time.Time, pageSize int32, pageToken string) ([]business.AuditEntry, string, int32, error) {
+// RelayAuditEvents moves up to limit due outbox rows into audit_events, in
+// order. When the batch can't be moved as a whole, rows are moved one by one
+// and those that fail stay in the outbox with an exponential backoff (capped
+// at an hour). It returns how many rows were moved and how many failed.
+func (s *PostgresStore) RelayAuditEvents(ctx context.Context, limit int) (int, int, error) {
+	var moved, failed int
+	err := s.RunInTransaction(ctx, func(ctx context.Context) error {
+		q := s.getQueryExecutor(ctx)
+
+		rows, err := q.Query(ctx, `
+			SELECT seq FROM audit_outbox
+			WHERE next_attempt_at <= now()
+			ORDER BY seq
+			LIMIT $1 FOR UPDATE SKIP LOCKED`, limit)
+		if err != nil {
+			return err
+		}
+		var seqs []int64
+		for rows.Next() {
+			var seq int64
+			if err := rows.Scan(&seq); err != nil {
+				rows.Close()
+				return err
+			}
+			seqs = append(seqs, seq)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(seqs) == 0 {
+			return nil
+		}
+
+		if err := s.moveAuditEvents(ctx, seqs); err == nil {
+			moved = len(seqs)
+			return nil
+		}
+		// Isolate the rows that can't be moved
+		for _, seq := range seqs {
+			moveErr := s.moveAuditEvents(ctx, []int64{seq})
+			if moveErr == nil {
+				moved++
+				continue
+			}
+			failed++
+			_, err := q.Exec(ctx, `
+				UPDATE audit_outbox
+				SET attempts = attempts + 1, last_error = $2,
+				    next_attempt_at = now() + LEAST(interval '1 second' * power(2, attempts), interval '1 hour')
+				WHERE seq = $1`, seq, moveErr.Error())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return moved, failed, nil
+}
+
+// moveAuditEvents appends outbox rows to their chains in audit_events and
+// deletes them, under a savepoint so a failure leaves the surrounding
+// transaction usable.
+func (s *PostgresStore) moveAuditEvents(ctx context.Context, seqs []int64) error {
 	q := s.getQueryExecutor(ctx)
 
-	var conditions []string
-	var args []any
-	argN := 1
+	if _, err := q.Exec(ctx, "SAVEPOINT audit_relay"); err != nil {
+		return err
+	}
+	if err := s.chainAuditEvents(ctx, seqs); err != nil {
+		if _, rollbackErr := q.Exec(ctx, "ROLLBACK TO SAVEPOINT audit_relay"); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	_, err := q.Exec(ctx, "RELEASE SAVEPOINT audit_relay")
+	return err
+}
+
+// nilChainID keys the chain of events without an org.
+const nilChainID = "00000000-0000-0000-0000-000000000000"
+
+// auditChainKey is the chain of an audit_events row, as indexed.
+const auditChainKey = "COALESCE(org_id, '" + nilChainID + "'::uuid)"
+
+func auditChainID(orgID string) string {
+	if orgID == "" {
+		return nilChainID
+	}
+	return orgID
+}
 
-	if orgID != "" {
-		conditions = append(conditions, fmt.Sprintf("org_id = $%d", argN))
-		args = append(args, orgID)
-		argN++
+type auditChainHead struct {
+	seq  int64
+	hash string
+}
+
+// chainAuditEvents hashes outbox rows onto the heads of their chains, which
+// stay locked until the transaction ends so relays append one at a time.
+func (s *PostgresStore) chainAuditEvents(ctx context.Context, seqs []int64) error {
+	q := s.getQueryExecutor(ctx)
+
+	rows, err := q.Query(ctx, `SELECT `+auditEventColumns+` FROM audit_outbox WHERE seq = ANY($1) ORDER BY seq`, seqs)
+	if err != nil {
+		return err
 	}
-	if actorID != "" {
-		conditions = append(conditions, fmt.Sprintf("actor_id = $%d", argN))
-		args = append(args, actorID)
-		argN++
+	var events []business.AuditEntry
+	for rows.Next() {
+		e, err := scanAuditEntry(rows, false)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		events = append(events, e)
 	}
-	if action != "" {
-		conditions = append(conditions, fmt.Sprintf("action = $%d", argN))
-		args = append(args, action)
-		argN++
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
 	}
-	if resource != "" {
-		conditions = append(conditions, fmt.Sprintf("resource = $%d", argN))
-		args = append(args, resource)
-		argN++
+
+	// Lock heads in a fixed order so concurrent relays don't deadlock
+	heads := map[string]*auditChainHead{}
+	for _, e := range events {
+		heads[auditChainID(e.OrgID)] = nil
+	}
+	chains := slices.Sorted(maps.Keys(heads))
+	for _, chain := range chains {
+		_, err := q.Exec(ctx, `
+			INSERT INTO audit_chain_heads (org_id, chain_seq, hash) VALUES ($1, 0, '')
+			ON CONFLICT (org_id) DO NOTHING`, chain)
+		if err != nil {
+			return err
+		}
+		head := &auditChainHead{}
+		err = q.QueryRow(ctx, `SELECT chain_seq, hash FROM audit_chain_heads WHERE org_id = $1 FOR UPDATE`,
+			chain).Scan(&head.seq, &head.hash)
+		if err != nil {
+			return err
+		}
+		heads[chain] = head
 	}
-	if resourceID != "" {
-		conditions = append(conditions, fmt.Sprintf("resource_id = $%d", argN))
-		args = append(args, resourceID)
-		argN++
+
+	for _, e := range events {
+		head := heads[auditChainID(e.OrgID)]
+		e.ChainSeq, e.PrevHash = head.seq+1, head.hash
+		e.Hash = business.AuditEventHash(e)
+
+		metadata, err := json.Marshal(e.Metadata)
+		if err != nil || e.Metadata == nil {
+			metadata = []byte("{}")
+		}
+		_, err = q.Exec(ctx, `
+			INSERT INTO audit_events (id, actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address,
+				created_at, chain_seq, prev_hash, hash)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+			e.ID, nilIfEmpty(e.ActorID), e.ActorType, e.Action,
+			e.Resource, nilIfEmpty(e.ResourceID), nilIfEmpty(e.OrgID),
+			metadata, nilIfEmpty(e.IPAddress), e.CreatedAt, e.ChainSeq, e.PrevHash, e.Hash)
+		if err != nil {
+			return err
+		}
+		head.seq, head.hash = e.ChainSeq, e.Hash
 	}
-	if from != nil {
-		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argN))
-		args = append(args, *from)
-		argN++
+
+	for _, chain := range chains {
+		_, err := q.Exec(ctx, `
+			UPDATE audit_chain_heads SET chain_seq = $2, hash = $3, updated_at = now()
+			WHERE org_id = $1`, chain, heads[chain].seq, heads[chain].hash)
+		if err != nil {
+			return err
+		}
 	}
-	if to != nil {
-		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", argN))
-		args = append(args, *to)
-		argN++
+
+	_, err = q.Exec(ctx, "DELETE FROM audit_outbox WHERE seq = ANY($1)", seqs)
+	return err
+}
+
+// CountAuditOutbox returns how many events wait in the outbox, and how many
+// of them failed to move at least once.
+func (s *PostgresStore) CountAuditOutbox(ctx context.Context) (int64, int64, error) {
+	q := s.getQueryExecutor(ctx)
+
+	var pending, failing int64
+	err := q.QueryRow(ctx, `
+		SELECT count(*), count(*) FILTER (WHERE attempts > 0) FROM audit_outbox`).Scan(&pending, &failing)
+	return pending, failing, err
+}
+
+// QueryAuditLog returns events newest first. Page tokens hold the position of
+// the last event returned, so paging runs across the monthly partitions.
+func (s *PostgresStore) QueryAuditLog(ctx context.Context, orgID, actorID, action, resource, resourceID string,
+	from, to *time.Time, pageSize int32, pageToken string) ([]business.AuditEntry, string, int32, error) {
+	q := s.getQueryExecutor(ctx)
+
+	conditions, args := auditFilterConditions(business.AuditFilter{
+		OrgID: orgID, ActorID: actorID, Action: action, Resource: resource, ResourceID: resourceID, From: from, To: to,
+	})
+	if pageToken != "" {
+		createdAt, id, err := parseAuditPageToken(pageToken)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		args = append(args, createdAt, id)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
 	}
 
 	where := ""
@@ -83,9 +255,10 @@ func (s *PostgresStore) QueryAuditLog(ctx context.Context, orgID, actorID, actio
 		pageSize = 50
 	}
 
-	query := fmt.Sprintf(`SELECT id, actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address, created_at
-		FROM audit_events %s ORDER BY created_at DESC LIMIT $%d`, where, argN)
-	args = append(args, pageSize)
+	// One more event tells whether there is a next page
+	query := fmt.Sprintf(`SELECT `+auditEventColumns+auditChainColumns+`
+		FROM audit_events %s ORDER BY created_at DESC, id DESC LIMIT $%d`, where, len(args)+1)
+	args = append(args, pageSize+1)
 
 	rows, err := q.Query(ctx, query, args...)
 	if err != nil {
@@ -95,36 +268,272 @@ func (s *PostgresStore) QueryAuditLog(ctx context.Context, orgID, actorID, actio
 
 	var events []business.AuditEntry
 	for rows.Next() {
-		var e business.AuditEntry
-		var metadataJSON []byte
-		var actorID, resourceID, orgID, ipAddress *string
-
-		err := rows.Scan(&e.ID, &actorID, &e.ActorType, &e.Action, &e.Resource,
-			&resourceID, &orgID, &metadataJSON, &ipAddress, &e.CreatedAt)
+		e, err := scanAuditEntry(rows, true)
 		if err != nil {
 			return nil, "", 0, err
 		}
-		if actorID != nil {
-			e.ActorID = *actorID
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", 0, err
+	}
+
+	nextToken := ""
+	if len(events) > int(pageSize) {
+		events = events[:pageSize]
+		nextToken = auditPageToken(events[len(events)-1])
+	}
+	return events, nextToken, int32(len(events)), nil
+}
+
+// auditPageToken is the position of an event in QueryAuditLog's order.
+func auditPageToken(e business.AuditEntry) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(e.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + e.ID))
+}
+
+func parseAuditPageToken(token string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, "", errors.New("invalid page token")
+	}
+	encodedTime, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, "", errors.New("invalid page token")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, encodedTime)
+	if err != nil {
+		return time.Time{}, "", errors.New("invalid page token")
+	}
+	return createdAt, id, nil
+}
+
+// ListAuditEventsAfter lists events matching the filter, oldest first,
+// resuming after the given event (keyset on created_at and id).
+func (s *PostgresStore) ListAuditEventsAfter(ctx context.Context, filter business.AuditFilter, after *business.AuditEntry, limit int) ([]business.AuditEntry, error) {
+	q := s.getQueryExecutor(ctx)
+
+	conditions, args := auditFilterConditions(filter)
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) > ($%d, $%d)", len(args)+1, len(args)+2))
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`SELECT `+auditEventColumns+auditChainColumns+`
+		FROM audit_events %s ORDER BY created_at, id LIMIT $%d`, where, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []business.AuditEntry
+	for rows.Next() {
+		e, err := scanAuditEntry(rows, true)
+		if err != nil {
+			return nil, err
 		}
-		if resourceID != nil {
-			e.ResourceID = *resourceID
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// auditFilterConditions are the WHERE conditions for a filter, with their
+// arguments numbered from $1.
+func auditFilterConditions(f business.AuditFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if f.OrgID != "" {
+		add("org_id = $%d", f.OrgID)
+	}
+	if f.ActorID != "" {
+		add("actor_id = $%d", f.ActorID)
+	}
+	if f.Action != "" {
+		add("action = $%d", f.Action)
+	}
+	if f.Resource != "" {
+		add("resource = $%d", f.Resource)
+	}
+	if f.ResourceID != "" {
+		add("resource_id = $%d", f.ResourceID)
+	}
+	if f.From != nil {
+		add("created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("created_at <= $%d", *f.To)
+	}
+	return conditions, args
+}
+
+// auditEventColumns are the columns scanned by scanAuditEntry, in
+// audit_events and audit_outbox.
+const auditEventColumns = `id, actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address, created_at`
+
+// auditChainColumns follow auditEventColumns for chained audit_events.
+const auditChainColumns = `, COALESCE(chain_seq, 0), COALESCE(prev_hash, ''), COALESCE(hash, '')`
+
+func scanAuditEntry(row pgx.Row, chained bool) (business.AuditEntry, error) {
+	var e business.AuditEntry
+	var metadataJSON []byte
+	var actorID, resourceID, orgID, ipAddress *string
+
+	dest := []any{&e.ID, &actorID, &e.ActorType, &e.Action, &e.Resource,
+		&resourceID, &orgID, &metadataJSON, &ipAddress, &e.CreatedAt}
+	if chained {
+		dest = append(dest, &e.ChainSeq, &e.PrevHash, &e.Hash)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return e, err
+	}
+	if actorID != nil {
+		e.ActorID = *actorID
+	}
+	if resourceID != nil {
+		e.ResourceID = *resourceID
+	}
+	if orgID != nil {
+		e.OrgID = *orgID
+	}
+	if ipAddress != nil {
+		e.IPAddress = *ipAddress
+	}
+
+	var metadata map[string]string
+	if json.Unmarshal(metadataJSON, &metadata) == nil {
+		e.Metadata = metadata
+	}
+	return e, nil
+}
+
+// AuditChainRange returns the first and last chain_seq of the org's events
+// recorded between from and to, or zeros when there are none.
+func (s *PostgresStore) AuditChainRange(ctx context.Context, orgID string, from, to *time.Time) (int64, int64, error) {
+	q := s.getQueryExecutor(ctx)
+
+	var first, last int64
+	err := q.QueryRow(ctx, `
+		SELECT COALESCE(min(chain_seq), 0), COALESCE(max(chain_seq), 0) FROM audit_events
+		WHERE `+auditChainKey+` = $1 AND chain_seq IS NOT NULL
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at <= $3)`,
+		auditChainID(orgID), from, to).Scan(&first, &last)
+	return first, last, err
+}
+
+// ListAuditChain returns up to limit events of the org's chain after afterSeq,
+// in chain order.
+func (s *PostgresStore) ListAuditChain(ctx context.Context, orgID string, afterSeq int64, limit int) ([]business.AuditEntry, error) {
+	q := s.getQueryExecutor(ctx)
+
+	rows, err := q.Query(ctx, `SELECT `+auditEventColumns+auditChainColumns+` FROM audit_events
+		WHERE `+auditChainKey+` = $1 AND chain_seq > $2
+		ORDER BY chain_seq LIMIT $3`, auditChainID(orgID), afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []business.AuditEntry
+	for rows.Next() {
+		e, err := scanAuditEntry(rows, true)
+		if err != nil {
+			return nil, err
 		}
-		if orgID != nil {
-			e.OrgID = *orgID
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// GetAuditChainHead returns the last chain_seq and hash of the org's chain,
+// or zeros before its first event.
+func (s *PostgresStore) GetAuditChainHead(ctx context.Context, orgID string) (int64, string, error) {
+	q := s.getQueryExecutor(ctx)
+
+	var seq int64
+	var hash string
+	err := q.QueryRow(ctx, `SELECT chain_seq, hash FROM audit_chain_heads WHERE org_id = $1`,
+		auditChainID(orgID)).Scan(&seq, &hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", nil
+	}
+	return seq, hash, err
+}
+
+// ListUncheckpointedAuditChains returns, as unsigned checkpoints, the heads
+// of chains that grew since their last checkpoint.
+func (s *PostgresStore) ListUncheckpointedAuditChains(ctx context.Context) ([]*business.AuditCheckpoint, error) {
+	q := s.getQueryExecutor(ctx)
+
+	rows, err := q.Query(ctx, `
+		SELECT h.org_id, h.chain_seq, h.hash FROM audit_chain_heads h
+		WHERE h.chain_seq > COALESCE((SELECT max(c.chain_seq) FROM audit_checkpoints c WHERE c.org_id = h.org_id), 0)
+		ORDER BY h.org_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var heads []*business.AuditCheckpoint
+	for rows.Next() {
+		cp := &business.AuditCheckpoint{}
+		if err := rows.Scan(&cp.OrgID, &cp.ChainSeq, &cp.Hash); err != nil {
+			return nil, err
 		}
-		if ipAddress != nil {
-			e.IPAddress = *ipAddress
+		if cp.OrgID == nilChainID {
+			cp.OrgID = ""
 		}
+		heads = append(heads, cp)
+	}
+	return heads, rows.Err()
+}
 
-		var metadata map[string]string
-		if json.Unmarshal(metadataJSON, &metadata) == nil {
-			e.Metadata = metadata
-		}
-		events = append(events, e)
+// CreateAuditCheckpoint stores a signed checkpoint. A checkpoint of the same
+// head made concurrently is kept instead.
+func (s *PostgresStore) CreateAuditCheckpoint(ctx context.Context, cp *business.AuditCheckpoint) error {
+	q := s.getQueryExecutor(ctx)
+
+	_, err := q.Exec(ctx, `
+		INSERT INTO audit_checkpoints (id, org_id, chain_seq, hash, signature, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (org_id, chain_seq) DO NOTHING`,
+		cp.ID, auditChainID(cp.OrgID), cp.ChainSeq, cp.Hash, cp.Signature, cp.CreatedAt)
+	return err
+}
+
+// ListAuditCheckpoints returns the org's checkpoints between two chain_seq,
+// inclusive, in chain order.
+func (s *PostgresStore) ListAuditCheckpoints(ctx context.Context, orgID string, fromSeq, toSeq int64) ([]*business.AuditCheckpoint, error) {
+	q := s.getQueryExecutor(ctx)
+
+	rows, err := q.Query(ctx, `
+		SELECT id, chain_seq, hash, signature, created_at FROM audit_checkpoints
+		WHERE org_id = $1 AND chain_seq BETWEEN $2 AND $3
+		ORDER BY chain_seq`, auditChainID(orgID), fromSeq, toSeq)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return events, "", int32(len(events)), nil
+	var checkpoints []*business.AuditCheckpoint
+	for rows.Next() {
+		cp := &business.AuditCheckpoint{OrgID: orgID}
+		if err := rows.Scan(&cp.ID, &cp.ChainSeq, &cp.Hash, &cp.Signature, &cp.CreatedAt); err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	return checkpoints, rows.Err()
 }
 
 // AuditEntryToProto converts a business AuditEntry to proto AuditEvent.
@@ -139,6 +548,9 @@ func AuditEntryToProto(e business.AuditEntry) *gen.AuditEvent {
 		OrgId:      e.OrgID,
 		IpAddress:  e.IPAddress,
 		CreatedAt:  timestamppb.New(e.CreatedAt),
+		ChainSeq:   e.ChainSeq,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
 	}
 	if e.Metadata != nil {
 		event.Metadata = e.Metadata
@@ -146,6 +558,18 @@ func AuditEntryToProto(e business.AuditEntry) *gen.AuditEvent {
 	return event
 }
 
+// AuditCheckpointToProto converts a business AuditCheckpoint to proto.
+func AuditCheckpointToProto(cp *business.AuditCheckpoint) *gen.AuditCheckpoint {
+	return &gen.AuditCheckpoint{
+		Id:        cp.ID,
+		OrgId:     cp.OrgID,
+		ChainSeq:  cp.ChainSeq,
+		Hash:      cp.Hash,
+		Signature: cp.Signature,
+		CreatedAt: timestamppb.New(cp.CreatedAt),
+	}
+}
+
 func nilIfEmpty(s string) *string {
 	if s == "" {
 		return nil
@@ -0,0 +1,158 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"backend/pkg/business"
+)
+
+// auditPartitionName matches the partitions audit_events_create_partition
+// creates (migration 22): audit_events_YYYY_MM.
+var auditPartitionName = regexp.MustCompile(`^audit_events_[0-9]{4}_[0-9]{2}$`)
+
+// auditPartitionTable is the quoted name of a partition, for statements that
+// cannot take it as a parameter.
+func auditPartitionTable(name string) (string, error) {
+	if !auditPartitionName.MatchString(name) {
+		return "", fmt.Errorf("%q is not an audit partition", name)
+	}
+	return pgx.Identifier{name}.Sanitize(), nil
+}
+
+// CreateAuditPartition creates the partition of the month of t, if missing.
+func (s *PostgresStore) CreateAuditPartition(ctx context.Context, month time.Time) (string, error) {
+	q := s.getQueryExecutor(ctx)
+
+	var name string
+	err := q.QueryRow(ctx, `SELECT audit_events_create_partition($1)`, month).Scan(&name)
+	return name, err
+}
+
+// ListAuditPartitions returns the monthly partitions, oldest first, with the
+// ones detached but not dropped yet.
+func (s *PostgresStore) ListAuditPartitions(ctx context.Context) ([]business.AuditPartition, error) {
+	q := s.getQueryExecutor(ctx)
+
+	rows, err := q.Query(ctx, `
+		SELECT c.relname, EXISTS (
+			SELECT 1 FROM pg_inherits i
+			WHERE i.inhrelid = c.oid AND i.inhparent = 'audit_events'::regclass)
+		FROM pg_class c
+		WHERE c.relkind = 'r' AND c.relnamespace = current_schema()::regnamespace
+		  AND c.relname ~ '^audit_events_[0-9]{4}_[0-9]{2}$'
+		ORDER BY c.relname`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partitions []business.AuditPartition
+	for rows.Next() {
+		var p business.AuditPartition
+		if err := rows.Scan(&p.Name, &p.Attached); err != nil {
+			return nil, err
+		}
+		p.Month, err = time.Parse("2006_01", strings.TrimPrefix(p.Name, "audit_events_"))
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, p)
+	}
+	return partitions, rows.Err()
+}
+
+// ListAuditPartitionOrgs returns the orgs with events in a partition; "" for
+// events without an org.
+func (s *PostgresStore) ListAuditPartitionOrgs(ctx context.Context, name string) ([]string, error) {
+	q := s.getQueryExecutor(ctx)
+
+	table, err := auditPartitionTable(name)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.Query(ctx, `SELECT DISTINCT COALESCE(org_id::text, '') FROM `+table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgIDs []string
+	for rows.Next() {
+		var orgID string
+		if err := rows.Scan(&orgID); err != nil {
+			return nil, err
+		}
+		orgIDs = append(orgIDs, orgID)
+	}
+	return orgIDs, rows.Err()
+}
+
+// DetachAuditPartition takes a partition out of audit_events: its events are
+// no longer queried, and it can be dropped.
+func (s *PostgresStore) DetachAuditPartition(ctx context.Context, name string) error {
+	q := s.getQueryExecutor(ctx)
+
+	table, err := auditPartitionTable(name)
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx, `ALTER TABLE audit_events DETACH PARTITION `+table)
+	return err
+}
+
+// ScanAuditPartition calls fn with each event of a partition, oldest first,
+// as they are read.
+func (s *PostgresStore) ScanAuditPartition(ctx context.Context, name string, fn func(e business.AuditEntry) error) error {
+	q := s.getQueryExecutor(ctx)
+
+	table, err := auditPartitionTable(name)
+	if err != nil {
+		return err
+	}
+	rows, err := q.Query(ctx, `SELECT `+auditEventColumns+auditChainColumns+` FROM `+table+` ORDER BY created_at, id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanAuditEntry(rows, true)
+		if err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// DropAuditPartition drops a detached partition. Attached ones are refused,
+// so events are only dropped once archived.
+func (s *PostgresStore) DropAuditPartition(ctx context.Context, name string) error {
+	q := s.getQueryExecutor(ctx)
+
+	table, err := auditPartitionTable(name)
+	if err != nil {
+		return err
+	}
+	var attached bool
+	err = q.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_inherits
+			WHERE inhrelid = to_regclass($1) AND inhparent = 'audit_events'::regclass)`, table).Scan(&attached)
+	if err != nil {
+		return err
+	}
+	if attached {
+		return fmt.Errorf("audit partition %s is attached", name)
+	}
+	_, err = q.Exec(ctx, `DROP TABLE IF EXISTS `+table)
+	return err
+}
@@ -5,16 +5,38 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"backend/pkg/business"
 )
 
 func (s *PostgresStore) CreateInvitation(ctx context.Context, inv *business.Invitation) error {
 	q := s.getQueryExecutor(ctx)
-	_, err := q.Exec(ctx, `
-		INSERT INTO invitations (id, org_id, inviter_id, email, role, token_hash, status, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
-		inv.ID, inv.OrgID, inv.InviterID, inv.Email, inv.Role, inv.TokenHash, inv.Status, inv.ExpiresAt)
+	email, emailIndex, err := s.encryptPII(ctx, piiInvitationEmail, inv.Email)
+	if err != nil {
+		return err
+	}
+	// The blind index keeps encrypted emails unique; pending invitations not
+	// encrypted yet are checked in plaintext
+	if inv.Status == "pending" {
+		var exists bool
+		err = q.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM invitations
+				WHERE org_id = $1 AND status = 'pending' AND email_bidx IS NULL AND LOWER(email) = LOWER($2))`,
+			inv.OrgID, inv.Email).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return status.Errorf(codes.AlreadyExists, "a pending invitation for %s already exists", inv.Email)
+		}
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO invitations (id, org_id, inviter_id, email, email_bidx, role, token_hash, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		inv.ID, inv.OrgID, inv.InviterID, email, emailIndex, inv.Role, inv.TokenHash, inv.Status, inv.ExpiresAt)
 	return err
 }
 
@@ -36,6 +58,9 @@ func (s *PostgresStore) GetInvitationByTokenHash(ctx context.Context, hash strin
 		}
 		return nil, err
 	}
+	if inv.Email, err = s.decryptPII(ctx, piiInvitationEmail, inv.Email); err != nil {
+		return nil, err
+	}
 	if acceptedAt != nil {
 		inv.AcceptedAt = acceptedAt
 	}
@@ -72,6 +97,9 @@ func (s *PostgresStore) ListInvitations(ctx context.Context, orgID string, statu
 		if err != nil {
 			return nil, err
 		}
+		if inv.Email, err = s.decryptPII(ctx, piiInvitationEmail, inv.Email); err != nil {
+			return nil, err
+		}
 		invitations = append(invitations, &inv)
 	}
 	return invitations, nil
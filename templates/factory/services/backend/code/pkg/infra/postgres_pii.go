@@ -0,0 +1,138 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/codefly-dev/core/wool"
+)
+
+// piiColumn is an encrypted column, with its blind index if it is looked up.
+type piiColumn struct {
+	table  string
+	key    string
+	column string
+	index  string
+}
+
+// Encrypted columns. The "table.column" name is authenticated with each value.
+var (
+	piiUserEmail       = piiColumn{table: "users", key: "uuid", column: "primary_email", index: "primary_email_bidx"}
+	piiUserProfile     = piiColumn{table: "users", key: "uuid", column: "profile"}
+	piiIdentityEmail   = piiColumn{table: "user_identities", key: "uuid", column: "provider_email", index: "provider_email_bidx"}
+	piiInvitationEmail = piiColumn{table: "invitations", key: "id", column: "email", index: "email_bidx"}
+
+	piiColumns = []piiColumn{piiUserEmail, piiUserProfile, piiIdentityEmail, piiInvitationEmail}
+)
+
+func (c piiColumn) String() string {
+	return c.table + "." + c.column
+}
+
+var errNoPIICipher = errors.New("PII cipher not configured")
+
+// encryptPII encrypts a value for the column and computes its blind index
+// (empty when the column has none).
+func (s *PostgresStore) encryptPII(ctx context.Context, column piiColumn, value string) (string, string, error) {
+	if s.pii == nil {
+		return "", "", errNoPIICipher
+	}
+	encrypted, err := s.pii.Encrypt(ctx, column.String(), value)
+	if err != nil {
+		return "", "", err
+	}
+	index := ""
+	if column.index != "" {
+		index = s.pii.BlindIndex(value)
+	}
+	return encrypted, index, nil
+}
+
+// decryptPII decrypts a column value; plaintext from rows not encrypted yet
+// is returned as it is.
+func (s *PostgresStore) decryptPII(ctx context.Context, column piiColumn, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if s.pii == nil {
+		return "", errNoPIICipher
+	}
+	return s.pii.Decrypt(ctx, column.String(), value)
+}
+
+// EncryptPII encrypts plaintext PII left from before encryption, in batches
+// of batchSize rows per transaction, and fills their blind indexes. It can
+// run while the service is up and be resumed; it returns the number of
+// values encrypted per column.
+func (s *PostgresStore) EncryptPII(ctx context.Context, batchSize int) (map[string]int64, error) {
+	w := wool.Get(ctx).In("EncryptPII")
+
+	if s.pii == nil {
+		return nil, errNoPIICipher
+	}
+	counts := map[string]int64{}
+	for _, column := range piiColumns {
+		for {
+			var n int
+			err := s.RunInTransaction(ctx, func(ctx context.Context) error {
+				var err error
+				n, err = s.encryptPIIBatch(ctx, column, batchSize)
+				return err
+			})
+			if err != nil {
+				return counts, w.Wrapf(err, "cannot encrypt %s", column)
+			}
+			counts[column.String()] += int64(n)
+			if n < batchSize {
+				break
+			}
+		}
+		w.Info("encrypted PII column", wool.Field("column", column.String()), wool.Field("rows", counts[column.String()]))
+	}
+	return counts, nil
+}
+
+func (s *PostgresStore) encryptPIIBatch(ctx context.Context, column piiColumn, batchSize int) (int, error) {
+	q := s.getQueryExecutor(ctx)
+
+	rows, err := q.Query(ctx, fmt.Sprintf(`
+		SELECT %[1]s, %[2]s FROM %[3]s
+		WHERE %[2]s IS NOT NULL AND %[2]s NOT LIKE 'enc:v1:%%'
+		LIMIT $1 FOR UPDATE SKIP LOCKED`, column.key, column.column, column.table), batchSize)
+	if err != nil {
+		return 0, err
+	}
+	type plainRow struct{ key, value string }
+	var batch []plainRow
+	for rows.Next() {
+		var r plainRow
+		if err := rows.Scan(&r.key, &r.value); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, r := range batch {
+		encrypted, index, err := s.encryptPII(ctx, column, r.value)
+		if err != nil {
+			return 0, err
+		}
+		if column.index == "" {
+			_, err = q.Exec(ctx, fmt.Sprintf(`UPDATE %s SET %s = $2 WHERE %s = $1`,
+				column.table, column.column, column.key), r.key, encrypted)
+		} else {
+			_, err = q.Exec(ctx, fmt.Sprintf(`UPDATE %s SET %s = $2, %s = $3 WHERE %s = $1`,
+				column.table, column.column, column.index, column.key), r.key, encrypted, index)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(batch), nil
+}
@@ -0,0 +1,143 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/codefly-dev/core/wool"
+	codefly "github.com/codefly-dev/sdk-go"
+	"github.com/redis/go-redis/v9"
+
+	"backend/pkg/business"
+)
+
+// Redis keys and channel shared with the auth-sidecar.
+const (
+	RevokedTokenPrefix = "revoked:jti:"
+	RevokedUserPrefix  = "revoked:user:"
+	RevocationChannel  = "token-revocations"
+
+	// APIKeyRevocationChannel carries the IDs of revoked API keys.
+	APIKeyRevocationChannel = "api-key-revocations"
+)
+
+// Revocation is the message published on RevocationChannel so sidecars can
+// keep a local copy of the denylist for when Redis is unreachable.
+type Revocation struct {
+	TokenID   string `json:"jti,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+	Before    int64  `json:"before,omitempty"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// RevocationList stores revoked access tokens in the Redis cache service.
+// Entries expire once the tokens they cover can no longer be valid.
+// It also broadcasts API key revocations to sidecar validation caches.
+type RevocationList struct {
+	client *redis.Client
+}
+
+var (
+	_ business.TokenRevoker      = (*RevocationList)(nil)
+	_ business.APIKeyInvalidator = (*RevocationList)(nil)
+)
+
+// NewRevocationList connects to the cache service's write endpoint.
+func NewRevocationList(ctx context.Context) (*RevocationList, error) {
+	client, err := connectCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewRevocationListFromClient(client), nil
+}
+
+// connectCache opens a client on the cache service's write endpoint.
+func connectCache(ctx context.Context) (*redis.Client, error) {
+	w := wool.Get(ctx).In("connectCache")
+
+	connection, err := codefly.For(ctx).Service("cache").Secret("redis", "write")
+	if err != nil {
+		return nil, w.Wrapf(err, "failed to get cache connection string")
+	}
+	opts, err := redis.ParseURL(connection)
+	if err != nil {
+		return nil, w.Wrapf(err, "failed to parse cache connection string")
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, w.Wrapf(err, "cannot reach cache")
+	}
+	return client, nil
+}
+
+// NewRevocationListFromClient wraps an existing Redis client.
+func NewRevocationListFromClient(client *redis.Client) *RevocationList {
+	return &RevocationList{client: client}
+}
+
+// RevokeToken denylists a single access token by jti until it expires.
+func (r *RevocationList) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	w := wool.Get(ctx).In("RevocationList.RevokeToken")
+
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil // already expired
+	}
+	if ttl > AccessTokenTTL {
+		ttl = AccessTokenTTL
+	}
+
+	if err := r.client.Set(ctx, RevokedTokenPrefix+tokenID, "1", ttl).Err(); err != nil {
+		return w.Wrapf(err, "cannot store revoked token")
+	}
+	r.publish(ctx, Revocation{TokenID: tokenID, ExpiresAt: time.Now().Add(ttl).Unix()})
+	return nil
+}
+
+// RevokeUserTokens invalidates every access token issued to the user at or before the given time.
+// The entry outlives the last such token by AccessTokenTTL, then expires.
+func (r *RevocationList) RevokeUserTokens(ctx context.Context, userID string, before time.Time) error {
+	w := wool.Get(ctx).In("RevocationList.RevokeUserTokens")
+
+	ttl := time.Until(before.Add(AccessTokenTTL))
+	if ttl <= 0 {
+		return nil
+	}
+
+	cutoff := before.Unix()
+	if err := r.client.Set(ctx, RevokedUserPrefix+userID, strconv.FormatInt(cutoff, 10), ttl).Err(); err != nil {
+		return w.Wrapf(err, "cannot store user revocation")
+	}
+	r.publish(ctx, Revocation{UserID: userID, Before: cutoff, ExpiresAt: time.Now().Add(ttl).Unix()})
+	return nil
+}
+
+// InvalidateAPIKey tells every auth-sidecar to drop cached validations of the key.
+func (r *RevocationList) InvalidateAPIKey(ctx context.Context, keyID string) error {
+	w := wool.Get(ctx).In("RevocationList.InvalidateAPIKey")
+	if err := r.client.Publish(ctx, APIKeyRevocationChannel, keyID).Err(); err != nil {
+		return w.Wrapf(err, "cannot publish API key revocation")
+	}
+	return nil
+}
+
+// publish is best effort: the Redis keys are the source of truth.
+func (r *RevocationList) publish(ctx context.Context, rev Revocation) {
+	w := wool.Get(ctx).In("RevocationList.publish")
+	data, err := json.Marshal(rev)
+	if err != nil {
+		return
+	}
+	if err := r.client.Publish(ctx, RevocationChannel, data).Err(); err != nil {
+		w.Warn("cannot publish revocation", wool.ErrField(err))
+	}
+}
+
+// Close releases the Redis connection.
+func (r *RevocationList) Close() error {
+	return r.client.Close()
+}
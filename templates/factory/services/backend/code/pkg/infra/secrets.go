@@ -0,0 +1,61 @@
+package infra
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/codefly-dev/core/wool"
+	codefly "github.com/codefly-dev/sdk-go"
+
+	"backend/pkg/business"
+)
+
+// Secrets providers, chosen with the "secrets" provider configuration.
+const (
+	SecretsProviderVault = "vault"
+	SecretsProviderLocal = "local"
+)
+
+// sha256Scheme prefixes plain SHA-256 digests, written by an earlier fallback
+// for when Vault was unreachable. They are only matched, never issued.
+const sha256Scheme = "sha256"
+
+// SecretsProvider supplies the backend's key material: the API key hasher,
+// the JWT signing key and the PII encryption keys.
+type SecretsProvider interface {
+	KeyHasher(ctx context.Context) (business.KeyHasher, error)
+	TokenService(ctx context.Context) (*TokenService, error)
+	PIICipher(ctx context.Context) (*PIICipher, error)
+	// DegradedStartup reports whether the service may start without the
+	// provider's secrets, reporting the outage through Health.
+	DegradedStartup() bool
+	Health(ctx context.Context) error
+	Close() error
+}
+
+var (
+	_ SecretsProvider = (*VaultClient)(nil)
+	_ SecretsProvider = (*LocalSecrets)(nil)
+)
+
+// NewSecretsProvider creates the configured provider: local files and
+// environment variables by default, or Vault with the "secrets" provider set
+// to "vault" and a "vault" address (or the vault service as a dependency).
+func NewSecretsProvider(ctx context.Context) (SecretsProvider, error) {
+	w := wool.Get(ctx).In("NewSecretsProvider")
+
+	provider, _ := codefly.For(ctx).Configuration("secrets", "provider")
+	switch provider {
+	case "", SecretsProviderLocal:
+		return NewLocalSecrets(ctx)
+	case SecretsProviderVault:
+		return NewVaultClient(ctx)
+	}
+	return nil, w.NewError("unknown secrets provider %q", provider)
+}
+
+func legacySHA256Hash(plaintext string) string {
+	digest := sha256.Sum256([]byte(plaintext))
+	return sha256Scheme + ":" + hex.EncodeToString(digest[:])
+}
@@ -2,82 +2,643 @@ package infra
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	codefly "github.com/codefly-dev/sdk-go"
 	"github.com/codefly-dev/core/wool"
+
+	"backend/pkg/business"
+)
+
+// Vault auth methods.
+const (
+	VaultAuthToken      = "token"
+	VaultAuthAppRole    = "approle"
+	VaultAuthKubernetes = "kubernetes"
 )
 
-// VaultClient provides transit encryption and hashing via HashiCorp Vault.
+// Startup modes: fail when Vault can't be reached, or start degraded and keep
+// logging in in the background while Health reports the outage.
+const (
+	VaultStartupFailFast = "fail_fast"
+	VaultStartupDegraded = "degraded"
+)
+
+const (
+	vaultTimeout          = 5 * time.Second
+	vaultMaxRetries       = 3
+	vaultBackoff          = 100 * time.Millisecond
+	vaultMaxBackoff       = 2 * time.Second
+	vaultMaxLoginBackoff  = time.Minute
+	vaultBreakerThreshold = 5
+	vaultBreakerCooldown  = 30 * time.Second
+	vaultVersionsTTL      = 5 * time.Minute
+
+	kubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// VaultConfig configures a VaultClient. Zero durations and counts use defaults.
+type VaultConfig struct {
+	Address    string
+	AuthMethod string // token (default), approle or kubernetes
+	AuthMount  string // auth mount path, defaults to the method name
+	TransitKey string // API key HMACs
+	PIIKey     string // transit key wrapping PII data keys, "pii" by default
+
+	Token          string // token auth
+	RoleID         string // approle auth
+	SecretID       string
+	KubernetesRole string // kubernetes auth
+	JWTPath        string // service account token, defaults to the in-cluster path
+
+	Timeout          time.Duration // per attempt
+	MaxRetries       int
+	BreakerThreshold int // consecutive failures that open the circuit
+	BreakerCooldown  time.Duration
+
+	// Degraded starts the client even when the first login fails
+	Degraded bool
+}
+
+// VaultClient is the shared Vault client: it logs in with the configured
+// auth method, renews its token (or logs in again) before it expires, and
+// calls Vault with a timeout, retries with backoff on transient errors and a
+// circuit breaker. It also provides transit hashing of API keys.
 type VaultClient struct {
-	address    string
-	token      string
-	transitKey string
+	config  VaultConfig
+	http    *http.Client
+	breaker *circuitBreaker
+
+	// loginMu serializes logins, so that concurrent refused requests log in once
+	loginMu sync.Mutex
+
+	mu            sync.RWMutex
+	token         string
+	expiresAt     time.Time // zero when the token doesn't expire
+	renewable     bool
+	loginErr      error
+	loginFailures int
+
+	// versions caches the transit key versions; an HMAC made with a newer
+	// version than the cached latest drops it
+	versionsMu sync.Mutex
+	versions   *transitVersions
+
+	stop context.CancelFunc
+	done chan struct{}
 }
 
+type transitVersions struct {
+	latest, oldest int
+	fetchedAt      time.Time
+}
+
+// NewVaultClient creates the Vault client from the "vault" configuration
+// (address, auth_method, auth_mount, kubernetes_role, kubernetes_token_path,
+// startup) and secrets (token, role_id, secret_id). The address and token
+// default to those of the vault service, when the backend depends on it.
 func NewVaultClient(ctx context.Context) (*VaultClient, error) {
 	w := wool.Get(ctx).In("NewVaultClient")
 
-	address, err := codefly.For(ctx).Service("vault").Configuration("vault", "address")
+	address, err := codefly.For(ctx).Configuration("vault", "address")
+	if err != nil || address == "" {
+		address, err = codefly.For(ctx).Service("vault").Configuration("vault", "address")
+		if err != nil {
+			return nil, w.Wrapf(err, "failed to get vault address")
+		}
+	}
+
+	config := VaultConfig{Address: address, TransitKey: "api-keys"}
+	config.AuthMethod, _ = codefly.For(ctx).Configuration("vault", "auth_method")
+	config.AuthMount, _ = codefly.For(ctx).Configuration("vault", "auth_mount")
+	startup, _ := codefly.For(ctx).Configuration("vault", "startup")
+	switch startup {
+	case "", VaultStartupFailFast:
+	case VaultStartupDegraded:
+		config.Degraded = true
+	default:
+		return nil, w.NewError("unknown vault startup mode %q", startup)
+	}
+
+	switch config.AuthMethod {
+	case "", VaultAuthToken:
+		config.AuthMethod = VaultAuthToken
+		config.Token, err = codefly.For(ctx).Secret("vault", "token")
+		if err != nil || config.Token == "" {
+			config.Token, err = codefly.For(ctx).Service("vault").Secret("vault", "token")
+			if err != nil {
+				return nil, w.Wrapf(err, "failed to get vault token")
+			}
+		}
+	case VaultAuthAppRole:
+		config.RoleID, err = codefly.For(ctx).Secret("vault", "role_id")
+		if err != nil {
+			return nil, w.Wrapf(err, "failed to get vault AppRole role_id")
+		}
+		config.SecretID, err = codefly.For(ctx).Secret("vault", "secret_id")
+		if err != nil {
+			return nil, w.Wrapf(err, "failed to get vault AppRole secret_id")
+		}
+	case VaultAuthKubernetes:
+		config.KubernetesRole, err = codefly.For(ctx).Configuration("vault", "kubernetes_role")
+		if err != nil {
+			return nil, w.Wrapf(err, "failed to get vault kubernetes_role")
+		}
+		config.JWTPath, _ = codefly.For(ctx).Configuration("vault", "kubernetes_token_path")
+	default:
+		return nil, w.NewError("unknown vault auth method %q", config.AuthMethod)
+	}
+
+	return NewVaultClientFromConfig(ctx, config)
+}
+
+// NewVaultClientFromConfig logs in to Vault and starts keeping the token
+// alive until Close. When the login fails, it returns the error, or with
+// Degraded a client whose Health reports it while it keeps trying.
+func NewVaultClientFromConfig(ctx context.Context, config VaultConfig) (*VaultClient, error) {
+	w := wool.Get(ctx).In("NewVaultClientFromConfig")
+
+	if config.Address == "" {
+		return nil, w.NewError("vault address is required")
+	}
+	if config.AuthMethod == "" {
+		config.AuthMethod = VaultAuthToken
+	}
+	if config.AuthMount == "" {
+		config.AuthMount = config.AuthMethod
+	}
+	if config.PIIKey == "" {
+		config.PIIKey = "pii"
+	}
+	if config.JWTPath == "" {
+		config.JWTPath = kubernetesTokenPath
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = vaultTimeout
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = vaultMaxRetries
+	}
+	if config.BreakerThreshold <= 0 {
+		config.BreakerThreshold = vaultBreakerThreshold
+	}
+	if config.BreakerCooldown <= 0 {
+		config.BreakerCooldown = vaultBreakerCooldown
+	}
+
+	v := &VaultClient{
+		config:  config,
+		http:    &http.Client{Timeout: config.Timeout},
+		breaker: newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
+		done:    make(chan struct{}),
+	}
+	if err := v.login(ctx); err != nil {
+		if !config.Degraded {
+			return nil, w.Wrapf(err, "cannot log in to vault with %s auth", config.AuthMethod)
+		}
+		w.Warn("vault unavailable, starting degraded", wool.ErrField(err))
+	}
+
+	maintainCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	v.stop = stop
+	go v.maintain(maintainCtx)
+	return v, nil
+}
+
+// Close stops the token renewal.
+func (v *VaultClient) Close() error {
+	v.stop()
+	<-v.done
+	return nil
+}
+
+// KeyHasher returns the client itself: API keys are hashed with transit HMAC.
+func (v *VaultClient) KeyHasher(_ context.Context) (business.KeyHasher, error) {
+	return v, nil
+}
+
+// TokenService loads the JWT signing key from Vault KV.
+func (v *VaultClient) TokenService(ctx context.Context) (*TokenService, error) {
+	return NewTokenService(ctx, v)
+}
+
+// PIICipher wraps PII data keys with the transit key and keys blind indexes
+// with the base64 "key" of the KV secret pii-index-key.
+func (v *VaultClient) PIICipher(ctx context.Context) (*PIICipher, error) {
+	w := wool.Get(ctx).In("VaultClient.PIICipher")
+
+	data, err := v.request(ctx, http.MethodGet, "/v1/secret/data/pii-index-key", "")
+	if err != nil {
+		return nil, w.Wrapf(err, "cannot fetch PII index key from vault")
+	}
+	secret, _ := data["data"].(map[string]interface{})
+	encoded, _ := secret["key"].(string)
+	indexKey, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(indexKey) == 0 {
+		return nil, w.NewError("no base64 key in vault secret pii-index-key")
+	}
+	return NewPIICipher(v, indexKey)
+}
+
+// WrapKey encrypts a data key with the PII transit key.
+func (v *VaultClient) WrapKey(ctx context.Context, key []byte) (string, error) {
+	body, err := json.Marshal(map[string]string{"plaintext": base64.StdEncoding.EncodeToString(key)})
+	if err != nil {
+		return "", err
+	}
+	result, err := v.request(ctx, http.MethodPost, fmt.Sprintf("/v1/transit/encrypt/%s", v.config.PIIKey), string(body))
 	if err != nil {
-		return nil, w.Wrapf(err, "failed to get vault address")
+		return "", err
 	}
+	ciphertext, _ := result["ciphertext"].(string)
+	if ciphertext == "" {
+		return "", fmt.Errorf("vault returned no ciphertext for transit key %s", v.config.PIIKey)
+	}
+	return ciphertext, nil
+}
 
-	token, err := codefly.For(ctx).Service("vault").Secret("vault", "token")
+// UnwrapKey decrypts a data key with the PII transit key.
+func (v *VaultClient) UnwrapKey(ctx context.Context, wrapped string) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"ciphertext": wrapped})
 	if err != nil {
-		return nil, w.Wrapf(err, "failed to get vault token")
+		return nil, err
 	}
+	result, err := v.request(ctx, http.MethodPost, fmt.Sprintf("/v1/transit/decrypt/%s", v.config.PIIKey), string(body))
+	if err != nil {
+		return nil, err
+	}
+	plaintext, _ := result["plaintext"].(string)
+	return base64.StdEncoding.DecodeString(plaintext)
+}
 
-	return &VaultClient{
-		address:    address,
-		token:      token,
-		transitKey: "api-keys",
-	}, nil
+// DegradedStartup reports whether the service may start without Vault.
+func (v *VaultClient) DegradedStartup() bool {
+	return v.config.Degraded
 }
 
-// HashKey hashes an API key using vault transit HMAC.
-// Falls back to local SHA-256 if vault is unavailable.
+// Health returns why Vault can't be used right now: no valid token, or the
+// circuit is open after repeated failures.
+func (v *VaultClient) Health(_ context.Context) error {
+	v.mu.RLock()
+	loginErr := v.loginErr
+	v.mu.RUnlock()
+	if loginErr != nil {
+		return fmt.Errorf("not logged in: %w", loginErr)
+	}
+	if err := v.breaker.open(); err != nil {
+		return fmt.Errorf("circuit open: %w", err)
+	}
+	return nil
+}
+
+// vaultHMACScheme prefixes Vault transit HMACs, which keep the transit key
+// version ("vault-hmac:v2:...").
+const vaultHMACScheme = "vault-hmac"
+
+// HashKey hashes an API key with the latest version of the transit key.
 func (v *VaultClient) HashKey(ctx context.Context, plaintext string) (string, error) {
-	input := base64.StdEncoding.EncodeToString([]byte(plaintext))
-	body := fmt.Sprintf(`{"input":"%s"}`, input)
+	return v.hmac(ctx, plaintext, 0)
+}
+
+// PreviousHashKeys hashes an API key with every older version of the transit
+// key still usable, newest first, then with the legacy SHA-256 scheme.
+func (v *VaultClient) PreviousHashKeys(ctx context.Context, plaintext string) ([]string, error) {
+	versions, err := v.transitVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []string
+	for version := versions.latest - 1; version >= max(versions.oldest, 1); version-- {
+		hash, err := v.hmac(ctx, plaintext, version)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return append(hashes, legacySHA256Hash(plaintext)), nil
+}
+
+// transitVersions returns the transit key versions, read from Vault at most
+// every vaultVersionsTTL.
+func (v *VaultClient) transitVersions(ctx context.Context) (transitVersions, error) {
+	w := wool.Get(ctx).In("VaultClient.transitVersions")
+
+	v.versionsMu.Lock()
+	cached := v.versions
+	v.versionsMu.Unlock()
+	if cached != nil && time.Since(cached.fetchedAt) < vaultVersionsTTL {
+		return *cached, nil
+	}
+
+	result, err := v.request(ctx, http.MethodGet, fmt.Sprintf("/v1/transit/keys/%s", v.config.TransitKey), "")
+	if err != nil {
+		return transitVersions{}, w.Wrapf(err, "cannot read transit key %s", v.config.TransitKey)
+	}
+	latest, _ := result["latest_version"].(float64)
+	oldest, _ := result["min_decryption_version"].(float64)
+	if latest < 1 {
+		return transitVersions{}, w.NewError("transit key %s has no latest_version", v.config.TransitKey)
+	}
+
+	versions := &transitVersions{latest: int(latest), oldest: int(oldest), fetchedAt: time.Now()}
+	v.versionsMu.Lock()
+	v.versions = versions
+	v.versionsMu.Unlock()
+	return *versions, nil
+}
+
+// observeVersion drops the cached transit key versions once Vault hashed
+// with a version newer than their latest: the key was rotated.
+func (v *VaultClient) observeVersion(version int) {
+	v.versionsMu.Lock()
+	defer v.versionsMu.Unlock()
+	if v.versions != nil && version > v.versions.latest {
+		v.versions = nil
+	}
+}
+
+// hmac computes a transit HMAC with a key version (0 for the latest) and
+// rewrites Vault's "vault:vN:" prefix into the stored "vault-hmac:vN:" scheme.
+func (v *VaultClient) hmac(ctx context.Context, plaintext string, version int) (string, error) {
+	w := wool.Get(ctx).In("VaultClient.hmac")
+
+	payload := map[string]any{"input": base64.StdEncoding.EncodeToString([]byte(plaintext))}
+	if version > 0 {
+		payload["key_version"] = version
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", w.Wrapf(err, "cannot encode HMAC request")
+	}
 
 	result, err := v.request(ctx, http.MethodPost,
-		fmt.Sprintf("/v1/transit/hmac/%s", v.transitKey), body)
+		fmt.Sprintf("/v1/transit/hmac/%s", v.config.TransitKey), string(body))
+	if err != nil {
+		return "", w.Wrapf(err, "cannot compute HMAC with transit key %s", v.config.TransitKey)
+	}
+
+	hmac, _ := result["hmac"].(string)
+	rest, ok := strings.CutPrefix(hmac, "vault:")
+	prefix, _, found := strings.Cut(rest, ":")
+	keyVersion, err := strconv.Atoi(strings.TrimPrefix(prefix, "v"))
+	if !ok || !found || !strings.HasPrefix(prefix, "v") || err != nil {
+		return "", w.NewError("unexpected HMAC format from transit key %s", v.config.TransitKey)
+	}
+	v.observeVersion(keyVersion)
+	return vaultHMACScheme + ":" + rest, nil
+}
+
+// login gets a token with the configured auth method, and records a failure
+// as the client being logged out.
+func (v *VaultClient) login(ctx context.Context) error {
+	v.loginMu.Lock()
+	defer v.loginMu.Unlock()
+	auth, err := v.authenticate(ctx)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
 	if err != nil {
-		// Fallback to local SHA-256
-		h := sha256.Sum256([]byte(plaintext))
-		return hex.EncodeToString(h[:]), nil
+		v.loginErr = err
+		v.loginFailures++
+		return err
 	}
+	v.setToken(auth)
+	v.loginErr = nil
+	v.loginFailures = 0
+	return nil
+}
+
+// relogin logs in again after Vault refused the token, unless another request
+// already did, and returns the token to retry with. On failure the current
+// token is kept: maintain keeps renewing it or logging in.
+func (v *VaultClient) relogin(ctx context.Context, refused string) (string, error) {
+	v.loginMu.Lock()
+	defer v.loginMu.Unlock()
 
-	hmac, ok := result["hmac"].(string)
-	if !ok {
-		h := sha256.Sum256([]byte(plaintext))
-		return hex.EncodeToString(h[:]), nil
+	v.mu.RLock()
+	token := v.token
+	v.mu.RUnlock()
+	if token != refused {
+		return token, nil
 	}
-	return hmac, nil
+
+	auth, err := v.authenticate(ctx)
+	if err != nil {
+		return "", err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.setToken(auth)
+	v.loginErr = nil
+	v.loginFailures = 0
+	return v.token, nil
 }
 
+// authenticate gets a token with the configured auth method. A static token
+// is looked up to learn its TTL.
+func (v *VaultClient) authenticate(ctx context.Context) (*vaultAuth, error) {
+	var (
+		auth *vaultAuth
+		err  error
+	)
+	switch v.config.AuthMethod {
+	case VaultAuthToken:
+		auth, err = v.lookupToken(ctx, v.config.Token)
+	case VaultAuthAppRole:
+		auth, err = v.authLogin(ctx, map[string]string{"role_id": v.config.RoleID, "secret_id": v.config.SecretID})
+	case VaultAuthKubernetes:
+		var jwt []byte
+		jwt, err = os.ReadFile(v.config.JWTPath)
+		if err == nil {
+			auth, err = v.authLogin(ctx, map[string]string{"role": v.config.KubernetesRole, "jwt": strings.TrimSpace(string(jwt))})
+		}
+	default:
+		err = fmt.Errorf("unknown auth method %q", v.config.AuthMethod)
+	}
+	return auth, err
+}
+
+func (v *VaultClient) authLogin(ctx context.Context, credentials map[string]string) (*vaultAuth, error) {
+	body, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.call(ctx, http.MethodPost, fmt.Sprintf("/v1/auth/%s/login", v.config.AuthMount), string(body), "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return nil, errors.New("vault login returned no token")
+	}
+	return resp.Auth, nil
+}
+
+func (v *VaultClient) lookupToken(ctx context.Context, token string) (*vaultAuth, error) {
+	resp, err := v.call(ctx, http.MethodGet, "/v1/auth/token/lookup-self", "", token)
+	if err != nil {
+		return nil, err
+	}
+	ttl, _ := resp.Data["ttl"].(float64)
+	renewable, _ := resp.Data["renewable"].(bool)
+	return &vaultAuth{ClientToken: token, LeaseDuration: int(ttl), Renewable: renewable}, nil
+}
+
+// setToken must be called with v.mu held.
+func (v *VaultClient) setToken(auth *vaultAuth) {
+	v.token = auth.ClientToken
+	v.renewable = auth.Renewable
+	v.expiresAt = time.Time{}
+	if auth.LeaseDuration > 0 {
+		v.expiresAt = time.Now().Add(time.Duration(auth.LeaseDuration) * time.Second)
+	}
+}
+
+// maintain renews the token at two thirds of its TTL, logs in again when it
+// can't be renewed, and retries failed logins with backoff.
+func (v *VaultClient) maintain(ctx context.Context) {
+	defer close(v.done)
+	w := wool.Get(ctx).In("VaultClient.maintain")
+	for {
+		timer := time.NewTimer(v.nextMaintenance())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		v.mu.RLock()
+		loggedIn, renewable, expires := v.loginErr == nil, v.renewable, !v.expiresAt.IsZero()
+		v.mu.RUnlock()
+		if loggedIn && !expires {
+			continue
+		}
+		if loggedIn && renewable {
+			err := v.renew(ctx)
+			if err == nil {
+				continue
+			}
+			w.Warn("cannot renew vault token, logging in again", wool.ErrField(err))
+		}
+		if err := v.login(ctx); err != nil {
+			w.Warn("cannot log in to vault", wool.ErrField(err))
+		}
+	}
+}
+
+func (v *VaultClient) nextMaintenance() time.Duration {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.loginErr != nil {
+		return backoff(v.loginFailures, time.Second, vaultMaxLoginBackoff)
+	}
+	if v.expiresAt.IsZero() {
+		// Nothing to renew; check again in case the token gets a TTL
+		return time.Hour
+	}
+	return max(time.Until(v.expiresAt)*2/3, time.Second)
+}
+
+func (v *VaultClient) renew(ctx context.Context) error {
+	v.mu.RLock()
+	token := v.token
+	v.mu.RUnlock()
+
+	resp, err := v.call(ctx, http.MethodPost, "/v1/auth/token/renew-self", "", token)
+	if err != nil {
+		return err
+	}
+	if resp.Auth == nil {
+		return errors.New("vault renewal returned no lease")
+	}
+	if resp.Auth.ClientToken == "" {
+		resp.Auth.ClientToken = token
+	}
+	v.mu.Lock()
+	v.setToken(resp.Auth)
+	v.mu.Unlock()
+	return nil
+}
+
+// request calls Vault with the current token and returns the response data.
+// It fails fast while the circuit is open, and logs in again once if Vault
+// refuses a token that should still be valid.
 func (v *VaultClient) request(ctx context.Context, method, path, body string) (map[string]interface{}, error) {
+	v.mu.RLock()
+	token, loginErr := v.token, v.loginErr
+	v.mu.RUnlock()
+	if loginErr != nil {
+		return nil, fmt.Errorf("vault unavailable: not logged in: %w", loginErr)
+	}
+	if !v.breaker.allow() {
+		return nil, fmt.Errorf("vault unavailable: circuit open: %w", v.breaker.open())
+	}
+
+	resp, err := v.call(ctx, method, path, body, token)
+	var statusErr *vaultStatusError
+	if errors.As(err, &statusErr) && statusErr.status == http.StatusForbidden && v.config.AuthMethod != VaultAuthToken {
+		if token, loginErr = v.relogin(ctx, token); loginErr == nil {
+			resp, err = v.call(ctx, method, path, body, token)
+		}
+	}
+
+	// Only outages count against the circuit, not refused requests
+	if vaultRetryable(err) {
+		v.breaker.record(err)
+	} else {
+		v.breaker.record(nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// call sends a request, retrying transient failures with jittered backoff.
+func (v *VaultClient) call(ctx context.Context, method, path, body, token string) (*vaultResponse, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := v.send(ctx, method, path, body, token)
+		if err == nil || !vaultRetryable(err) || attempt == v.config.MaxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+		timer := time.NewTimer(backoff(attempt, vaultBackoff, vaultMaxBackoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (v *VaultClient) send(ctx context.Context, method, path, body, token string) (*vaultResponse, error) {
 	var bodyReader io.Reader
 	if body != "" {
 		bodyReader = strings.NewReader(body)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, v.address+path, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, v.config.Address+path, bodyReader)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("X-Vault-Token", v.token)
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := v.http.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -86,14 +647,57 @@ func (v *VaultClient) request(ctx context.Context, method, path, body string) (m
 	respBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("vault %s %s returned %d: %s", method, path, resp.StatusCode, string(respBody))
+		return nil, &vaultStatusError{method: method, path: path, status: resp.StatusCode, body: string(respBody)}
 	}
 
-	var envelope struct {
-		Data map[string]interface{} `json:"data"`
+	var envelope vaultResponse
+	if len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, &envelope); err != nil {
+			return nil, err
+		}
 	}
-	if err := json.Unmarshal(respBody, &envelope); err != nil {
-		return nil, err
+	return &envelope, nil
+}
+
+type vaultResponse struct {
+	Data map[string]interface{} `json:"data"`
+	Auth *vaultAuth             `json:"auth"`
+}
+
+type vaultAuth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+type vaultStatusError struct {
+	method, path string
+	status       int
+	body         string
+}
+
+func (e *vaultStatusError) Error() string {
+	return fmt.Sprintf("vault %s %s returned %d: %s", e.method, e.path, e.status, e.body)
+}
+
+// vaultRetryable reports whether err means Vault is unavailable rather than
+// refusing the request: network errors, timeouts, 429 and 5xx.
+func vaultRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *vaultStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.status == http.StatusTooManyRequests || statusErr.status >= 500
+	}
+	return true
+}
+
+// backoff is an exponential delay from base, capped, with jitter.
+func backoff(attempt int, base, limit time.Duration) time.Duration {
+	d := limit
+	if attempt < 16 {
+		d = min(base<<attempt, limit)
 	}
-	return envelope.Data, nil
+	return d/2 + rand.N(d/2+1)
 }
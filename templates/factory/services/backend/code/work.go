@@ -4,7 +4,9 @@ import (
 	"backend/fixtures"
 	"backend/pkg/business"
 	"context"
+	"time"
 
+	"github.com/codefly-dev/core/wool"
 	codefly "github.com/codefly-dev/sdk-go"
 
 	"backend/pkg/infra"
@@ -27,19 +29,85 @@ func doWork(ctx context.Context) (Clean, error) {
 		return nil, err
 	}
 
-	// Initialize vault-backed services
-	vaultClient, err := infra.NewVaultClient(ctx)
-	if err == nil {
-		service.SetHasher(vaultClient)
+	service.AddHealthCheck("store", store)
+
+	// Key material comes from the "secrets" provider: Vault, or local files
+	// and environment variables. Without Vault, startup fails unless the
+	// "vault" startup configuration is "degraded": the client then keeps
+	// logging in in the background and health reports the outage.
+	secrets, err := infra.NewSecretsProvider(ctx)
+	if err != nil {
+		return nil, err
 	}
+	service.AddHealthCheck("secrets", secrets)
 
-	tokenService, err := infra.NewTokenService(ctx)
+	hasher, err := secrets.KeyHasher(ctx)
+	if err != nil {
+		_ = secrets.Close()
+		return nil, err
+	}
+	service.SetHasher(hasher)
+
+	// The signing key is only read at startup: a degraded start issues no
+	// tokens until restarted, which health reports as token_signer
+	tokenService, err := secrets.TokenService(ctx)
 	if err == nil {
 		service.SetTokenSigner(tokenService)
+	} else if secrets.DegradedStartup() {
+		wool.Get(ctx).Warn("cannot load JWT signing key, starting degraded", wool.ErrField(err))
+	} else {
+		_ = secrets.Close()
+		return nil, err
+	}
+
+	// PII columns are encrypted under keys from the same provider
+	piiCipher, err := secrets.PIICipher(ctx)
+	if err == nil {
+		store.SetPIICipher(piiCipher)
+	} else if secrets.DegradedStartup() {
+		wool.Get(ctx).Warn("cannot load PII keys, starting degraded", wool.ErrField(err))
+	} else {
+		_ = secrets.Close()
+		return nil, err
+	}
+
+	// Revocation needs the cache service; without it only sessions are revoked
+	// and sidecars only drop revoked API keys when their cache TTL runs out
+	revocations, err := infra.NewRevocationList(ctx)
+	if err == nil {
+		service.SetTokenRevoker(revocations)
+		service.SetAPIKeyInvalidator(revocations)
+	}
+
+	// Notifications (e.g. leaked API keys) are queued in the cache service for delivery
+	notifications, err := infra.NewNotificationQueue(ctx)
+	if err == nil {
+		service.SetNotifier(notifications)
+	} else {
+		wool.Get(ctx).Warn("cannot queue notifications, leaked API key owners will not be notified", wool.ErrField(err))
 	}
 
-	auditEmitter := business.NewAsyncAuditEmitter(store, 1024)
+	apiKeyUsage := business.NewBatchedAPIKeyUsage(store, 100000)
+	service.SetAPIKeyUsageRecorder(apiKeyUsage)
+
+	// Audit events go to an outbox in the transaction of their change; the
+	// relay moves them to the audit log
+	auditEmitter := business.NewOutboxAuditEmitter(store)
 	service.SetAuditEmitter(auditEmitter)
+	service.AddHealthCounters(auditEmitter)
+
+	auditRelay := business.NewAuditRelay(store, 500)
+	service.AddHealthCheck("audit_relay", auditRelay)
+	service.AddHealthCounters(auditRelay)
+
+	// Expired audit partitions are archived to local storage; without an
+	// archive directory, audit events are kept for ever
+	auditArchive, err := infra.NewLocalAuditArchive(ctx)
+	if err != nil {
+		wool.Get(ctx).Warn("cannot open audit archive, audit events are kept", wool.ErrField(err))
+	} else if auditArchive != nil {
+		service.SetAuditArchive(auditArchive)
+	}
 
 	entitlementChecker := business.NewDefaultEntitlementChecker(store)
 	service.SetEntitlementChecker(entitlementChecker)
@@ -49,6 +117,16 @@ func doWork(ctx context.Context) (Clean, error) {
 
 	adapters.WithService(service)
 
+	// Forwarded client addresses (API key allowlists, audit events) are only
+	// believed from the auth sidecar and ingress listed here, besides loopback
+	if value, err := codefly.For(ctx).Configuration("network", "trusted_proxies"); err == nil && value != "" {
+		proxies, err := adapters.ParseTrustedProxies(value)
+		if err != nil {
+			return nil, err
+		}
+		adapters.WithTrustedProxies(proxies)
+	}
+
 	if codefly.WithFixture("simple") {
 		err = fixtures.Simple(ctx, service)
 		if err != nil {
@@ -56,7 +134,38 @@ func doWork(ctx context.Context) (Clean, error) {
 		}
 	}
 
+	sweepCtx, stopSweep := context.WithCancel(ctx)
+	go apiKeyUsage.Run(sweepCtx, 10*time.Second)
+	// Revoke rotated API keys once their grace period is over
+	go service.RunAPIKeyRevocations(sweepCtx, time.Minute)
+	// Disable API keys whose owner lost the permissions behind their scopes
+	go service.RunAPIKeyReevaluation(sweepCtx, 15*time.Minute)
+	go auditRelay.Run(sweepCtx, time.Second)
+	// Sign the heads of audit chains that grew, for offline verification
+	go service.RunAuditCheckpoints(sweepCtx, time.Hour)
+	// Create monthly audit partitions ahead, archive the expired ones
+	go service.RunAuditMaintenance(sweepCtx, 24*time.Hour)
+
 	return func() {
+		stopSweep()
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := apiKeyUsage.Flush(flushCtx); err != nil {
+			wool.Get(ctx).Warn("cannot record API key usage", wool.ErrField(err))
+		}
+		cancel()
+		// Relay what is left of the outbox before exiting (SIGTERM)
+		drainCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := auditRelay.Drain(drainCtx); err != nil {
+			wool.Get(ctx).Warn("cannot drain audit outbox", wool.ErrField(err))
+		}
+		cancel()
+		if revocations != nil {
+			_ = revocations.Close()
+		}
+		if notifications != nil {
+			_ = notifications.Close()
+		}
+		_ = secrets.Close()
 		store.Close()
 	}, nil
 }
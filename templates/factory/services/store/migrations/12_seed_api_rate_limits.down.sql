@@ -0,0 +1,2 @@
+DELETE FROM entitlement_overrides WHERE feature = 'api_key_requests_per_minute';
+DELETE FROM plan_entitlements WHERE feature = 'api_key_requests_per_minute';
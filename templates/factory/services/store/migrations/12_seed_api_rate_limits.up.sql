@@ -0,0 +1,16 @@
+-- =============================================================================
+-- Migration 12: Per-key API rate limits
+-- Enforced by the auth sidecar as a token bucket per API key.
+-- The per-org bucket is derived from api_calls_monthly.
+-- =============================================================================
+
+-- NULL = unlimited
+INSERT INTO plan_entitlements (plan_id, feature, limit_value)
+SELECT p.id, e.feature, e.limit_value
+FROM plans p
+JOIN (VALUES
+    ('free',       'api_key_requests_per_minute', 60),
+    ('pro',        'api_key_requests_per_minute', 600),
+    ('enterprise', 'api_key_requests_per_minute', NULL)
+) AS e(plan_name, feature, limit_value) ON p.name = e.plan_name
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_api_keys_revoke_at;
+DROP INDEX IF EXISTS idx_api_keys_rotated_from;
+
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS revoke_at,
+    DROP COLUMN IF EXISTS rotated_from;
@@ -0,0 +1,15 @@
+-- =============================================================================
+-- Migration 13: API key rotation
+-- RotateAPIKey issues a successor linked to its predecessor; the old key keeps
+-- working until revoke_at, when the backend's sweeper revokes it.
+-- =============================================================================
+
+ALTER TABLE api_keys
+    ADD COLUMN rotated_from UUID REFERENCES api_keys(id) ON DELETE SET NULL,
+    ADD COLUMN revoke_at    TIMESTAMP WITH TIME ZONE;
+
+-- A key is rotated at most once
+CREATE UNIQUE INDEX idx_api_keys_rotated_from ON api_keys(rotated_from) WHERE rotated_from IS NOT NULL;
+
+-- RevokeDueAPIKeys: keys in their grace period
+CREATE INDEX idx_api_keys_revoke_at ON api_keys(revoke_at) WHERE revoke_at IS NOT NULL AND revoked_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS disabled_reason,
+    DROP COLUMN IF EXISTS disabled_at;
@@ -0,0 +1,10 @@
+-- =============================================================================
+-- Migration 14: API key scope containment
+-- A key's scopes must stay within its owner's permissions. Re-evaluation
+-- disables keys whose owner lost a permission behind them, and re-enables
+-- them if it comes back.
+-- =============================================================================
+
+ALTER TABLE api_keys
+    ADD COLUMN disabled_at     TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN disabled_reason TEXT;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_api_keys_org_last_used;
+DROP TABLE IF EXISTS api_key_usage_daily;
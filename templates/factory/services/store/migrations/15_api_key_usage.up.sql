@@ -0,0 +1,15 @@
+-- =============================================================================
+-- Migration 15: API key usage
+-- ValidateAPIKey aggregates usage in memory and flushes it in batches:
+-- api_keys.last_used_at / last_used_ip and a per-day request count.
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS "api_key_usage_daily" (
+    api_key_id    UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    day           DATE NOT NULL,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (api_key_id, day)
+);
+
+-- ListAPIKeys(unused_since): stale keys per org
+CREATE INDEX idx_api_keys_org_last_used ON api_keys(organization_id, last_used_at) WHERE revoked_at IS NULL;
@@ -0,0 +1,8 @@
+-- =============================================================================
+-- Migration 16: API key network restrictions
+-- Optional per-key allowlists checked by ValidateAPIKey: client CIDRs and
+-- request origins (Origin / Referer). Empty means unrestricted.
+-- =============================================================================
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS allowed_cidrs TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS allowed_origins TEXT[] NOT NULL DEFAULT '{}';
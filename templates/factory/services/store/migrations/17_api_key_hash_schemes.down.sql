@@ -0,0 +1,5 @@
+UPDATE api_keys SET key_hash = substr(key_hash, 8)
+WHERE key_hash LIKE 'sha256:%';
+
+UPDATE api_keys SET key_hash = 'vault:' || substr(key_hash, 12)
+WHERE key_hash LIKE 'vault-hmac:v%';
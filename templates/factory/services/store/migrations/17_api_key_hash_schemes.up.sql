@@ -0,0 +1,15 @@
+-- =============================================================================
+-- Migration 17: Self-describing API key hashes
+-- key_hash carries its scheme and key version ("vault-hmac:v1:...",
+-- "sha256:...") so hashes made with a rotated Vault key, or by the old local
+-- SHA-256 fallback, can still be matched and are re-hashed on their next
+-- successful validation.
+-- =============================================================================
+
+-- Vault transit HMACs: vault:v1:... -> vault-hmac:v1:...
+UPDATE api_keys SET key_hash = 'vault-hmac:' || substr(key_hash, 7)
+WHERE key_hash LIKE 'vault:v%';
+
+-- Hex SHA-256 digests written when Vault was unreachable
+UPDATE api_keys SET key_hash = 'sha256:' || key_hash
+WHERE key_hash ~ '^[0-9a-f]{64}$';
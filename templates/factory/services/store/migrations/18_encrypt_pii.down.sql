@@ -0,0 +1,30 @@
+-- Restores the plaintext schema. Encrypted emails stay encrypted (the checks
+-- come back NOT VALID) and encrypted profiles, which JSONB can't hold, are
+-- reset to {}.
+
+DROP INDEX IF EXISTS idx_invitations_pending_legacy;
+DROP INDEX IF EXISTS idx_invitations_email_bidx;
+DROP INDEX IF EXISTS idx_invitations_pending_unique;
+ALTER TABLE invitations DROP COLUMN IF EXISTS email_bidx;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invitations_pending_unique
+    ON invitations (org_id, LOWER(email))
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_invitations_email ON invitations (LOWER(email));
+
+DROP INDEX IF EXISTS idx_user_identities_email_bidx;
+ALTER TABLE user_identities DROP COLUMN IF EXISTS provider_email_bidx;
+CREATE INDEX IF NOT EXISTS idx_user_identities_email_lower ON user_identities (LOWER(provider_email));
+ALTER TABLE user_identities ADD CONSTRAINT user_identities_email_check
+    CHECK (provider_email ~* '^[A-Za-z0-9._+%-]+@[A-Za-z0-9.-]+[.][A-Za-z]+$') NOT VALID;
+
+ALTER TABLE users ALTER COLUMN profile TYPE JSONB
+    USING CASE WHEN profile LIKE 'enc:v1:%' THEN '{}'::jsonb ELSE profile::jsonb END;
+ALTER TABLE users ALTER COLUMN profile SET DEFAULT '{}'::jsonb;
+CREATE INDEX IF NOT EXISTS idx_users_profile ON users USING gin (profile jsonb_path_ops);
+
+DROP INDEX IF EXISTS idx_users_primary_email_legacy;
+DROP INDEX IF EXISTS idx_users_primary_email_bidx;
+ALTER TABLE users DROP COLUMN IF EXISTS primary_email_bidx;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_primary_email_lower ON users (LOWER(primary_email));
+ALTER TABLE users ADD CONSTRAINT users_primary_email_check
+    CHECK (primary_email ~* '^[A-Za-z0-9._+%-]+@[A-Za-z0-9.-]+[.][A-Za-z]+$') NOT VALID;
@@ -0,0 +1,44 @@
+-- =============================================================================
+-- Migration 18: Field-level encryption of PII
+-- users.primary_email, users.profile, user_identities.provider_email and
+-- invitations.email hold envelope-encrypted values ("enc:v1:..."), written
+-- by the backend. Lookups and uniqueness move to HMAC blind indexes of the
+-- lowercased value, so they stay case-insensitive.
+--
+-- Existing rows keep their plaintext, with NULL blind indexes, until
+-- cmd/encrypt-pii encrypts them in place; the backend reads both. Until then
+-- their uniqueness is kept by partial LOWER() indexes over those rows, and the
+-- backend checks new values against them.
+-- =============================================================================
+
+-- users: ciphertexts fail the email format check and the LOWER() unique index
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_primary_email_check;
+DROP INDEX IF EXISTS idx_users_primary_email_lower;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS primary_email_bidx TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_primary_email_bidx ON users (primary_email_bidx);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_primary_email_legacy
+    ON users (LOWER(primary_email))
+    WHERE primary_email_bidx IS NULL;
+
+-- profile: encrypted JSON object
+DROP INDEX IF EXISTS idx_users_profile;
+ALTER TABLE users ALTER COLUMN profile DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN profile TYPE TEXT USING profile::text;
+
+-- user_identities
+ALTER TABLE user_identities DROP CONSTRAINT IF EXISTS user_identities_email_check;
+DROP INDEX IF EXISTS idx_user_identities_email_lower;
+ALTER TABLE user_identities ADD COLUMN IF NOT EXISTS provider_email_bidx TEXT;
+CREATE INDEX IF NOT EXISTS idx_user_identities_email_bidx ON user_identities (provider_email_bidx);
+
+-- invitations: one pending invitation per email per org
+DROP INDEX IF EXISTS idx_invitations_pending_unique;
+DROP INDEX IF EXISTS idx_invitations_email;
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS email_bidx TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invitations_pending_unique
+    ON invitations (org_id, email_bidx)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_invitations_email_bidx ON invitations (email_bidx);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invitations_pending_legacy
+    ON invitations (org_id, LOWER(email))
+    WHERE status = 'pending' AND email_bidx IS NULL;
@@ -0,0 +1,6 @@
+-- Keep the events not relayed yet
+INSERT INTO audit_events (id, actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address, created_at)
+SELECT id, actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address, created_at
+FROM audit_outbox ORDER BY seq;
+
+DROP TABLE IF EXISTS audit_outbox;
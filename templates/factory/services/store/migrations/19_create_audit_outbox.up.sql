@@ -0,0 +1,30 @@
+-- =============================================================================
+-- Migration 19: Transactional audit outbox
+-- Audit events are written to audit_outbox in the same transaction as the
+-- change they record, so an event exists if and only if its change
+-- committed. The backend relay moves them into audit_events (keeping their
+-- id and time) and deletes them from the outbox in one transaction.
+--
+-- Rows that fail to move stay in the outbox and are retried with backoff:
+-- attempts and last_error show why.
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS "audit_outbox" (
+    seq             BIGSERIAL PRIMARY KEY,
+    id              UUID DEFAULT gen_random_uuid() NOT NULL,
+    actor_id        UUID,
+    actor_type      TEXT NOT NULL,
+    action          TEXT NOT NULL,
+    resource        TEXT NOT NULL,
+    resource_id     TEXT,
+    org_id          UUID,
+    metadata        JSONB DEFAULT '{}'::jsonb NOT NULL,
+    ip_address      TEXT,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    attempts        INT DEFAULT 0 NOT NULL,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- The relay takes due rows in order
+CREATE INDEX IF NOT EXISTS idx_audit_outbox_due ON audit_outbox (next_attempt_at, seq);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS audit_checkpoints;
+DROP FUNCTION IF EXISTS audit_checkpoints_immutable();
+DROP TABLE IF EXISTS audit_chain_heads;
+
+DROP INDEX IF EXISTS idx_audit_events_chain;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS hash;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS prev_hash;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS chain_seq;
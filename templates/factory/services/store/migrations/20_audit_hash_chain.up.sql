@@ -0,0 +1,52 @@
+-- =============================================================================
+-- Migration 20: Hash-chained audit log
+-- Each org's audit events form a chain: chain_seq numbers them from 1 and
+-- hash = sha256(prev_hash + canonical event), computed by the backend relay
+-- as it moves events from the outbox. Rewriting or removing an event breaks
+-- the chain. Events without an org chain under the nil UUID. Events recorded
+-- before this migration are not chained.
+--
+-- Checkpoints sign a chain head with the JWT signing key, so a chain rebuilt
+-- from the database alone can't match them.
+-- =============================================================================
+
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS chain_seq BIGINT;
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS prev_hash TEXT;
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS hash TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_events_chain
+    ON audit_events ((COALESCE(org_id, '00000000-0000-0000-0000-000000000000'::uuid)), chain_seq)
+    WHERE chain_seq IS NOT NULL;
+
+-- Last event of each chain; the relay locks it to append
+CREATE TABLE IF NOT EXISTS "audit_chain_heads" (
+    org_id     UUID PRIMARY KEY,
+    chain_seq  BIGINT NOT NULL,
+    hash       TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "audit_checkpoints" (
+    id         UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    org_id     UUID NOT NULL,
+    chain_seq  BIGINT NOT NULL,
+    hash       TEXT NOT NULL,
+    -- JWT with org, seq and hash claims, verifiable with the JWKS
+    signature  TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (org_id, chain_seq)
+);
+
+CREATE OR REPLACE FUNCTION audit_checkpoints_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_checkpoints table is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_checkpoints_no_update
+    BEFORE UPDATE ON audit_checkpoints FOR EACH ROW
+    EXECUTE FUNCTION audit_checkpoints_immutable();
+
+CREATE TRIGGER audit_checkpoints_no_delete
+    BEFORE DELETE ON audit_checkpoints FOR EACH ROW
+    EXECUTE FUNCTION audit_checkpoints_immutable();
@@ -0,0 +1,4 @@
+-- Events are append-only: the ones with the new actor types stay, unchecked
+ALTER TABLE audit_events DROP CONSTRAINT IF EXISTS audit_events_actor_type_check;
+ALTER TABLE audit_events ADD CONSTRAINT audit_events_actor_type_check
+    CHECK (actor_type IN ('user', 'api_key', 'system')) NOT VALID;
@@ -0,0 +1,9 @@
+-- =============================================================================
+-- Migration 21: Audit actor types
+-- Events record who acted on the request: besides users, API keys and the
+-- system, service accounts and admins impersonating a user.
+-- =============================================================================
+
+ALTER TABLE audit_events DROP CONSTRAINT IF EXISTS audit_events_actor_type_check;
+ALTER TABLE audit_events ADD CONSTRAINT audit_events_actor_type_check
+    CHECK (actor_type IN ('user', 'api_key', 'service_account', 'impersonator', 'system'));
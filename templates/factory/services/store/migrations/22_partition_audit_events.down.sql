@@ -0,0 +1,62 @@
+-- Archived partitions are not restored
+DELETE FROM entitlement_overrides WHERE feature = 'audit_retention_days';
+DELETE FROM plan_entitlements WHERE feature = 'audit_retention_days';
+
+ALTER TABLE audit_events RENAME TO audit_events_partitioned;
+ALTER TABLE audit_events_partitioned DROP CONSTRAINT audit_events_pkey;
+DROP INDEX IF EXISTS idx_audit_events_org_time;
+DROP INDEX IF EXISTS idx_audit_events_actor;
+DROP INDEX IF EXISTS idx_audit_events_action;
+DROP INDEX IF EXISTS idx_audit_events_resource;
+DROP INDEX IF EXISTS idx_audit_events_metadata;
+DROP INDEX IF EXISTS idx_audit_events_created_at_brin;
+DROP INDEX IF EXISTS idx_audit_events_chain;
+
+CREATE TABLE "audit_events" (
+    id          UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    actor_id    UUID,
+    actor_type  TEXT NOT NULL DEFAULT 'user'
+        CONSTRAINT audit_events_actor_type_check
+        CHECK (actor_type IN ('user', 'api_key', 'service_account', 'impersonator', 'system')),
+    action      TEXT NOT NULL,
+    resource    TEXT NOT NULL,
+    resource_id TEXT,
+    org_id      UUID,
+    metadata    JSONB DEFAULT '{}'::jsonb,
+    ip_address  TEXT,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    chain_seq   BIGINT,
+    prev_hash   TEXT,
+    hash        TEXT
+);
+
+INSERT INTO audit_events (id, actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address,
+                          created_at, chain_seq, prev_hash, hash)
+SELECT id, actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address,
+       created_at, chain_seq, prev_hash, hash
+FROM audit_events_partitioned;
+
+DROP TABLE audit_events_partitioned;
+DROP FUNCTION IF EXISTS audit_events_create_partition(TIMESTAMP WITH TIME ZONE);
+DROP FUNCTION IF EXISTS audit_events_claim_chain_seq();
+DROP TABLE IF EXISTS audit_chain_seqs;
+
+CREATE TRIGGER audit_events_no_update
+    BEFORE UPDATE ON audit_events FOR EACH ROW
+    EXECUTE FUNCTION audit_events_immutable();
+
+CREATE TRIGGER audit_events_no_delete
+    BEFORE DELETE ON audit_events FOR EACH ROW
+    EXECUTE FUNCTION audit_events_immutable();
+
+CREATE INDEX idx_audit_events_org_time ON audit_events(org_id, created_at DESC);
+CREATE INDEX idx_audit_events_actor ON audit_events(actor_id);
+CREATE INDEX idx_audit_events_action ON audit_events(action);
+CREATE INDEX idx_audit_events_resource ON audit_events(resource, resource_id);
+CREATE INDEX idx_audit_events_metadata ON audit_events USING gin (metadata jsonb_path_ops);
+CREATE INDEX idx_audit_events_created_at_brin
+    ON audit_events USING brin (created_at)
+    WITH (pages_per_range = 32);
+CREATE UNIQUE INDEX idx_audit_events_chain
+    ON audit_events ((COALESCE(org_id, '00000000-0000-0000-0000-000000000000'::uuid)), chain_seq)
+    WHERE chain_seq IS NOT NULL;
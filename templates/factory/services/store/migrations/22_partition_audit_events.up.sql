@@ -0,0 +1,137 @@
+-- =============================================================================
+-- Migration 22: Monthly audit_events partitions, with retention
+-- audit_events becomes partitioned by month of created_at, as planned in
+-- migration 11. Partitions are named audit_events_YYYY_MM (months in UTC);
+-- audit_events_create_partition creates them, here up to 3 months ahead and
+-- then from the backend's audit maintenance job. An event without a
+-- partition stays in the outbox until its partition exists.
+--
+-- Retention works on whole partitions: once every org with events in a
+-- partition is past its audit_retention_days, the backend archives it to
+-- compressed NDJSON, detaches it and drops it. Rows are never deleted.
+-- Partitions are shared by all orgs, so audit_retention_days is a minimum:
+-- a month is kept as long as the longest retention of the orgs with events
+-- in it, and for ever while one of them keeps events for ever. Plans that
+-- must not keep events past their retention need partitions per retention
+-- class, which this migration does not do.
+--
+-- Unique indexes of a partitioned table must include created_at: the primary
+-- key becomes (id, created_at) and idx_audit_events_chain is no longer
+-- unique. Each chained event claims its (org, chain_seq) in audit_chain_seqs
+-- instead, in the transaction that inserts it, so a sequence number is never
+-- used twice in a chain, even across archived partitions.
+-- =============================================================================
+
+ALTER TABLE audit_events RENAME TO audit_events_unpartitioned;
+-- Free the index names for the partitioned table
+ALTER TABLE audit_events_unpartitioned DROP CONSTRAINT audit_events_pkey;
+DROP INDEX IF EXISTS idx_audit_events_org_time;
+DROP INDEX IF EXISTS idx_audit_events_actor;
+DROP INDEX IF EXISTS idx_audit_events_action;
+DROP INDEX IF EXISTS idx_audit_events_resource;
+DROP INDEX IF EXISTS idx_audit_events_metadata;
+DROP INDEX IF EXISTS idx_audit_events_created_at_brin;
+DROP INDEX IF EXISTS idx_audit_events_chain;
+
+CREATE TABLE "audit_events" (
+    id          UUID DEFAULT gen_random_uuid() NOT NULL,
+    actor_id    UUID,
+    actor_type  TEXT NOT NULL DEFAULT 'user'
+        CONSTRAINT audit_events_actor_type_check
+        CHECK (actor_type IN ('user', 'api_key', 'service_account', 'impersonator', 'system')),
+    action      TEXT NOT NULL,
+    resource    TEXT NOT NULL,
+    resource_id TEXT,
+    org_id      UUID,
+    metadata    JSONB DEFAULT '{}'::jsonb,
+    ip_address  TEXT,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    chain_seq   BIGINT,
+    prev_hash   TEXT,
+    hash        TEXT,
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+-- Creates the partition of the month of the given time (UTC), if missing,
+-- and returns its name
+CREATE OR REPLACE FUNCTION audit_events_create_partition(month TIMESTAMP WITH TIME ZONE) RETURNS TEXT AS $$
+DECLARE
+    start_at       TIMESTAMP := date_trunc('month', month AT TIME ZONE 'UTC');
+    partition_name TEXT := 'audit_events_' || to_char(start_at, 'YYYY_MM');
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF audit_events FOR VALUES FROM (%L) TO (%L)',
+        partition_name, start_at AT TIME ZONE 'UTC', (start_at + interval '1 month') AT TIME ZONE 'UTC');
+    RETURN partition_name;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Partitions for the existing events, and ahead
+SELECT audit_events_create_partition(month AT TIME ZONE 'UTC')
+FROM generate_series(
+    date_trunc('month', (SELECT COALESCE(min(created_at), now()) FROM audit_events_unpartitioned) AT TIME ZONE 'UTC'),
+    date_trunc('month', now() AT TIME ZONE 'UTC') + interval '3 months',
+    interval '1 month') AS month;
+
+-- Sequence numbers used by each chain; rows outlive archived partitions
+CREATE TABLE IF NOT EXISTS "audit_chain_seqs" (
+    org_id    UUID NOT NULL,
+    chain_seq BIGINT NOT NULL,
+    PRIMARY KEY (org_id, chain_seq)
+);
+
+CREATE OR REPLACE FUNCTION audit_events_claim_chain_seq() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO audit_chain_seqs (org_id, chain_seq)
+    VALUES (COALESCE(NEW.org_id, '00000000-0000-0000-0000-000000000000'::uuid), NEW.chain_seq);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Created before the copy, which claims the existing sequence numbers
+CREATE TRIGGER audit_events_claim_chain_seq
+    AFTER INSERT ON audit_events FOR EACH ROW
+    WHEN (NEW.chain_seq IS NOT NULL)
+    EXECUTE FUNCTION audit_events_claim_chain_seq();
+
+INSERT INTO audit_events (id, actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address,
+                          created_at, chain_seq, prev_hash, hash)
+SELECT id, actor_id, actor_type, action, resource, resource_id, org_id, metadata, ip_address,
+       created_at, chain_seq, prev_hash, hash
+FROM audit_events_unpartitioned;
+
+DROP TABLE audit_events_unpartitioned;
+
+-- Append-only enforcement, inherited by the partitions. Detaching and
+-- dropping a partition fire no row triggers.
+CREATE TRIGGER audit_events_no_update
+    BEFORE UPDATE ON audit_events FOR EACH ROW
+    EXECUTE FUNCTION audit_events_immutable();
+
+CREATE TRIGGER audit_events_no_delete
+    BEFORE DELETE ON audit_events FOR EACH ROW
+    EXECUTE FUNCTION audit_events_immutable();
+
+CREATE INDEX idx_audit_events_org_time ON audit_events(org_id, created_at DESC);
+CREATE INDEX idx_audit_events_actor ON audit_events(actor_id);
+CREATE INDEX idx_audit_events_action ON audit_events(action);
+CREATE INDEX idx_audit_events_resource ON audit_events(resource, resource_id);
+CREATE INDEX idx_audit_events_metadata ON audit_events USING gin (metadata jsonb_path_ops);
+CREATE INDEX idx_audit_events_created_at_brin
+    ON audit_events USING brin (created_at)
+    WITH (pages_per_range = 32);
+CREATE INDEX idx_audit_events_chain
+    ON audit_events ((COALESCE(org_id, '00000000-0000-0000-0000-000000000000'::uuid)), chain_seq)
+    WHERE chain_seq IS NOT NULL;
+
+-- Days events are kept at least before archival (see above). NULL = for
+-- ever; plans without it keep events for a year.
+INSERT INTO plan_entitlements (plan_id, feature, limit_value)
+SELECT p.id, e.feature, e.limit_value
+FROM plans p
+JOIN (VALUES
+    ('free',       'audit_retention_days', 90),
+    ('pro',        'audit_retention_days', 365),
+    ('enterprise', 'audit_retention_days', NULL)
+) AS e(plan_name, feature, limit_value) ON p.name = e.plan_name
+ON CONFLICT DO NOTHING;